@@ -0,0 +1,83 @@
+// Command calibrate fits confidence calibration curves from labelled samples
+// (JSON lines of tenant_id, confidence and helpful/escalated) and writes a
+// profile for the server's CALIBRATION_FILE.
+//
+//	go run ./cmd/calibrate -in calibration_samples.jsonl -out calibration.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/calibration"
+)
+
+func main() {
+	in := flag.String("in", "calibration_samples.jsonl", "labelled samples (JSON lines)")
+	out := flag.String("out", "calibration.json", "profile to write")
+	method := flag.String("method", string(calibration.MethodIsotonic), "isotonic or platt")
+	minSamples := flag.Int("min-samples", 50, "samples a tenant needs for its own curve")
+	flag.Parse()
+
+	f, err := os.Open(*in)
+	if err != nil {
+		log.Fatalf("failed to open samples: %v", err)
+	}
+	samples, err := calibration.ReadSamples(f)
+	f.Close()
+	if err != nil {
+		log.Fatalf("failed to read samples from %s: %v", *in, err)
+	}
+	if len(samples) < *minSamples {
+		log.Fatalf("%d samples in %s; need at least %d", len(samples), *in, *minSamples)
+	}
+
+	byTenant := make(map[string][]calibration.Sample)
+	for _, s := range samples {
+		byTenant[s.TenantID] = append(byTenant[s.TenantID], s)
+	}
+
+	profile := &calibration.Profile{
+		CreatedAt: time.Now().UTC(),
+		Tenants:   make(map[string]*calibration.Curve),
+	}
+	// Tenants with too few samples share the curve fitted on all samples
+	profile.Default, err = calibration.Fit(samples, calibration.Method(*method))
+	if err != nil {
+		log.Fatalf("failed to fit default curve: %v", err)
+	}
+	report("(default)", samples, profile.Default)
+
+	tenantIDs := make([]string, 0, len(byTenant))
+	for id := range byTenant {
+		tenantIDs = append(tenantIDs, id)
+	}
+	sort.Strings(tenantIDs)
+	for _, id := range tenantIDs {
+		tenantSamples := byTenant[id]
+		if id == "" || len(tenantSamples) < *minSamples {
+			continue
+		}
+		curve, err := calibration.Fit(tenantSamples, calibration.Method(*method))
+		if err != nil {
+			log.Fatalf("failed to fit curve for tenant %s: %v", id, err)
+		}
+		profile.Tenants[id] = curve
+		report(id, tenantSamples, curve)
+	}
+
+	if err := profile.Save(*out); err != nil {
+		log.Fatalf("failed to write profile: %v", err)
+	}
+	fmt.Printf("wrote %s (%d tenant curves)\n", *out, len(profile.Tenants))
+}
+
+// report prints the Brier score before and after calibration.
+func report(name string, samples []calibration.Sample, curve *calibration.Curve) {
+	fmt.Printf("%-24s samples=%-6d brier_raw=%.4f brier_calibrated=%.4f\n",
+		name, len(samples), calibration.Brier(samples, nil), calibration.Brier(samples, curve))
+}
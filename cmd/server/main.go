@@ -6,21 +6,43 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/RyoKusnadi/tier1-support-ai/internal/auth"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/calibration"
 	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/experiment"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/guard"
 	"github.com/RyoKusnadi/tier1-support-ai/internal/handler"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/intent"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/jobs"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/knowledge"
 	"github.com/RyoKusnadi/tier1-support-ai/internal/llm"
 	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
 	"github.com/RyoKusnadi/tier1-support-ai/internal/middleware"
 	"github.com/RyoKusnadi/tier1-support-ai/internal/observability"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/pii"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/prompts"
 	"github.com/RyoKusnadi/tier1-support-ai/internal/reliability"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/shadow"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/tenant"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/tools"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/translate"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("failed to load configuration: %v", err)
+	}
+
+	// Strip personal data from every log line
+	if cfg.PIIRedaction {
+		logger.SetRedactor(pii.New(pii.AllKinds).RedactString)
+	}
 
 	// Initialize LLM client
 	llmConfig := llm.Config{
@@ -33,6 +55,8 @@ func main() {
 		Timeout:      cfg.LLMTimeout,
 		MaxRetries:   cfg.LLMMaxRetries,
 		RetryDelay:   cfg.LLMRetryDelay,
+
+		ConfidenceStrategy: llm.ConfidenceStrategy(cfg.LLMConfidenceStrategy),
 	}
 
 	llmClient, err := llm.NewClient(llmConfig)
@@ -40,18 +64,179 @@ func main() {
 		log.Fatalf("failed to initialize LLM client: %v", err)
 	}
 
+	// Groundedness scoring; the LLM judge only runs when the lexical check is inconclusive
+	var grounding *llm.GroundingScorer
+	if cfg.GroundingWeight > 0 {
+		grounding = llm.NewGroundingScorer()
+		if cfg.GroundingJudge {
+			grounding.WithJudge(llmClient, cfg.GroundingJudgeModel)
+		}
+	}
+
+	// Optional shadow client: same provider settings unless overridden
+	var shadowRunner *shadow.Runner
+	if cfg.ShadowModel != "" {
+		shadowConfig := llmConfig
+		shadowConfig.DefaultModel = cfg.ShadowModel
+		if cfg.ShadowLLMBaseURL != "" {
+			shadowConfig.BaseURL = cfg.ShadowLLMBaseURL
+		}
+		if cfg.ShadowLLMAPIKey != "" {
+			shadowConfig.APIKey = cfg.ShadowLLMAPIKey
+		}
+		shadowClient, err := llm.NewClient(shadowConfig)
+		if err != nil {
+			log.Fatalf("failed to initialize shadow LLM client: %v", err)
+		}
+		shadowRunner = shadow.NewRunner(shadowClient, shadow.Config{
+			Model:          cfg.ShadowModel,
+			SampleRate:     cfg.ShadowSampleRate,
+			MaxConcurrency: cfg.ShadowMaxConcurrency,
+			MaxResults:     cfg.ShadowMaxResults,
+			Timeout:        time.Duration(cfg.LLMTimeout) * time.Second,
+
+			Grounding:       grounding,
+			GroundingWeight: cfg.GroundingWeight,
+		})
+		logger.Info("shadow mode enabled", map[string]interface{}{
+			"shadow_model": cfg.ShadowModel,
+			"sample_rate":  cfg.ShadowSampleRate,
+		})
+	}
+
 	// Initialize reliability & cost-control primitives (Phase 5)
 	rateLimiter := reliability.NewTenantRateLimiter(cfg.TenantRateLimitPerSec, cfg.TenantRateLimitBurst)
 	responseCache := reliability.NewResponseCache[handler.SupportQueryResponse](time.Duration(cfg.ResponseCacheTTLSeconds) * time.Second)
 	tokenUsageTracker := reliability.NewTokenUsageTracker(time.Duration(cfg.TokenUsageWindowHours) * time.Hour)
-	var budgetGuard *reliability.BudgetGuard
-	if cfg.TenantTokenBudget > 0 {
-		budgetGuard = reliability.NewBudgetGuard(tokenUsageTracker, cfg.TenantTokenBudget)
+	// Always constructed: tenants can enable a budget even when the global one is 0
+	budgetGuard := reliability.NewBudgetGuard(tokenUsageTracker, cfg.TenantTokenBudget)
+
+	// Initialize tenant registry: tenants from the config file, or the static
+	// seed list when the file declares none; optionally persisted
+	tenantRegistry := tenant.NewRegistry(config.SupportedLanguages)
+	if len(cfg.Tenants) == 0 {
+		tenantRegistry = tenant.NewRegistryFromConfig(config.Tenants, config.SupportedLanguages)
+	}
+	if cfg.TenantRegistryFile != "" {
+		if err := tenantRegistry.LoadFile(cfg.TenantRegistryFile); err != nil {
+			log.Fatalf("failed to load tenant registry: %v", err)
+		}
+	}
+	if err := tenantRegistry.Reconfigure(cfg.DefaultTenantSettings(), cfg.Tenants); err != nil {
+		log.Fatalf("failed to configure tenants: %v", err)
+	}
+
+	// Initialize API key store, optionally persisted; the bootstrap admin key
+	// can issue tenant keys and is never written to the store file
+	keyStore := auth.NewKeyStore()
+	if cfg.APIKeyStoreFile != "" {
+		if err := keyStore.LoadFile(cfg.APIKeyStoreFile); err != nil {
+			log.Fatalf("failed to load API keys: %v", err)
+		}
+	}
+	if cfg.AdminAPIKey != "" {
+		if _, err := keyStore.Bootstrap("", "bootstrap-admin", cfg.AdminAPIKey, []auth.Scope{auth.ScopeAdmin}); err != nil {
+			log.Fatalf("failed to register admin API key: %v", err)
+		}
+	}
+	jwtVerifier, err := newJWTVerifier(cfg)
+	if err != nil {
+		log.Fatalf("failed to initialize JWT verifier: %v", err)
+	}
+	if !cfg.AuthEnabled {
+		logger.Info("authentication disabled; do not run like this in production", nil)
 	}
 
 	// Initialize handlers
 	metrics := observability.New()
-	supportHandler := handler.NewSupportHandler(llmClient, rateLimiter, responseCache, tokenUsageTracker, budgetGuard, metrics)
+	retriever := knowledge.NewInMemoryRetriever()
+	promptStore := prompts.NewStore()
+	if cfg.PromptStoreFile != "" {
+		if err := promptStore.LoadFile(cfg.PromptStoreFile); err != nil {
+			log.Fatalf("failed to load prompt store: %v", err)
+		}
+	}
+	experimentStore := experiment.NewStore()
+	experimentStore.SetPrices(cfg.LLMModelPrices)
+	supportOpts := []handler.SupportHandlerOption{
+		handler.WithTenantRegistry(tenantRegistry),
+		handler.WithRetriever(retriever),
+		handler.WithPromptStore(promptStore),
+		handler.WithExperiments(experimentStore),
+		handler.WithLanguageDetection(cfg.LanguageDetectMinConfidence),
+		// Only used for tenants whose profile configures translations
+		handler.WithTranslator(translate.NewLLMTranslator(llmClient, cfg.TranslationModel)),
+		handler.WithBatchLimits(cfg.BatchMaxItems, cfg.BatchConcurrency, time.Duration(cfg.BatchRateWaitMs)*time.Millisecond),
+	}
+	if cfg.IdempotencyWindowSeconds > 0 {
+		idempotency := reliability.NewIdempotencyStore[handler.SupportQueryResponse](time.Duration(cfg.IdempotencyWindowSeconds) * time.Second)
+		supportOpts = append(supportOpts, handler.WithIdempotency(idempotency))
+	}
+	if shadowRunner != nil {
+		supportOpts = append(supportOpts, handler.WithShadow(shadowRunner))
+	}
+	if cfg.GuardEnabled {
+		var classifier guard.Classifier
+		if cfg.GuardLLMClassifier {
+			classifier = guard.NewLLMClassifier(llmClient, cfg.GuardClassifierModel)
+		}
+		supportOpts = append(supportOpts, handler.WithInputGuard(guard.NewInputGuard(classifier), guard.Mode(cfg.GuardMode)))
+	}
+	if cfg.IntentEnabled {
+		var classifier intent.Classifier
+		if cfg.IntentLLMClassifier {
+			classifier = intent.NewLLMClassifier(llmClient, cfg.IntentClassifierModel)
+		}
+		supportOpts = append(supportOpts, handler.WithIntentClassifier(intent.New(classifier)))
+	}
+	// Tools only run for tenants whose profile configures them
+	toolRegistry := tools.NewRegistry(time.Duration(cfg.ToolTimeoutMs)*time.Millisecond, cfg.ToolAllowedHosts)
+	supportOpts = append(supportOpts, handler.WithTools(toolRegistry, cfg.ToolMaxIterations))
+	if cfg.LLMStructuredOutput {
+		supportOpts = append(supportOpts, handler.WithStructuredOutput())
+	}
+	if grounding != nil {
+		supportOpts = append(supportOpts, handler.WithGrounding(grounding, cfg.GroundingWeight))
+	}
+	if cfg.CalibrationFile != "" {
+		profile, err := calibration.LoadProfile(cfg.CalibrationFile)
+		if err != nil {
+			log.Fatalf("failed to load calibration profile: %v", err)
+		}
+		supportOpts = append(supportOpts, handler.WithCalibration(profile))
+		logger.Info("confidence calibration enabled", map[string]interface{}{
+			"file":    cfg.CalibrationFile,
+			"tenants": len(profile.Tenants),
+		})
+	}
+	if cfg.CalibrationSamplesFile != "" {
+		supportOpts = append(supportOpts, handler.WithCalibrationSamples(calibration.NewRecorder(cfg.CalibrationSamplesFile)))
+	}
+	if cfg.OutputGuardEnabled {
+		supportOpts = append(supportOpts, handler.WithOutputGuard(guard.NewOutputGuard()))
+	}
+	supportHandler := handler.NewSupportHandler(llmClient, rateLimiter, responseCache, tokenUsageTracker, budgetGuard, metrics, supportOpts...)
+	apiKeyHandler := handler.NewAPIKeyHandler(keyStore, tenantRegistry)
+	tenantHandler := handler.NewTenantHandler(tenantRegistry)
+	promptHandler := handler.NewPromptHandler(promptStore, tenantRegistry, supportHandler)
+	experimentHandler := handler.NewExperimentHandler(experimentStore, tenantRegistry, promptStore)
+	shadowHandler := handler.NewShadowHandler(shadowRunner)
+
+	// Asynchronous jobs run the same pipeline on a worker pool; queued jobs
+	// survive restarts when JOBS_FILE is set
+	jobNotifier := jobs.NewNotifier(time.Duration(cfg.JobsCallbackTimeoutMs)*time.Millisecond, cfg.JobsCallbackSecret,
+		cfg.JobsCallbackAllowedHosts, cfg.JobsCallbackMaxAttempts)
+	jobQueue := jobs.NewQueue(supportHandler.RunJob, jobNotifier, jobs.Config{
+		Workers:     cfg.JobsWorkers,
+		QueueSize:   cfg.JobsQueueSize,
+		MaxAttempts: cfg.JobsMaxAttempts,
+		Retention:   time.Duration(cfg.JobsRetentionHours) * time.Hour,
+		Path:        cfg.JobsFile,
+	})
+	if err := jobQueue.Load(); err != nil {
+		log.Fatalf("failed to load jobs: %v", err)
+	}
+	jobHandler := handler.NewJobHandler(supportHandler, jobQueue, jobNotifier, metrics)
 
 	router := gin.New()
 	router.Use(gin.Recovery())
@@ -63,10 +248,44 @@ func main() {
 
 	// Register support query endpoint
 	v1 := router.Group("/v1")
+	v1.Use(middleware.Authenticate(keyStore, jwtVerifier, cfg.AuthEnabled))
 	{
 		support := v1.Group("/support")
 		{
-			support.POST("/query", supportHandler.SupportQuery)
+			support.POST("/query", middleware.RequireScope(auth.ScopeQuery), supportHandler.SupportQuery)
+			support.POST("/query:method", middleware.RequireScope(auth.ScopeQuery), supportHandler.QueryMethod) // query:batch
+			support.POST("/feedback", middleware.RequireScope(auth.ScopeQuery), supportHandler.Feedback)
+			support.POST("/jobs", middleware.RequireScope(auth.ScopeQuery), jobHandler.Submit)
+			support.GET("/jobs/:id", middleware.RequireScope(auth.ScopeQuery), jobHandler.Get)
+		}
+
+		admin := v1.Group("/admin", middleware.RequireScope(auth.ScopeAdmin))
+		{
+			admin.POST("/keys", apiKeyHandler.Issue)
+			admin.GET("/keys", apiKeyHandler.List)
+			admin.POST("/keys/:id/rotate", apiKeyHandler.Rotate)
+			admin.DELETE("/keys/:id", apiKeyHandler.Revoke)
+
+			admin.POST("/tenants", tenantHandler.Create)
+			admin.GET("/tenants", tenantHandler.List)
+			admin.GET("/tenants/:id", tenantHandler.Get)
+			admin.GET("/tenants/:id/config", tenantHandler.GetConfig)
+			admin.PUT("/tenants/:id/config", tenantHandler.UpdateConfig)
+			admin.POST("/tenants/:id/enable", tenantHandler.Enable)
+			admin.POST("/tenants/:id/disable", tenantHandler.Disable)
+
+			admin.POST("/tenants/:id/prompts", promptHandler.Create)
+			admin.GET("/tenants/:id/prompts", promptHandler.List)
+			admin.POST("/tenants/:id/prompts/preview", promptHandler.Preview)
+			admin.POST("/tenants/:id/prompts/:version/activate", promptHandler.Activate)
+
+			admin.POST("/experiments", experimentHandler.Create)
+			admin.GET("/experiments", experimentHandler.List)
+			admin.GET("/experiments/:id", experimentHandler.Get)
+			admin.GET("/experiments/:id/report", experimentHandler.Report)
+			admin.POST("/experiments/:id/stop", experimentHandler.Stop)
+
+			admin.GET("/shadow", shadowHandler.List)
 		}
 	}
 
@@ -75,8 +294,38 @@ func main() {
 		Handler: router,
 	}
 
+	// Hot reload: rate limits, tenant settings and prompts are swapped atomically
+	// on SIGHUP or when the config file changes; in-flight requests keep the
+	// settings they already resolved.
+	reloadCtx, stopReload := context.WithCancel(context.Background())
+	defer stopReload()
+	if cfg.File != "" {
+		hup := make(chan os.Signal, 1)
+		signal.Notify(hup, syscall.SIGHUP)
+		// Compare against the last applied file so a pending restart is logged once
+		applied := cfg
+		reloader := config.NewReloader(cfg.File, time.Duration(cfg.ConfigReloadSeconds)*time.Second, hup, func(next config.Config) error {
+			if err := tenantRegistry.Reconfigure(next.DefaultTenantSettings(), next.Tenants); err != nil {
+				return err
+			}
+			experimentStore.SetPrices(next.LLMModelPrices)
+			if fields := config.RestartRequired(applied, next); len(fields) > 0 {
+				logger.Info("config changes require a restart to take effect", map[string]interface{}{
+					"fields": strings.Join(fields, ","),
+				})
+			}
+			applied = next
+			return nil
+		})
+		go reloader.Run(reloadCtx)
+	}
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobQueue.Start(jobsCtx)
+
 	go func() {
-		logger.Info("server starting on :8080", nil)
+		logger.Info("server starting on :"+cfg.Port, nil)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("listen error: %v", err)
 		}
@@ -94,6 +343,41 @@ func main() {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatalf("server forced to shutdown: %v", err)
 	}
+	if shadowRunner != nil {
+		shadowRunner.Wait()
+	}
+	// Running jobs are abandoned and run again on the next start
+	stopJobs()
+	jobQueue.Wait()
 
 	log.Println("server exited properly")
 }
+
+// newJWTVerifier returns nil when neither a JWKS file nor URL is configured.
+func newJWTVerifier(cfg config.Config) (*auth.JWTVerifier, error) {
+	var (
+		keys *auth.KeySet
+		err  error
+	)
+	switch {
+	case cfg.JWTJWKSFile != "":
+		keys, err = auth.LoadJWKSFile(cfg.JWTJWKSFile)
+	case cfg.JWTJWKSURL != "":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		keys, err = auth.FetchJWKS(ctx, cfg.JWTJWKSURL)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.NewJWTVerifier(keys, auth.JWTConfig{
+		Issuer:        cfg.JWTIssuer,
+		Audience:      cfg.JWTAudience,
+		TenantClaim:   cfg.JWTTenantClaim,
+		CustomerClaim: cfg.JWTCustomerClaim,
+		RolesClaim:    cfg.JWTRolesClaim,
+	}), nil
+}
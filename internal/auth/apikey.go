@@ -0,0 +1,386 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyPrefix is prepended to every issued API key so leaked keys are easy to spot.
+const KeyPrefix = "t1_"
+
+var (
+	ErrKeyNotFound = errors.New("api key not found")
+	ErrKeyRevoked  = errors.New("api key revoked")
+	ErrInvalidKey  = errors.New("invalid api key")
+	ErrKeyExists   = errors.New("api key already registered")
+)
+
+// APIKey is the stored form of an API key. Only the SHA-256 hash of the
+// secret is kept; the plaintext is returned once at issue/rotate time.
+type APIKey struct {
+	ID         string     `json:"id"`
+	TenantID   string     `json:"tenant_id"`
+	Name       string     `json:"name,omitempty"`
+	Scopes     []Scope    `json:"scopes"`
+	Hash       string     `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	RotatedTo  string     `json:"rotated_to,omitempty"`
+
+	bootstrap bool // Registered from configuration on every start; never persisted
+}
+
+// Revoked reports whether the key has been revoked.
+func (k APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// storedKey is the on-disk form of an API key; unlike the API view it keeps the hash.
+type storedKey struct {
+	APIKey
+	Hash string `json:"hash"`
+}
+
+// KeyStore is a store of hashed API keys. It is safe for concurrent use.
+// When a storage path is set, every change is written to it as a JSON
+// snapshot; can be swapped for a database later.
+type KeyStore struct {
+	mu     sync.RWMutex
+	byID   map[string]*APIKey
+	byHash map[string]*APIKey
+	path   string
+	now    func() time.Time
+}
+
+func NewKeyStore() *KeyStore {
+	return &KeyStore{
+		byID:   map[string]*APIKey{},
+		byHash: map[string]*APIKey{},
+		now:    time.Now,
+	}
+}
+
+// LoadFile replaces the store contents with a snapshot previously written by
+// the store and persists subsequent changes to the same path. A missing file
+// starts an empty store. Last-used times are saved with the next change.
+func (s *KeyStore) LoadFile(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s.saveLocked()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read api keys: %w", err)
+	}
+
+	var snapshot []storedKey
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse api keys: %w", err)
+	}
+	byID := make(map[string]*APIKey, len(snapshot))
+	byHash := make(map[string]*APIKey, len(snapshot))
+	for _, stored := range snapshot {
+		key := stored.APIKey
+		key.Hash = stored.Hash
+		if key.ID == "" || key.Hash == "" {
+			return fmt.Errorf("invalid api key record %q", key.ID)
+		}
+		byID[key.ID] = &key
+		byHash[key.Hash] = &key
+	}
+	s.byID, s.byHash = byID, byHash
+	return nil
+}
+
+// HashKey returns the at-rest representation of a plaintext key.
+func HashKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// Issue creates a new key for tenantID and returns the stored key plus its plaintext.
+func (s *KeyStore) Issue(tenantID, name string, scopes []Scope) (APIKey, string, error) {
+	plaintext, err := newSecret()
+	if err != nil {
+		return APIKey{}, "", err
+	}
+	key, err := s.Import(tenantID, name, plaintext, scopes)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+	return key, plaintext, nil
+}
+
+// Import registers an externally provisioned plaintext key (e.g. a bootstrap
+// admin key from the environment). The plaintext itself is not retained.
+func (s *KeyStore) Import(tenantID, name, plaintext string, scopes []Scope) (APIKey, error) {
+	key, err := s.newKey(tenantID, name, plaintext, scopes)
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.addLocked(key); err != nil {
+		return APIKey{}, err
+	}
+	if err := s.saveLocked(); err != nil {
+		s.removeLocked(key)
+		return APIKey{}, err
+	}
+	return *key, nil
+}
+
+// Bootstrap registers a key from configuration, such as ADMIN_API_KEY. It is
+// kept in memory only, so changing or removing it takes effect on restart.
+func (s *KeyStore) Bootstrap(tenantID, name, plaintext string, scopes []Scope) (APIKey, error) {
+	key, err := s.newKey(tenantID, name, plaintext, scopes)
+	if err != nil {
+		return APIKey{}, err
+	}
+	key.bootstrap = true
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.addLocked(key); err != nil {
+		return APIKey{}, err
+	}
+	return *key, nil
+}
+
+// newKey validates and builds a key record; it does not touch the store.
+func (s *KeyStore) newKey(tenantID, name, plaintext string, scopes []Scope) (*APIKey, error) {
+	if plaintext == "" {
+		return nil, ErrInvalidKey
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	for _, sc := range scopes {
+		if !ValidScope(sc) {
+			return nil, errors.New("unknown scope: " + string(sc))
+		}
+	}
+
+	id, err := newKeyID()
+	if err != nil {
+		return nil, err
+	}
+	return &APIKey{
+		ID:        id,
+		TenantID:  tenantID,
+		Name:      name,
+		Scopes:    append([]Scope(nil), scopes...),
+		Hash:      HashKey(plaintext),
+		CreatedAt: s.now(),
+	}, nil
+}
+
+func (s *KeyStore) addLocked(key *APIKey) error {
+	if _, exists := s.byHash[key.Hash]; exists {
+		return ErrKeyExists
+	}
+	s.byID[key.ID] = key
+	s.byHash[key.Hash] = key
+	return nil
+}
+
+func (s *KeyStore) removeLocked(key *APIKey) {
+	delete(s.byID, key.ID)
+	delete(s.byHash, key.Hash)
+}
+
+// Authenticate resolves a plaintext key to its stored record.
+func (s *KeyStore) Authenticate(plaintext string) (APIKey, error) {
+	if plaintext == "" {
+		return APIKey{}, ErrInvalidKey
+	}
+	hash := HashKey(plaintext)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.byHash[hash]
+	if !ok {
+		return APIKey{}, ErrInvalidKey
+	}
+	if key.Revoked() {
+		return APIKey{}, ErrKeyRevoked
+	}
+	now := s.now()
+	key.LastUsedAt = &now
+	return *key, nil
+}
+
+// Get returns the key with the given ID.
+func (s *KeyStore) Get(id string) (APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.byID[id]
+	if !ok {
+		return APIKey{}, ErrKeyNotFound
+	}
+	return *key, nil
+}
+
+// List returns all keys for tenantID, or every key when tenantID is empty.
+func (s *KeyStore) List(tenantID string) []APIKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]APIKey, 0, len(s.byID))
+	for _, key := range s.byID {
+		if tenantID != "" && key.TenantID != tenantID {
+			continue
+		}
+		out = append(out, *key)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// Revoke marks the key as revoked. Revoking an already revoked key is a no-op.
+func (s *KeyStore) Revoke(id string) (APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.byID[id]
+	if !ok {
+		return APIKey{}, ErrKeyNotFound
+	}
+	if !key.Revoked() {
+		now := s.now()
+		key.RevokedAt = &now
+		if err := s.saveLocked(); err != nil {
+			key.RevokedAt = nil
+			return APIKey{}, err
+		}
+	}
+	return *key, nil
+}
+
+// Rotate issues a replacement key with the same tenant, name and scopes and
+// revokes the old one. It returns the new key and its plaintext. Both happen
+// under one lock, so concurrent rotations of a key leave one live replacement.
+func (s *KeyStore) Rotate(id string) (APIKey, string, error) {
+	plaintext, err := newSecret()
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.byID[id]
+	if !ok {
+		return APIKey{}, "", ErrKeyNotFound
+	}
+	if old.Revoked() {
+		return APIKey{}, "", ErrKeyRevoked
+	}
+	newKey, err := s.newKey(old.TenantID, old.Name, plaintext, old.Scopes)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+	if err := s.addLocked(newKey); err != nil {
+		return APIKey{}, "", err
+	}
+	now := s.now()
+	old.RevokedAt = &now
+	old.RotatedTo = newKey.ID
+	if err := s.saveLocked(); err != nil {
+		old.RevokedAt, old.RotatedTo = nil, ""
+		s.removeLocked(newKey)
+		return APIKey{}, "", err
+	}
+	return *newKey, plaintext, nil
+}
+
+// saveLocked writes the snapshot atomically (temp file + rename).
+func (s *KeyStore) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+	snapshot := make([]storedKey, 0, len(s.byID))
+	for _, key := range s.byID {
+		if key.bootstrap {
+			continue
+		}
+		snapshot = append(snapshot, storedKey{APIKey: *key, Hash: key.Hash})
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].ID < snapshot[j].ID })
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode api keys: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".apikeys-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to persist api keys: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to persist api keys: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to persist api keys: %w", err)
+	}
+	// Key hashes are credentials; keep the file private
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to persist api keys: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to persist api keys: %w", err)
+	}
+	return nil
+}
+
+// ParseScopes converts raw scope strings, rejecting unknown values.
+func ParseScopes(raw []string) ([]Scope, error) {
+	scopes := make([]Scope, 0, len(raw))
+	for _, r := range raw {
+		sc := Scope(strings.TrimSpace(r))
+		if sc == "" {
+			continue
+		}
+		if !ValidScope(sc) {
+			return nil, errors.New("unknown scope: " + string(sc))
+		}
+		scopes = append(scopes, sc)
+	}
+	return scopes, nil
+}
+
+func newSecret() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return KeyPrefix + hex.EncodeToString(b[:]), nil
+}
+
+func newKeyID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return "key_" + hex.EncodeToString(b[:]), nil
+}
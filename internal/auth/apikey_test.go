@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestKeyStore_IssueAndAuthenticate(t *testing.T) {
+	store := NewKeyStore()
+
+	key, plaintext, err := store.Issue("shop-123", "widget", []Scope{ScopeQuery})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if !strings.HasPrefix(plaintext, KeyPrefix) {
+		t.Errorf("Issue() plaintext = %q, want prefix %q", plaintext, KeyPrefix)
+	}
+	if key.Hash == plaintext || key.Hash != HashKey(plaintext) {
+		t.Errorf("Issue() stored hash does not match hashed plaintext")
+	}
+
+	got, err := store.Authenticate(plaintext)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if got.TenantID != "shop-123" {
+		t.Errorf("Authenticate() TenantID = %s, want shop-123", got.TenantID)
+	}
+	if got.LastUsedAt == nil {
+		t.Error("Authenticate() LastUsedAt = nil, want set")
+	}
+
+	if _, err := store.Authenticate("t1_unknown"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Authenticate(unknown) error = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestKeyStore_IssueRejectsUnknownScope(t *testing.T) {
+	store := NewKeyStore()
+	if _, _, err := store.Issue("shop-123", "", []Scope{"superuser"}); err == nil {
+		t.Error("Issue() error = nil, want error for unknown scope")
+	}
+	if _, _, err := store.Issue("shop-123", "", nil); err == nil {
+		t.Error("Issue() error = nil, want error for empty scopes")
+	}
+}
+
+func TestKeyStore_RotateAndRevoke(t *testing.T) {
+	store := NewKeyStore()
+	old, oldPlain, err := store.Issue("shop-123", "backend", []Scope{ScopeQuery, ScopeKBWrite})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	rotated, newPlain, err := store.Rotate(old.ID)
+	if err != nil {
+		t.Fatalf("Rotate() error = %v", err)
+	}
+	if rotated.TenantID != old.TenantID || len(rotated.Scopes) != len(old.Scopes) {
+		t.Errorf("Rotate() did not preserve tenant and scopes")
+	}
+	if _, err := store.Authenticate(oldPlain); !errors.Is(err, ErrKeyRevoked) {
+		t.Errorf("Authenticate(old) error = %v, want ErrKeyRevoked", err)
+	}
+	if _, err := store.Authenticate(newPlain); err != nil {
+		t.Errorf("Authenticate(new) error = %v, want nil", err)
+	}
+	if _, _, err := store.Rotate(old.ID); !errors.Is(err, ErrKeyRevoked) {
+		t.Errorf("Rotate(revoked) error = %v, want ErrKeyRevoked", err)
+	}
+
+	if _, err := store.Revoke(rotated.ID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, err := store.Authenticate(newPlain); !errors.Is(err, ErrKeyRevoked) {
+		t.Errorf("Authenticate(revoked) error = %v, want ErrKeyRevoked", err)
+	}
+	if _, err := store.Revoke("key_missing"); !errors.Is(err, ErrKeyNotFound) {
+		t.Errorf("Revoke(missing) error = %v, want ErrKeyNotFound", err)
+	}
+}
+
+func TestIdentity_CanAccessTenant(t *testing.T) {
+	tests := []struct {
+		name     string
+		identity *Identity
+		tenantID string
+		want     bool
+	}{
+		{name: "own tenant", identity: &Identity{TenantID: "shop-123"}, tenantID: "shop-123", want: true},
+		{name: "other tenant", identity: &Identity{TenantID: "shop-123"}, tenantID: "shop-456", want: false},
+		{name: "platform identity", identity: &Identity{}, tenantID: "shop-456", want: true},
+		{name: "nil identity", identity: nil, tenantID: "shop-123", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.identity.CanAccessTenant(tt.tenantID); got != tt.want {
+				t.Errorf("CanAccessTenant() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyStore_LoadFilePersistsKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	store := NewKeyStore()
+	if err := store.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if _, err := store.Bootstrap("", "bootstrap-admin", "t1_admin", []Scope{ScopeAdmin}); err != nil {
+		t.Fatalf("Bootstrap() error = %v", err)
+	}
+	kept, keptPlain, err := store.Issue("shop-123", "widget", []Scope{ScopeQuery})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	revoked, revokedPlain, _ := store.Issue("shop-123", "old", []Scope{ScopeQuery})
+	if _, err := store.Revoke(revoked.ID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if strings.Contains(string(data), keptPlain) {
+		t.Error("snapshot contains a plaintext key")
+	}
+
+	restarted := NewKeyStore()
+	if err := restarted.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if got, err := restarted.Authenticate(keptPlain); err != nil || got.ID != kept.ID {
+		t.Errorf("Authenticate(kept) = %v, %v; want %s", got.ID, err, kept.ID)
+	}
+	if _, err := restarted.Authenticate(revokedPlain); !errors.Is(err, ErrKeyRevoked) {
+		t.Errorf("Authenticate(revoked) error = %v, want ErrKeyRevoked", err)
+	}
+	if _, err := restarted.Authenticate("t1_admin"); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Authenticate(bootstrap) error = %v, want ErrInvalidKey: bootstrap keys are not persisted", err)
+	}
+}
+
+func TestKeyStore_ConcurrentRotateLeavesOneLiveKey(t *testing.T) {
+	store := NewKeyStore()
+	old, _, err := store.Issue("shop-123", "backend", []Scope{ScopeQuery})
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store.Rotate(old.ID)
+		}()
+	}
+	wg.Wait()
+
+	live := 0
+	for _, key := range store.List("shop-123") {
+		if !key.Revoked() {
+			live++
+		}
+	}
+	if live != 1 {
+		t.Errorf("live keys after concurrent rotations = %d, want 1", live)
+	}
+}
@@ -0,0 +1,70 @@
+package auth
+
+// Scope is a permission granted to an authenticated caller.
+type Scope string
+
+const (
+	ScopeQuery   Scope = "query"
+	ScopeAdmin   Scope = "admin"
+	ScopeKBWrite Scope = "kb:write"
+)
+
+// ValidScope reports whether s is a known scope.
+func ValidScope(s Scope) bool {
+	switch s {
+	case ScopeQuery, ScopeAdmin, ScopeKBWrite:
+		return true
+	}
+	return false
+}
+
+// Identity is the authenticated caller attached to a request.
+// An empty TenantID means a platform-level identity that is not bound to a tenant.
+type Identity struct {
+	Method   string // "api_key" or "jwt"
+	KeyID    string // Set for API key callers
+	TenantID string
+	Scopes   []Scope
+
+	// End-user details, set for JWT callers
+	CustomerID   string
+	CustomerName string
+	Roles        []string
+}
+
+// Subject returns a stable identifier for audit logs.
+func (i *Identity) Subject() string {
+	if i == nil {
+		return "anonymous"
+	}
+	if i.CustomerID != "" {
+		return i.Method + ":" + i.CustomerID
+	}
+	return i.Method + ":" + i.KeyID
+}
+
+// HasScope reports whether the identity was granted the given scope.
+func (i *Identity) HasScope(s Scope) bool {
+	if i == nil {
+		return false
+	}
+	for _, granted := range i.Scopes {
+		if granted == s {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPlatform reports whether the identity is not bound to a single tenant.
+func (i *Identity) IsPlatform() bool {
+	return i != nil && i.TenantID == ""
+}
+
+// CanAccessTenant reports whether the identity may act on behalf of tenantID.
+func (i *Identity) CanAccessTenant(tenantID string) bool {
+	if i == nil {
+		return false
+	}
+	return i.IsPlatform() || i.TenantID == tenantID
+}
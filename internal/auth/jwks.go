@@ -0,0 +1,257 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// jwk is a single JSON Web Key as published in a JWKS document.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+
+	// Symmetric
+	K string `json:"k"`
+}
+
+// verificationKey is a parsed public (or shared) key usable to verify signatures.
+type verificationKey struct {
+	kid    string
+	alg    string
+	public interface{} // *rsa.PublicKey, *ecdsa.PublicKey or []byte
+}
+
+// KeySet holds the keys used to verify JWT signatures.
+type KeySet struct {
+	mu   sync.RWMutex
+	keys []verificationKey
+
+	// Remote sets re-fetch on unknown kid, at most once per minRefresh
+	// whether or not the fetch succeeds. refreshMu lets one fetch run at a
+	// time; callers waiting on it look the kid up again afterwards.
+	url         string
+	httpClient  *http.Client
+	refreshMu   sync.Mutex
+	lastRefresh time.Time // Time of the last fetch attempt
+	minRefresh  time.Duration
+}
+
+// ParseJWKS parses a JWKS document ({"keys":[...]}).
+func ParseJWKS(data []byte) (*KeySet, error) {
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+	return &KeySet{keys: keys}, nil
+}
+
+// LoadJWKSFile reads a JWKS document from disk. Useful for offline tests and
+// for deployments that sync the IdP's keys out of band.
+func LoadJWKSFile(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// FetchJWKS downloads a JWKS document from an OIDC provider. The returned set
+// refreshes itself when it encounters a key ID it does not know.
+func FetchJWKS(ctx context.Context, url string) (*KeySet, error) {
+	ks := &KeySet{
+		url:        url,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		minRefresh: time.Minute,
+	}
+	ks.lastRefresh = time.Now()
+	if err := ks.refresh(ctx); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+func (ks *KeySet) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+	resp, err := ks.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch JWKS: status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read JWKS: %w", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.mu.Unlock()
+	return nil
+}
+
+// lookup finds a key by kid. With no kid, a set holding exactly one key matches it.
+func (ks *KeySet) lookup(ctx context.Context, kid string) (verificationKey, bool) {
+	if key, ok := ks.find(kid); ok {
+		return key, true
+	}
+
+	if ks.url == "" {
+		return verificationKey{}, false
+	}
+
+	ks.refreshMu.Lock()
+	defer ks.refreshMu.Unlock()
+	// Another caller may have refreshed while this one waited
+	if key, ok := ks.find(kid); ok {
+		return key, true
+	}
+	if time.Since(ks.lastRefresh) < ks.minRefresh {
+		return verificationKey{}, false
+	}
+	// Failed attempts count too, so an erroring IdP is not hammered
+	ks.lastRefresh = time.Now()
+	if err := ks.refresh(ctx); err != nil {
+		return verificationKey{}, false
+	}
+	return ks.find(kid)
+}
+
+func (ks *KeySet) find(kid string) (verificationKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	if kid == "" {
+		if len(ks.keys) == 1 {
+			return ks.keys[0], true
+		}
+		return verificationKey{}, false
+	}
+	for _, key := range ks.keys {
+		if key.kid == kid {
+			return key, true
+		}
+	}
+	return verificationKey{}, false
+}
+
+func parseJWKS(data []byte) ([]verificationKey, error) {
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %w", err)
+	}
+	if len(doc.Keys) == 0 {
+		return nil, errors.New("JWKS contains no keys")
+	}
+
+	keys := make([]verificationKey, 0, len(doc.Keys))
+	for _, k := range doc.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		public, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWK %q: %w", k.Kid, err)
+		}
+		keys = append(keys, verificationKey{kid: k.Kid, alg: k.Alg, public: public})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS contains no signing keys")
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("y: %w", err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil {
+			return nil, fmt.Errorf("k: %w", err)
+		}
+		if len(secret) == 0 {
+			return nil, errors.New("empty symmetric key")
+		}
+		return secret, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, errors.New("missing value")
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
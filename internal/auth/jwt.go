@@ -0,0 +1,303 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+// JWTConfig configures how tokens are validated and how claims map to an Identity.
+type JWTConfig struct {
+	Issuer   string // Required "iss" value; empty skips the check
+	Audience string // Required "aud" entry; empty skips the check
+
+	TenantClaim   string // Claim holding the tenant ID (default "tenant_id")
+	CustomerClaim string // Claim holding the customer ID (default "sub")
+	NameClaim     string // Claim holding the display name (default "name")
+	RolesClaim    string // Claim holding roles (default "roles")
+
+	// RoleScopes maps IdP roles to scopes. Defaults to DefaultRoleScopes.
+	RoleScopes map[string][]Scope
+	// DefaultScopes are granted when the token carries no roles (e.g. widget customers).
+	DefaultScopes []Scope
+
+	// Leeway tolerates small clock skew on exp/nbf.
+	Leeway time.Duration
+}
+
+// DefaultRoleScopes is the role mapping used when JWTConfig.RoleScopes is nil.
+var DefaultRoleScopes = map[string][]Scope{
+	"customer":  {ScopeQuery},
+	"agent":     {ScopeQuery},
+	"kb_editor": {ScopeKBWrite},
+	"admin":     {ScopeAdmin, ScopeQuery, ScopeKBWrite},
+}
+
+// JWTVerifier validates signed JWTs against a JWKS and maps claims to identities.
+type JWTVerifier struct {
+	keys   *KeySet
+	config JWTConfig
+	now    func() time.Time
+}
+
+// NewJWTVerifier creates a verifier, filling in claim-name defaults.
+func NewJWTVerifier(keys *KeySet, config JWTConfig) *JWTVerifier {
+	if config.TenantClaim == "" {
+		config.TenantClaim = "tenant_id"
+	}
+	if config.CustomerClaim == "" {
+		config.CustomerClaim = "sub"
+	}
+	if config.NameClaim == "" {
+		config.NameClaim = "name"
+	}
+	if config.RolesClaim == "" {
+		config.RolesClaim = "roles"
+	}
+	if config.RoleScopes == nil {
+		config.RoleScopes = DefaultRoleScopes
+	}
+	if config.DefaultScopes == nil {
+		config.DefaultScopes = []Scope{ScopeQuery}
+	}
+	if config.Leeway == 0 {
+		config.Leeway = 30 * time.Second
+	}
+	return &JWTVerifier{keys: keys, config: config, now: time.Now}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Typ string `json:"typ"`
+}
+
+// Verify checks the token signature and standard claims, then maps it to an Identity.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*Identity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+
+	key, ok := v.keys.lookup(ctx, header.Kid)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidToken, header.Kid)
+	}
+	if key.alg != "" && key.alg != header.Alg {
+		return nil, fmt.Errorf("%w: algorithm %q does not match key", ErrInvalidToken, header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: signature encoding", ErrInvalidToken)
+	}
+	if err := verifySignature(header.Alg, key.public, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	if err := v.validateClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return v.identityFromClaims(claims)
+}
+
+func (v *JWTVerifier) validateClaims(claims map[string]interface{}) error {
+	now := v.now()
+
+	exp, ok := numericClaim(claims, "exp")
+	if !ok {
+		return fmt.Errorf("%w: missing exp", ErrInvalidToken)
+	}
+	if now.After(time.Unix(exp, 0).Add(v.config.Leeway)) {
+		return ErrTokenExpired
+	}
+	if nbf, ok := numericClaim(claims, "nbf"); ok && now.Add(v.config.Leeway).Before(time.Unix(nbf, 0)) {
+		return fmt.Errorf("%w: token not yet valid", ErrInvalidToken)
+	}
+
+	if v.config.Issuer != "" {
+		if iss, _ := claims["iss"].(string); iss != v.config.Issuer {
+			return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+		}
+	}
+	if v.config.Audience != "" && !containsString(stringsClaim(claims, "aud"), v.config.Audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+	return nil
+}
+
+func (v *JWTVerifier) identityFromClaims(claims map[string]interface{}) (*Identity, error) {
+	tenantID, _ := claims[v.config.TenantClaim].(string)
+	customerID, _ := claims[v.config.CustomerClaim].(string)
+	if customerID == "" {
+		// The subject keeps audit logs and per-caller state attributable
+		customerID, _ = claims["sub"].(string)
+	}
+	if customerID == "" {
+		return nil, fmt.Errorf("%w: missing %s claim", ErrInvalidToken, v.config.CustomerClaim)
+	}
+	name, _ := claims[v.config.NameClaim].(string)
+	roles := stringsClaim(claims, v.config.RolesClaim)
+
+	var scopes []Scope
+	if len(roles) == 0 {
+		scopes = append(scopes, v.config.DefaultScopes...)
+	}
+	for _, role := range roles {
+		for _, sc := range v.config.RoleScopes[role] {
+			if !containsScope(scopes, sc) {
+				scopes = append(scopes, sc)
+			}
+		}
+	}
+
+	// Only admin tokens may omit the tenant and act at platform level.
+	if tenantID == "" && !containsScope(scopes, ScopeAdmin) {
+		return nil, fmt.Errorf("%w: missing %s claim", ErrInvalidToken, v.config.TenantClaim)
+	}
+
+	return &Identity{
+		Method:       "jwt",
+		TenantID:     tenantID,
+		CustomerID:   customerID,
+		CustomerName: name,
+		Roles:        roles,
+		Scopes:       scopes,
+	}, nil
+}
+
+func verifySignature(alg string, key interface{}, signingInput, signature []byte) error {
+	switch alg {
+	case "RS256", "RS384", "RS512", "PS256", "PS384", "PS512":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match algorithm")
+		}
+		hash, digest := digestFor(alg, signingInput)
+		if strings.HasPrefix(alg, "PS") {
+			return rsa.VerifyPSS(pub, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		return rsa.VerifyPKCS1v15(pub, hash, digest, signature)
+	case "ES256", "ES384", "ES512":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.New("key type does not match algorithm")
+		}
+		_, digest := digestFor(alg, signingInput)
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid signature length")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return errors.New("signature verification failed")
+		}
+		return nil
+	case "HS256", "HS384", "HS512":
+		secret, ok := key.([]byte)
+		if !ok {
+			return errors.New("key type does not match algorithm")
+		}
+		hash, _ := digestFor(alg, nil)
+		mac := hmac.New(hash.New, secret)
+		mac.Write(signingInput)
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return errors.New("signature verification failed")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+}
+
+func digestFor(alg string, data []byte) (crypto.Hash, []byte) {
+	switch alg[2:] {
+	case "384":
+		sum := sha512.Sum384(data)
+		return crypto.SHA384, sum[:]
+	case "512":
+		sum := sha512.Sum512(data)
+		return crypto.SHA512, sum[:]
+	default:
+		sum := sha256.Sum256(data)
+		return crypto.SHA256, sum[:]
+	}
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func numericClaim(claims map[string]interface{}, name string) (int64, bool) {
+	f, ok := claims[name].(float64)
+	if !ok {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// stringsClaim reads a claim that may be a string, a space-separated string or an array.
+func stringsClaim(claims map[string]interface{}, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsScope(list []Scope, s Scope) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
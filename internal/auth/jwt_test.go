@@ -0,0 +1,263 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func b64(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	input := b64(header) + "." + b64(payload)
+	digest := sha256.Sum256([]byte(input))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("SignPKCS1v15() error = %v", err)
+	}
+	return input + "." + b64(sig)
+}
+
+func writeJWKS(t *testing.T, keys ...map[string]string) string {
+	t.Helper()
+	data, _ := json.Marshal(map[string]interface{}{"keys": keys})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func newRSAVerifier(t *testing.T, config JWTConfig) (*JWTVerifier, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	path := writeJWKS(t, map[string]string{
+		"kty": "RSA",
+		"kid": "rsa-1",
+		"alg": "RS256",
+		"use": "sig",
+		"n":   b64(key.N.Bytes()),
+		"e":   b64(big.NewInt(int64(key.E)).Bytes()),
+	})
+	keys, err := LoadJWKSFile(path)
+	if err != nil {
+		t.Fatalf("LoadJWKSFile() error = %v", err)
+	}
+	return NewJWTVerifier(keys, config), key
+}
+
+func TestJWTVerifier_Verify(t *testing.T) {
+	verifier, key := newRSAVerifier(t, JWTConfig{Issuer: "https://idp.example", Audience: "support-widget"})
+	now := time.Now()
+
+	base := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":       "https://idp.example",
+			"aud":       []string{"support-widget"},
+			"sub":       "cust-42",
+			"name":      "Budi",
+			"tenant_id": "shop-123",
+			"exp":       now.Add(time.Hour).Unix(),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		mutate  func(map[string]interface{})
+		wantErr error
+	}{
+		{name: "valid customer token", mutate: func(map[string]interface{}) {}},
+		{name: "expired", mutate: func(c map[string]interface{}) { c["exp"] = now.Add(-time.Hour).Unix() }, wantErr: ErrTokenExpired},
+		{name: "wrong issuer", mutate: func(c map[string]interface{}) { c["iss"] = "https://evil.example" }, wantErr: ErrInvalidToken},
+		{name: "wrong audience", mutate: func(c map[string]interface{}) { c["aud"] = "other" }, wantErr: ErrInvalidToken},
+		{name: "missing tenant", mutate: func(c map[string]interface{}) { delete(c, "tenant_id") }, wantErr: ErrInvalidToken},
+		{name: "not yet valid", mutate: func(c map[string]interface{}) { c["nbf"] = now.Add(time.Hour).Unix() }, wantErr: ErrInvalidToken},
+		{name: "missing subject", mutate: func(c map[string]interface{}) { delete(c, "sub") }, wantErr: ErrInvalidToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := base()
+			tt.mutate(claims)
+			identity, err := verifier.Verify(context.Background(), signRS256(t, key, "rsa-1", claims))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if identity.TenantID != "shop-123" || identity.CustomerID != "cust-42" || identity.CustomerName != "Budi" {
+				t.Errorf("Verify() identity = %+v", identity)
+			}
+			if !identity.HasScope(ScopeQuery) || identity.HasScope(ScopeAdmin) {
+				t.Errorf("Verify() scopes = %v, want only query", identity.Scopes)
+			}
+		})
+	}
+}
+
+func TestJWTVerifier_RolesMapToScopes(t *testing.T) {
+	verifier, key := newRSAVerifier(t, JWTConfig{})
+	token := signRS256(t, key, "rsa-1", map[string]interface{}{
+		"sub":   "ops-1",
+		"roles": []string{"admin"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	})
+
+	identity, err := verifier.Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !identity.IsPlatform() || !identity.HasScope(ScopeAdmin) {
+		t.Errorf("Verify() identity = %+v, want platform admin", identity)
+	}
+}
+
+func TestJWTVerifier_RejectsTamperedAndUnsigned(t *testing.T) {
+	verifier, key := newRSAVerifier(t, JWTConfig{})
+	claims := map[string]interface{}{"sub": "c", "tenant_id": "shop-123", "exp": time.Now().Add(time.Hour).Unix()}
+	token := signRS256(t, key, "rsa-1", claims)
+
+	claims["tenant_id"] = "shop-456"
+	forgedPayload, _ := json.Marshal(claims)
+	parts := strings.Split(token, ".")
+	tampered := parts[0] + "." + b64(forgedPayload) + "." + parts[2]
+	if _, err := verifier.Verify(context.Background(), tampered); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify(tampered) error = %v, want ErrInvalidToken", err)
+	}
+
+	noneHeader, _ := json.Marshal(map[string]string{"alg": "none", "kid": "rsa-1"})
+	unsigned := b64(noneHeader) + "." + parts[1] + "."
+	if _, err := verifier.Verify(context.Background(), unsigned); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Verify(alg=none) error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestJWTVerifier_ES256AndHS256(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	secret := []byte("shared-secret-for-tests")
+	path := writeJWKS(t,
+		map[string]string{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(ecKey.X.FillBytes(make([]byte, 32))), "y": b64(ecKey.Y.FillBytes(make([]byte, 32)))},
+		map[string]string{"kty": "oct", "kid": "hs-1", "k": b64(secret)},
+	)
+	keys, err := LoadJWKSFile(path)
+	if err != nil {
+		t.Fatalf("LoadJWKSFile() error = %v", err)
+	}
+	verifier := NewJWTVerifier(keys, JWTConfig{})
+
+	claims, _ := json.Marshal(map[string]interface{}{"sub": "c", "tenant_id": "shop-123", "exp": time.Now().Add(time.Hour).Unix()})
+
+	esHeader, _ := json.Marshal(map[string]string{"alg": "ES256", "kid": "ec-1"})
+	esInput := b64(esHeader) + "." + b64(claims)
+	digest := sha256.Sum256([]byte(esInput))
+	r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest[:])
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	esSig := append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	if _, err := verifier.Verify(context.Background(), esInput+"."+b64(esSig)); err != nil {
+		t.Errorf("Verify(ES256) error = %v", err)
+	}
+
+	hsHeader, _ := json.Marshal(map[string]string{"alg": "HS256", "kid": "hs-1"})
+	hsInput := b64(hsHeader) + "." + b64(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(hsInput))
+	if _, err := verifier.Verify(context.Background(), hsInput+"."+b64(mac.Sum(nil))); err != nil {
+		t.Errorf("Verify(HS256) error = %v", err)
+	}
+
+	// An HS256 token must not verify against the EC key (algorithm confusion).
+	if _, err := verifier.Verify(context.Background(), fmt.Sprintf("%s.%s.%s", b64([]byte(`{"alg":"HS256","kid":"ec-1"}`)), b64(claims), b64(mac.Sum(nil)))); err == nil {
+		t.Error("Verify(HS256 with EC key) error = nil, want error")
+	}
+}
+
+func TestJWTVerifier_CustomerClaimFallsBackToSub(t *testing.T) {
+	verifier, key := newRSAVerifier(t, JWTConfig{CustomerClaim: "customer_id"})
+	token := signRS256(t, key, "rsa-1", map[string]interface{}{
+		"sub":       "cust-42",
+		"tenant_id": "shop-123",
+		"exp":       time.Now().Add(time.Hour).Unix(),
+	})
+	identity, err := verifier.Verify(context.Background(), token)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if identity.CustomerID != "cust-42" || identity.Subject() != "jwt:cust-42" {
+		t.Errorf("Verify() identity = %+v, want customer from sub", identity)
+	}
+}
+
+func TestKeySet_RefreshIsRateLimited(t *testing.T) {
+	var fetches atomic.Int32
+	var failing atomic.Bool
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"keys":[{"kty":"oct","kid":"hs-1","k":"c2VjcmV0"}]}`))
+	}))
+	defer idp.Close()
+
+	ks, err := FetchJWKS(context.Background(), idp.URL)
+	if err != nil {
+		t.Fatalf("FetchJWKS() error = %v", err)
+	}
+	failing.Store(true)
+	// Let the next unknown kid refresh once
+	ks.lastRefresh = time.Now().Add(-2 * ks.minRefresh)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ks.lookup(context.Background(), "unknown")
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 5; i++ {
+		ks.lookup(context.Background(), "unknown")
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("JWKS fetches = %d, want 2 (initial + one failed refresh)", got)
+	}
+	if _, ok := ks.lookup(context.Background(), "hs-1"); !ok {
+		t.Error("lookup(hs-1) = false, want keys kept after a failed refresh")
+	}
+}
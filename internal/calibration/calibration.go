@@ -0,0 +1,322 @@
+// Package calibration maps raw confidence scores to the observed probability
+// that an answer was helpful, so the confidence threshold means what it says.
+package calibration
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"time"
+)
+
+// Method selects how a calibration curve is fitted
+type Method string
+
+const (
+	// MethodIsotonic fits a monotone step curve; flexible but needs more samples
+	MethodIsotonic Method = "isotonic"
+	// MethodPlatt fits a sigmoid; robust with few samples
+	MethodPlatt Method = "platt"
+)
+
+var (
+	ErrNoSamples     = errors.New("no calibration samples")
+	ErrUnknownMethod = errors.New("unknown calibration method")
+)
+
+// Sample is one answered request with its raw confidence and outcome.
+// Answers that were escalated count as unhelpful; otherwise Helpful decides,
+// and answers without feedback that were not escalated count as helpful.
+type Sample struct {
+	TenantID   string    `json:"tenant_id"`
+	Confidence float64   `json:"confidence"`
+	Helpful    *bool     `json:"helpful,omitempty"`
+	Escalated  bool      `json:"escalated,omitempty"`
+	Time       time.Time `json:"time,omitempty"`
+}
+
+// Positive reports whether the sample counts as a helpful answer.
+func (s Sample) Positive() bool {
+	if s.Escalated {
+		return false
+	}
+	return s.Helpful == nil || *s.Helpful
+}
+
+// Point is one breakpoint of an isotonic curve.
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Curve maps a raw confidence to a calibrated probability.
+type Curve struct {
+	Method  Method  `json:"method"`
+	Samples int     `json:"samples"`
+	A       float64 `json:"a,omitempty"`      // Platt: p = 1 / (1 + exp(-(A*x + B)))
+	B       float64 `json:"b,omitempty"`      // Platt intercept
+	Points  []Point `json:"points,omitempty"` // Isotonic: interpolated, flat outside the range
+}
+
+// Apply returns the calibrated confidence, clamped to [0, 1].
+func (c *Curve) Apply(confidence float64) float64 {
+	var p float64
+	switch c.Method {
+	case MethodPlatt:
+		p = sigmoid(c.A*confidence + c.B)
+	case MethodIsotonic:
+		p = interpolate(c.Points, confidence)
+	default:
+		p = confidence
+	}
+	return math.Max(0, math.Min(1, p))
+}
+
+// Fit fits a curve to samples with the given method.
+func Fit(samples []Sample, method Method) (*Curve, error) {
+	if len(samples) == 0 {
+		return nil, ErrNoSamples
+	}
+	switch method {
+	case MethodPlatt:
+		a, b := fitPlatt(samples)
+		return &Curve{Method: method, Samples: len(samples), A: a, B: b}, nil
+	case MethodIsotonic:
+		return &Curve{Method: method, Samples: len(samples), Points: fitIsotonic(samples)}, nil
+	default:
+		return nil, fmt.Errorf("%w %q (want isotonic or platt)", ErrUnknownMethod, method)
+	}
+}
+
+// Brier returns the mean squared error between predicted probabilities and
+// outcomes; lower is better. A nil curve scores the raw confidence.
+func Brier(samples []Sample, curve *Curve) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, s := range samples {
+		p := s.Confidence
+		if curve != nil {
+			p = curve.Apply(p)
+		}
+		d := p - outcome(s)
+		sum += d * d
+	}
+	return sum / float64(len(samples))
+}
+
+// Profile holds the fitted curves; tenants without their own curve use Default.
+type Profile struct {
+	CreatedAt time.Time         `json:"created_at"`
+	Default   *Curve            `json:"default,omitempty"`
+	Tenants   map[string]*Curve `json:"tenants,omitempty"`
+}
+
+// Curve returns the curve for tenantID, or nil when none applies.
+func (p *Profile) Curve(tenantID string) *Curve {
+	if c, ok := p.Tenants[tenantID]; ok {
+		return c
+	}
+	return p.Default
+}
+
+// Apply calibrates confidence for tenantID; without a curve it is returned unchanged.
+func (p *Profile) Apply(tenantID string, confidence float64) float64 {
+	c := p.Curve(tenantID)
+	if c == nil {
+		return confidence
+	}
+	return c.Apply(confidence)
+}
+
+// LoadProfile reads a profile written by the calibrate tool.
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read calibration profile: %w", err)
+	}
+	var p Profile
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse calibration profile %s: %w", path, err)
+	}
+	if p.Default != nil && p.Default.Method != MethodPlatt && p.Default.Method != MethodIsotonic {
+		return nil, fmt.Errorf("calibration profile %s: default: %w", path, ErrUnknownMethod)
+	}
+	for tenantID, c := range p.Tenants {
+		if c == nil || (c.Method != MethodPlatt && c.Method != MethodIsotonic) {
+			return nil, fmt.Errorf("calibration profile %s: tenant %s: %w", path, tenantID, ErrUnknownMethod)
+		}
+	}
+	return &p, nil
+}
+
+// Save writes the profile as indented JSON.
+func (p *Profile) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// ReadSamples reads JSON lines of Sample; blank lines are skipped.
+func ReadSamples(r io.Reader) ([]Sample, error) {
+	var samples []Sample
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var s Sample
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if s.Confidence < 0 || s.Confidence > 1 {
+			return nil, fmt.Errorf("line %d: confidence %v out of range", line, s.Confidence)
+		}
+		samples = append(samples, s)
+	}
+	return samples, scanner.Err()
+}
+
+func outcome(s Sample) float64 {
+	if s.Positive() {
+		return 1
+	}
+	return 0
+}
+
+func sigmoid(z float64) float64 {
+	return 1 / (1 + math.Exp(-z))
+}
+
+// fitPlatt fits A and B by Newton's method with backtracking on the log loss,
+// using Platt's smoothed targets so a tenant with only one kind of outcome
+// stays finite.
+func fitPlatt(samples []Sample) (a, b float64) {
+	positives := 0
+	for _, s := range samples {
+		if s.Positive() {
+			positives++
+		}
+	}
+	negatives := len(samples) - positives
+	hi := (float64(positives) + 1) / (float64(positives) + 2)
+	lo := 1 / (float64(negatives) + 2)
+	target := func(s Sample) float64 {
+		if s.Positive() {
+			return hi
+		}
+		return lo
+	}
+	loss := func(a, b float64) float64 {
+		sum := 0.0
+		for _, s := range samples {
+			p := math.Min(math.Max(sigmoid(a*s.Confidence+b), 1e-12), 1-1e-12)
+			t := target(s)
+			sum -= t*math.Log(p) + (1-t)*math.Log(1-p)
+		}
+		return sum
+	}
+
+	a, b = 1, 0
+	current := loss(a, b)
+	for iter := 0; iter < 100; iter++ {
+		// Gradient and Hessian of the log loss with respect to (a, b)
+		var ga, gb, haa, hab, hbb float64
+		for _, s := range samples {
+			p := sigmoid(a*s.Confidence + b)
+			d := p - target(s)
+			w := p * (1 - p)
+			ga += d * s.Confidence
+			gb += d
+			haa += w * s.Confidence * s.Confidence
+			hab += w * s.Confidence
+			hbb += w
+		}
+		// A small ridge keeps the Hessian invertible when all scores are equal
+		haa += 1e-6
+		hbb += 1e-6
+		det := haa*hbb - hab*hab
+		da := (hbb*ga - hab*gb) / det
+		db := (haa*gb - hab*ga) / det
+
+		// Halve the step until the loss improves
+		step := 1.0
+		for ; step > 1e-8; step /= 2 {
+			if next := loss(a-step*da, b-step*db); next < current {
+				a, b, current = a-step*da, b-step*db, next
+				break
+			}
+		}
+		if step <= 1e-8 || math.Abs(step*da) < 1e-9 && math.Abs(step*db) < 1e-9 {
+			break
+		}
+	}
+	return a, b
+}
+
+// fitIsotonic runs pool-adjacent-violators over samples sorted by confidence
+// and returns one point per block at the block's mean confidence.
+func fitIsotonic(samples []Sample) []Point {
+	sorted := make([]Sample, len(samples))
+	copy(sorted, samples)
+	// Positives first among ties so equal scores pool into a single block
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Confidence != sorted[j].Confidence {
+			return sorted[i].Confidence < sorted[j].Confidence
+		}
+		return sorted[i].Positive() && !sorted[j].Positive()
+	})
+
+	type block struct {
+		sumX, sumY, n float64
+	}
+	var blocks []block
+	for _, s := range sorted {
+		blocks = append(blocks, block{sumX: s.Confidence, sumY: outcome(s), n: 1})
+		// Merge while the previous block predicts more than this one
+		for len(blocks) > 1 {
+			last, prev := blocks[len(blocks)-1], blocks[len(blocks)-2]
+			if prev.sumY/prev.n <= last.sumY/last.n {
+				break
+			}
+			blocks = blocks[:len(blocks)-2]
+			blocks = append(blocks, block{sumX: prev.sumX + last.sumX, sumY: prev.sumY + last.sumY, n: prev.n + last.n})
+		}
+	}
+
+	points := make([]Point, len(blocks))
+	for i, b := range blocks {
+		points[i] = Point{X: b.sumX / b.n, Y: b.sumY / b.n}
+	}
+	return points
+}
+
+// interpolate evaluates a monotone curve linearly between points.
+func interpolate(points []Point, x float64) float64 {
+	if len(points) == 0 {
+		return x
+	}
+	if x <= points[0].X {
+		return points[0].Y
+	}
+	last := points[len(points)-1]
+	if x >= last.X {
+		return last.Y
+	}
+	i := sort.Search(len(points), func(i int) bool { return points[i].X >= x })
+	left, right := points[i-1], points[i]
+	if right.X == left.X {
+		return right.Y
+	}
+	return left.Y + (right.Y-left.Y)*(x-left.X)/(right.X-left.X)
+}
@@ -0,0 +1,225 @@
+package calibration
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// overconfident returns samples whose true helpful rate is half the raw score.
+func overconfident() []Sample {
+	var samples []Sample
+	for _, conf := range []float64{0.2, 0.4, 0.6, 0.8, 1.0} {
+		helpfulOf10 := int(conf * 5)
+		for i := 0; i < 10; i++ {
+			helpful := i < helpfulOf10
+			samples = append(samples, Sample{TenantID: "shop-1", Confidence: conf, Helpful: &helpful})
+		}
+	}
+	return samples
+}
+
+func TestFit_Isotonic(t *testing.T) {
+	samples := overconfident()
+	curve, err := Fit(samples, MethodIsotonic)
+	if err != nil {
+		t.Fatalf("Fit() error = %v", err)
+	}
+	if got := curve.Apply(0.8); math.Abs(got-0.4) > 1e-9 {
+		t.Errorf("Apply(0.8) = %v, want 0.4", got)
+	}
+	if got := curve.Apply(0.7); math.Abs(got-0.35) > 1e-9 {
+		t.Errorf("Apply(0.7) = %v, want interpolated 0.35", got)
+	}
+	if got := curve.Apply(0.05); math.Abs(got-0.1) > 1e-9 {
+		t.Errorf("Apply(0.05) = %v, want flat below the first point", got)
+	}
+	if Brier(samples, curve) >= Brier(samples, nil) {
+		t.Errorf("Brier calibrated = %v, raw = %v, want improvement", Brier(samples, curve), Brier(samples, nil))
+	}
+}
+
+func TestFit_IsotonicIsMonotone(t *testing.T) {
+	yes, no := true, false
+	samples := []Sample{
+		{Confidence: 0.3, Helpful: &yes},
+		{Confidence: 0.5, Helpful: &no},
+		{Confidence: 0.5, Helpful: &yes},
+		{Confidence: 0.7, Helpful: &no},
+		{Confidence: 0.9, Helpful: &yes},
+	}
+	curve, err := Fit(samples, MethodIsotonic)
+	if err != nil {
+		t.Fatalf("Fit() error = %v", err)
+	}
+	prev := -1.0
+	for x := 0.0; x <= 1.0; x += 0.05 {
+		y := curve.Apply(x)
+		if y < prev-1e-12 {
+			t.Fatalf("Apply(%v) = %v, below previous %v", x, y, prev)
+		}
+		prev = y
+	}
+}
+
+func TestFit_Platt(t *testing.T) {
+	samples := overconfident()
+	curve, err := Fit(samples, MethodPlatt)
+	if err != nil {
+		t.Fatalf("Fit() error = %v", err)
+	}
+	if curve.A <= 0 {
+		t.Errorf("A = %v, want positive slope", curve.A)
+	}
+	if got := curve.Apply(0.8); got < 0.3 || got > 0.5 {
+		t.Errorf("Apply(0.8) = %v, want about 0.4", got)
+	}
+	if Brier(samples, curve) >= Brier(samples, nil) {
+		t.Errorf("Brier calibrated = %v, raw = %v, want improvement", Brier(samples, curve), Brier(samples, nil))
+	}
+
+	// A single outcome class must not diverge
+	yes := true
+	curve, err = Fit([]Sample{{Confidence: 0.7, Helpful: &yes}, {Confidence: 0.7, Helpful: &yes}}, MethodPlatt)
+	if err != nil {
+		t.Fatalf("Fit() error = %v", err)
+	}
+	if got := curve.Apply(0.7); math.IsNaN(got) || got < 0.5 || got > 1 {
+		t.Errorf("Apply(0.7) = %v, want a finite probability above 0.5", got)
+	}
+}
+
+func TestFit_Errors(t *testing.T) {
+	if _, err := Fit(nil, MethodPlatt); !errors.Is(err, ErrNoSamples) {
+		t.Errorf("Fit(nil) error = %v, want ErrNoSamples", err)
+	}
+	if _, err := Fit(overconfident(), "spline"); !errors.Is(err, ErrUnknownMethod) {
+		t.Errorf("Fit(spline) error = %v, want ErrUnknownMethod", err)
+	}
+}
+
+func TestSample_Positive(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		sample Sample
+		want   bool
+	}{
+		{Sample{Helpful: &yes}, true},
+		{Sample{Helpful: &no}, false},
+		{Sample{Helpful: &yes, Escalated: true}, false},
+		{Sample{}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.sample.Positive(); got != tt.want {
+			t.Errorf("Positive(%+v) = %v, want %v", tt.sample, got, tt.want)
+		}
+	}
+}
+
+func TestProfile_SaveLoadApply(t *testing.T) {
+	tenantCurve, _ := Fit(overconfident(), MethodIsotonic)
+	profile := &Profile{
+		CreatedAt: time.Now().UTC(),
+		Default:   &Curve{Method: MethodPlatt, A: 0, B: 0},
+		Tenants:   map[string]*Curve{"shop-1": tenantCurve},
+	}
+	path := filepath.Join(t.TempDir(), "calibration.json")
+	if err := profile.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadProfile(path)
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if got := loaded.Apply("shop-1", 0.8); math.Abs(got-0.4) > 1e-9 {
+		t.Errorf("Apply(shop-1) = %v, want tenant curve 0.4", got)
+	}
+	if got := loaded.Apply("shop-2", 0.9); math.Abs(got-0.5) > 1e-9 {
+		t.Errorf("Apply(shop-2) = %v, want default curve 0.5", got)
+	}
+	if got := (&Profile{}).Apply("shop-2", 0.9); got != 0.9 {
+		t.Errorf("empty profile Apply() = %v, want unchanged 0.9", got)
+	}
+}
+
+func TestReadSamples(t *testing.T) {
+	input := `{"tenant_id":"shop-1","confidence":0.8,"helpful":true}
+
+{"tenant_id":"shop-1","confidence":0.6,"escalated":true}
+`
+	samples, err := ReadSamples(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadSamples() error = %v", err)
+	}
+	if len(samples) != 2 || !samples[0].Positive() || samples[1].Positive() {
+		t.Errorf("ReadSamples() = %+v", samples)
+	}
+	if _, err := ReadSamples(strings.NewReader(`{"confidence":1.5}`)); err == nil {
+		t.Error("ReadSamples() error = nil, want error for out-of-range confidence")
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "samples.jsonl")
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := NewRecorder(path)
+	r.now = func() time.Time { return now }
+
+	r.Track("req-1", "shop-1", 0.82)
+	r.Track("req-2", "shop-1", 0.4)
+	if tenantID, err := r.Tenant("req-1"); err != nil || tenantID != "shop-1" {
+		t.Errorf("Tenant() = %q, %v, want shop-1", tenantID, err)
+	}
+	if _, err := r.Label("req-1", true); err != nil {
+		t.Fatalf("Label() error = %v", err)
+	}
+	if _, err := r.Label("req-1", false); !errors.Is(err, ErrResponseNotFound) {
+		t.Errorf("second Label() error = %v, want ErrResponseNotFound", err)
+	}
+	if err := r.Track("req-1", "shop-1", 0.1); !errors.Is(err, ErrResponseTracked) {
+		t.Errorf("Track() of labelled response error = %v, want ErrResponseTracked", err)
+	}
+	if err := r.Track("req-2", "shop-1", 0.9); !errors.Is(err, ErrResponseTracked) {
+		t.Errorf("Track() of pending response error = %v, want ErrResponseTracked", err)
+	}
+
+	now = now.Add(LabelWindow + time.Minute)
+	if _, err := r.Label("req-2", false); !errors.Is(err, ErrResponseNotFound) {
+		t.Errorf("expired Label() error = %v, want ErrResponseNotFound", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer f.Close()
+	samples, err := ReadSamples(f)
+	if err != nil {
+		t.Fatalf("ReadSamples() error = %v", err)
+	}
+	if len(samples) != 1 || samples[0].Confidence != 0.82 || !samples[0].Positive() {
+		t.Errorf("recorded samples = %+v", samples)
+	}
+}
+
+func TestRecorder_Escalate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "samples.jsonl")
+	r := NewRecorder(path)
+
+	r.Track("resp-1", "shop-1", 0.55)
+	sample, err := r.Escalate("resp-1")
+	if err != nil {
+		t.Fatalf("Escalate() error = %v", err)
+	}
+	if !sample.Escalated || sample.Helpful == nil || *sample.Helpful || sample.Positive() {
+		t.Errorf("Escalate() = %+v, want an escalated, unhelpful sample", sample)
+	}
+	if _, err := r.Label("resp-1", true); !errors.Is(err, ErrResponseNotFound) {
+		t.Errorf("Label() after Escalate() error = %v, want ErrResponseNotFound", err)
+	}
+}
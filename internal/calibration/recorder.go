@@ -0,0 +1,122 @@
+package calibration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// LabelWindow is how long an answer's raw confidence is kept waiting for feedback.
+const LabelWindow = 24 * time.Hour
+
+var (
+	// ErrResponseNotFound is returned for unknown, expired or already labelled responses.
+	ErrResponseNotFound = errors.New("response not tracked for calibration")
+	// ErrResponseTracked is returned when a response ID is tracked a second time.
+	ErrResponseTracked = errors.New("response already tracked for calibration")
+)
+
+type pending struct {
+	tenantID   string
+	confidence float64
+	answeredAt time.Time
+	labelled   bool // Kept until the window ends so the ID cannot be tracked again
+}
+
+// Recorder remembers the raw confidence of recent answers and appends a
+// labelled Sample to a JSON lines file when feedback for them arrives. The
+// file is the input of the calibrate tool.
+type Recorder struct {
+	mu        sync.Mutex
+	path      string
+	pending   map[string]pending
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// NewRecorder creates a recorder appending samples to path.
+func NewRecorder(path string) *Recorder {
+	return &Recorder{
+		path:    path,
+		pending: make(map[string]pending),
+		now:     time.Now,
+	}
+}
+
+// Track remembers the uncalibrated confidence of an answer. A response ID
+// seen within the label window, labelled or not, is refused so one answer
+// yields at most one sample.
+func (r *Recorder) Track(responseID, tenantID string, confidence float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pruneLocked()
+	if p, ok := r.pending[responseID]; ok && r.now().Sub(p.answeredAt) <= LabelWindow {
+		return ErrResponseTracked
+	}
+	r.pending[responseID] = pending{tenantID: tenantID, confidence: confidence, answeredAt: r.now()}
+	return nil
+}
+
+// Tenant returns the tenant of a tracked response.
+func (r *Recorder) Tenant(responseID string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.pending[responseID]
+	if !ok || p.labelled || r.now().Sub(p.answeredAt) > LabelWindow {
+		return "", ErrResponseNotFound
+	}
+	return p.tenantID, nil
+}
+
+// Label writes the sample for a tracked response. Each response is labelled once.
+func (r *Recorder) Label(responseID string, helpful bool) (Sample, error) {
+	return r.label(responseID, helpful, false)
+}
+
+// Escalate labels a tracked response that was handed to a human as unhelpful.
+func (r *Recorder) Escalate(responseID string) (Sample, error) {
+	return r.label(responseID, false, true)
+}
+
+func (r *Recorder) label(responseID string, helpful, escalated bool) (Sample, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.pending[responseID]
+	if !ok || p.labelled || r.now().Sub(p.answeredAt) > LabelWindow {
+		return Sample{}, ErrResponseNotFound
+	}
+
+	sample := Sample{TenantID: p.tenantID, Confidence: p.confidence, Helpful: &helpful, Escalated: escalated, Time: r.now()}
+	line, err := json.Marshal(sample)
+	if err != nil {
+		return Sample{}, err
+	}
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return Sample{}, fmt.Errorf("open calibration samples: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return Sample{}, fmt.Errorf("write calibration sample: %w", err)
+	}
+	p.labelled = true
+	r.pending[responseID] = p
+	return sample, nil
+}
+
+// pruneLocked drops answers past the label window, at most once a minute.
+func (r *Recorder) pruneLocked() {
+	now := r.now()
+	if now.Sub(r.lastPrune) < time.Minute {
+		return
+	}
+	r.lastPrune = now
+	for id, p := range r.pending {
+		if now.Sub(p.answeredAt) > LabelWindow {
+			delete(r.pending, id)
+		}
+	}
+}
@@ -1,117 +1,554 @@
 package config
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/llm"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/pii"
 )
 
+// Config is the service configuration. Each field can be set in the optional
+// YAML config file (CONFIG_FILE) using the lower-cased environment variable
+// name as the key; environment variables override the file.
 type Config struct {
-	Port string
-	Env  string
+	Port string `yaml:"port"`
+	Env  string `yaml:"app_env"`
 
 	// LLM Configuration
-	LLMProvider     string
-	LLMAPIKey       string
-	LLMBaseURL      string
-	LLMDefaultModel string
-	LLMMaxTokens    int
-	LLMTemperature  float64
-	LLMTimeout      int
-	LLMMaxRetries   int
-	LLMRetryDelay   int
+	LLMProvider     string  `yaml:"llm_provider"`
+	LLMAPIKey       string  `yaml:"llm_api_key"`
+	LLMBaseURL      string  `yaml:"llm_base_url"`
+	LLMDefaultModel string  `yaml:"llm_default_model"`
+	LLMMaxTokens    int     `yaml:"llm_max_tokens"`
+	LLMTemperature  float64 `yaml:"llm_temperature"`
+	LLMTimeout      int     `yaml:"llm_timeout"`
+	LLMMaxRetries   int     `yaml:"llm_max_retries"`
+	LLMRetryDelay   int     `yaml:"llm_retry_delay"`
+
+	// LLMConfidenceStrategy combines token logprobs with the heuristic
+	// confidence: heuristic, mean, min or blend
+	LLMConfidenceStrategy string `yaml:"llm_confidence_strategy"`
+
+	// LLMStructuredOutput asks for a JSON answer with sources, intent and an
+	// escalation flag instead of plain text
+	LLMStructuredOutput bool `yaml:"llm_structured_output"`
+
+	// LLMModelPrices prices tokens per model so experiments can compare
+	// variants on cost; models without a price are reported as unpriced
+	LLMModelPrices map[string]ModelPrice `yaml:"llm_model_prices"`
 
 	// Reliability & cost control (Phase 5)
 	// Per-tenant rate limiting (token bucket)
-	TenantRateLimitPerSec float64
-	TenantRateLimitBurst  int
+	TenantRateLimitPerSec float64 `yaml:"tenant_rate_limit_per_sec"`
+	TenantRateLimitBurst  int     `yaml:"tenant_rate_limit_burst"`
 
 	// Response cache TTL in seconds
-	ResponseCacheTTLSeconds int
+	ResponseCacheTTLSeconds int `yaml:"response_cache_ttl_seconds"`
+
+	// How long responses are replayed for a retried Idempotency-Key; 0 ignores the header
+	IdempotencyWindowSeconds int `yaml:"idempotency_window_seconds"`
 
 	// Token usage tracking window (hours) and per-tenant token budget per window
-	TokenUsageWindowHours int
-	TenantTokenBudget     int
+	TokenUsageWindowHours int `yaml:"token_usage_window_hours"`
+	TenantTokenBudget     int `yaml:"tenant_token_budget"`
+
+	// Answer policy defaults; tenants may override these via TenantConfig
+	ConfidenceThreshold float64 `yaml:"confidence_threshold"`
+	FallbackMessage     string  `yaml:"fallback_message"`
+	SystemPrompt        string  `yaml:"system_prompt"` // Empty uses the built-in support prompt
+
+	// TenantRegistryFile persists runtime tenant changes; empty keeps them in memory
+	TenantRegistryFile string `yaml:"tenant_registry_file"`
+
+	// PromptStoreFile persists prompt template versions; empty keeps them in memory
+	PromptStoreFile string `yaml:"prompt_store_file"`
+
+	// Tenants declared in the config file. File tenants are upserted into the
+	// registry at startup and on every reload; runtime-added tenants are kept.
+	Tenants []TenantSpec `yaml:"tenants"`
+
+	// Authentication
+	// AuthEnabled requires an API key on /v1 routes; disable only for local development.
+	AuthEnabled bool `yaml:"auth_enabled"`
+	// AdminAPIKey bootstraps a platform admin key that can issue tenant keys.
+	AdminAPIKey string `yaml:"admin_api_key"`
+	// APIKeyStoreFile persists issued API keys (hashed); empty keeps them in memory
+	APIKeyStoreFile string `yaml:"api_key_store_file"`
+
+	// JWT / OIDC: bearer tokens are accepted when a JWKS file or URL is set
+	JWTJWKSFile      string `yaml:"jwt_jwks_file"`
+	JWTJWKSURL       string `yaml:"jwt_jwks_url"`
+	JWTIssuer        string `yaml:"jwt_issuer"`
+	JWTAudience      string `yaml:"jwt_audience"`
+	JWTTenantClaim   string `yaml:"jwt_tenant_claim"`
+	JWTCustomerClaim string `yaml:"jwt_customer_claim"`
+	JWTRolesClaim    string `yaml:"jwt_roles_claim"`
+
+	// PII redaction before text reaches the LLM, logs and cache keys.
+	// Empty PIIKinds uses the defaults for the request language.
+	PIIRedaction bool     `yaml:"pii_redaction"`
+	PIIKinds     []string `yaml:"pii_kinds"`
+
+	// Prompt injection guard. GuardMode "block" rejects flagged questions with
+	// REJECTED_INPUT; "fallback" answers them with the fallback message.
+	GuardEnabled         bool   `yaml:"guard_enabled"`
+	GuardMode            string `yaml:"guard_mode"`
+	GuardLLMClassifier   bool   `yaml:"guard_llm_classifier"`
+	GuardClassifierModel string `yaml:"guard_classifier_model"` // Empty uses the default model
+
+	// Groundedness: answers are compared with the retrieved knowledge and the
+	// score is blended into confidence with GroundingWeight (0 disables).
+	// GroundingJudge asks the LLM to rate answers the lexical check cannot confirm.
+	GroundingWeight     float64 `yaml:"grounding_weight"`
+	GroundingJudge      bool    `yaml:"grounding_judge"`
+	GroundingJudgeModel string  `yaml:"grounding_judge_model"` // Empty uses the default model
+
+	// LanguageDetectMinConfidence is the confidence a detected language needs to
+	// be trusted: requests without a language are rejected when it is reliably
+	// unsupported, and declared languages that disagree with it are logged
+	LanguageDetectMinConfidence float64 `yaml:"language_detect_min_confidence"`
+
+	// TranslationModel translates questions and answers for tenants with
+	// translations configured; empty uses the default model
+	TranslationModel string `yaml:"translation_model"`
+
+	// Intent classification: keyword rules, optionally backed by an LLM for
+	// questions no rule matches. Tenants route intents in their profile.
+	IntentEnabled         bool   `yaml:"intent_enabled"`
+	IntentLLMClassifier   bool   `yaml:"intent_llm_classifier"`
+	IntentClassifierModel string `yaml:"intent_classifier_model"` // Empty uses the default model
+
+	// FAQMatchThreshold is the similarity a question needs to a tenant FAQ to
+	// be answered with it; tenants can override it with faq_threshold
+	FAQMatchThreshold float64 `yaml:"faq_match_threshold"`
+
+	// Batch queries: at most BatchMaxItems questions per request, answered
+	// BatchConcurrency at a time. Items wait up to BatchRateWaitMs for their
+	// tenant's rate limit before failing with RATE_LIMIT_EXCEEDED.
+	BatchMaxItems    int `yaml:"batch_max_items"`
+	BatchConcurrency int `yaml:"batch_concurrency"`
+	BatchRateWaitMs  int `yaml:"batch_rate_wait_ms"`
+
+	// Asynchronous jobs: JobsWorkers answer queued queries, at most
+	// JobsQueueSize wait at a time. Failed attempts caused by rate limits or
+	// internal errors are retried up to JobsMaxAttempts times. Finished jobs
+	// can be polled for JobsRetentionHours. JobsFile persists the queue across
+	// restarts; empty keeps it in memory.
+	JobsWorkers        int    `yaml:"jobs_workers"`
+	JobsQueueSize      int    `yaml:"jobs_queue_size"`
+	JobsMaxAttempts    int    `yaml:"jobs_max_attempts"`
+	JobsRetentionHours int    `yaml:"jobs_retention_hours"`
+	JobsFile           string `yaml:"jobs_file"`
+
+	// Job callbacks: finished jobs are POSTed to the caller's callback_url,
+	// signed with JobsCallbackSecret when set. An empty
+	// JobsCallbackAllowedHosts allows any public address.
+	JobsCallbackTimeoutMs    int      `yaml:"jobs_callback_timeout_ms"`
+	JobsCallbackMaxAttempts  int      `yaml:"jobs_callback_max_attempts"`
+	JobsCallbackSecret       string   `yaml:"jobs_callback_secret"`
+	JobsCallbackAllowedHosts []string `yaml:"jobs_callback_allowed_hosts"`
+
+	// Tool calling: tenants configure HTTP tools in their profile; these bound
+	// every call. An empty ToolAllowedHosts allows any public host.
+	ToolTimeoutMs     int      `yaml:"tool_timeout_ms"`
+	ToolMaxIterations int      `yaml:"tool_max_iterations"`
+	ToolAllowedHosts  []string `yaml:"tool_allowed_hosts"`
+
+	// Confidence calibration: CalibrationFile is a profile written by
+	// cmd/calibrate; CalibrationSamplesFile collects labelled samples from feedback
+	CalibrationFile        string `yaml:"calibration_file"`
+	CalibrationSamplesFile string `yaml:"calibration_samples_file"`
+
+	// OutputGuardEnabled checks answers against each tenant's output policy
+	OutputGuardEnabled bool `yaml:"output_guard_enabled"`
+
+	// Shadow traffic: when ShadowModel is set, a sample of answered requests is
+	// replayed against it in the background. Empty base URL / API key reuse the
+	// primary LLM settings.
+	ShadowModel          string  `yaml:"shadow_model"`
+	ShadowLLMBaseURL     string  `yaml:"shadow_llm_base_url"`
+	ShadowLLMAPIKey      string  `yaml:"shadow_llm_api_key"`
+	ShadowSampleRate     float64 `yaml:"shadow_sample_rate"`
+	ShadowMaxConcurrency int     `yaml:"shadow_max_concurrency"`
+	ShadowMaxResults     int     `yaml:"shadow_max_results"`
+
+	// ConfigReloadSeconds is how often the config file is checked for changes;
+	// 0 disables polling (SIGHUP still triggers a reload).
+	ConfigReloadSeconds int `yaml:"config_reload_seconds"`
+
+	// File is the config file the values were loaded from, if any.
+	File string `yaml:"-"`
 }
 
-func Load() Config {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+// Defaults returns the built-in configuration that the file and environment are layered on.
+func Defaults() Config {
+	return Config{
+		Port: "8080",
+		Env:  "development",
+
+		LLMProvider:     "openai",
+		LLMDefaultModel: "gpt-3.5-turbo",
+		LLMMaxTokens:    500,
+		LLMTemperature:  0.7,
+		LLMTimeout:      30,
+		LLMMaxRetries:   3,
+		LLMRetryDelay:   100,
+
+		LLMConfidenceStrategy: string(llm.StrategyHeuristic),
+
+		// Reliability & cost control (Phase 5)
+		TenantRateLimitPerSec:   5.0,
+		TenantRateLimitBurst:    10,
+		ResponseCacheTTLSeconds: 300,
+		TokenUsageWindowHours:   24,
+		TenantTokenBudget:       0, // 0 = disabled
+
+		IdempotencyWindowSeconds: 86400,
+
+		ConfidenceThreshold: 0.7,
+		FallbackMessage:     DefaultFallbackMessage,
+
+		AuthEnabled: true,
+
+		JWTTenantClaim:   "tenant_id",
+		JWTCustomerClaim: "sub",
+		JWTRolesClaim:    "roles",
+
+		PIIRedaction: true,
+
+		GuardEnabled: true,
+		GuardMode:    "block",
+
+		GroundingWeight: 0.5,
+
+		LanguageDetectMinConfidence: 0.8,
+
+		IntentEnabled: true,
+
+		FAQMatchThreshold: DefaultFAQThreshold,
+
+		BatchMaxItems:    100,
+		BatchConcurrency: 4,
+		BatchRateWaitMs:  5000,
+
+		JobsWorkers:             4,
+		JobsQueueSize:           1000,
+		JobsMaxAttempts:         3,
+		JobsRetentionHours:      24,
+		JobsCallbackTimeoutMs:   5000,
+		JobsCallbackMaxAttempts: 5,
+
+		ToolTimeoutMs:     5000,
+		ToolMaxIterations: llm.DefaultMaxToolIterations,
+
+		OutputGuardEnabled: true,
+
+		ShadowSampleRate:     1.0,
+		ShadowMaxConcurrency: 4,
+		ShadowMaxResults:     1000,
+
+		ConfigReloadSeconds: 10,
 	}
+}
 
-	env := os.Getenv("APP_ENV")
-	if env == "" {
-		env = "development"
+// Load builds the configuration from defaults, the file named by CONFIG_FILE
+// (if set) and environment variables, in that order. Unparseable or invalid
+// values are returned as errors instead of being replaced by defaults.
+func Load() (Config, error) {
+	return LoadFile(os.Getenv("CONFIG_FILE"))
+}
+
+// LoadFile is Load with an explicit config file path; an empty path skips the file.
+func LoadFile(path string) (Config, error) {
+	cfg := Defaults()
+	if path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return Config{}, err
+		}
+		cfg.File = path
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return Config{}, err
 	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) applyEnv() error {
+	env := &envReader{}
+
+	env.str("PORT", &c.Port)
+	env.str("APP_ENV", &c.Env)
 
 	// LLM Configuration
-	llmProvider := os.Getenv("LLM_PROVIDER")
-	if llmProvider == "" {
-		llmProvider = "openai"
+	env.str("LLM_PROVIDER", &c.LLMProvider)
+	env.str("LLM_API_KEY", &c.LLMAPIKey)
+	env.str("LLM_BASE_URL", &c.LLMBaseURL)
+	env.str("LLM_DEFAULT_MODEL", &c.LLMDefaultModel)
+	env.int("LLM_MAX_TOKENS", &c.LLMMaxTokens)
+	env.float("LLM_TEMPERATURE", &c.LLMTemperature)
+	env.int("LLM_TIMEOUT", &c.LLMTimeout)
+	env.int("LLM_MAX_RETRIES", &c.LLMMaxRetries)
+	env.int("LLM_RETRY_DELAY", &c.LLMRetryDelay)
+	env.str("LLM_CONFIDENCE_STRATEGY", &c.LLMConfidenceStrategy)
+	env.bool("LLM_STRUCTURED_OUTPUT", &c.LLMStructuredOutput)
+
+	// Reliability & cost control (Phase 5)
+	env.float("TENANT_RATE_LIMIT_PER_SEC", &c.TenantRateLimitPerSec)
+	env.int("TENANT_RATE_LIMIT_BURST", &c.TenantRateLimitBurst)
+	env.int("RESPONSE_CACHE_TTL_SECONDS", &c.ResponseCacheTTLSeconds)
+	env.int("IDEMPOTENCY_WINDOW_SECONDS", &c.IdempotencyWindowSeconds)
+	env.int("TOKEN_USAGE_WINDOW_HOURS", &c.TokenUsageWindowHours)
+	env.int("TENANT_TOKEN_BUDGET", &c.TenantTokenBudget)
+
+	// Answer policy
+	env.float("CONFIDENCE_THRESHOLD", &c.ConfidenceThreshold)
+	env.str("FALLBACK_MESSAGE", &c.FallbackMessage)
+	env.str("SYSTEM_PROMPT", &c.SystemPrompt)
+
+	env.str("TENANT_REGISTRY_FILE", &c.TenantRegistryFile)
+	env.str("PROMPT_STORE_FILE", &c.PromptStoreFile)
+
+	// Authentication
+	env.bool("AUTH_ENABLED", &c.AuthEnabled)
+	env.str("ADMIN_API_KEY", &c.AdminAPIKey)
+	env.str("API_KEY_STORE_FILE", &c.APIKeyStoreFile)
+	env.str("JWT_JWKS_FILE", &c.JWTJWKSFile)
+	env.str("JWT_JWKS_URL", &c.JWTJWKSURL)
+	env.str("JWT_ISSUER", &c.JWTIssuer)
+	env.str("JWT_AUDIENCE", &c.JWTAudience)
+	env.str("JWT_TENANT_CLAIM", &c.JWTTenantClaim)
+	env.str("JWT_CUSTOMER_CLAIM", &c.JWTCustomerClaim)
+	env.str("JWT_ROLES_CLAIM", &c.JWTRolesClaim)
+
+	// PII redaction
+	env.bool("PII_REDACTION", &c.PIIRedaction)
+	env.list("PII_KINDS", &c.PIIKinds)
+
+	// Prompt injection guard
+	env.bool("GUARD_ENABLED", &c.GuardEnabled)
+	env.str("GUARD_MODE", &c.GuardMode)
+	env.bool("GUARD_LLM_CLASSIFIER", &c.GuardLLMClassifier)
+	env.str("GUARD_CLASSIFIER_MODEL", &c.GuardClassifierModel)
+
+	// Groundedness
+	env.float("GROUNDING_WEIGHT", &c.GroundingWeight)
+	env.bool("GROUNDING_JUDGE", &c.GroundingJudge)
+	env.str("GROUNDING_JUDGE_MODEL", &c.GroundingJudgeModel)
+
+	env.float("LANGUAGE_DETECT_MIN_CONFIDENCE", &c.LanguageDetectMinConfidence)
+
+	env.str("TRANSLATION_MODEL", &c.TranslationModel)
+
+	// Intent classification
+	env.bool("INTENT_ENABLED", &c.IntentEnabled)
+	env.bool("INTENT_LLM_CLASSIFIER", &c.IntentLLMClassifier)
+	env.str("INTENT_CLASSIFIER_MODEL", &c.IntentClassifierModel)
+
+	env.float("FAQ_MATCH_THRESHOLD", &c.FAQMatchThreshold)
+
+	// Batch queries
+	env.int("BATCH_MAX_ITEMS", &c.BatchMaxItems)
+	env.int("BATCH_CONCURRENCY", &c.BatchConcurrency)
+	env.int("BATCH_RATE_WAIT_MS", &c.BatchRateWaitMs)
+
+	// Asynchronous jobs
+	env.int("JOBS_WORKERS", &c.JobsWorkers)
+	env.int("JOBS_QUEUE_SIZE", &c.JobsQueueSize)
+	env.int("JOBS_MAX_ATTEMPTS", &c.JobsMaxAttempts)
+	env.int("JOBS_RETENTION_HOURS", &c.JobsRetentionHours)
+	env.str("JOBS_FILE", &c.JobsFile)
+	env.int("JOBS_CALLBACK_TIMEOUT_MS", &c.JobsCallbackTimeoutMs)
+	env.int("JOBS_CALLBACK_MAX_ATTEMPTS", &c.JobsCallbackMaxAttempts)
+	env.str("JOBS_CALLBACK_SECRET", &c.JobsCallbackSecret)
+	env.list("JOBS_CALLBACK_ALLOWED_HOSTS", &c.JobsCallbackAllowedHosts)
+
+	// Tool calling
+	env.int("TOOL_TIMEOUT_MS", &c.ToolTimeoutMs)
+	env.int("TOOL_MAX_ITERATIONS", &c.ToolMaxIterations)
+	env.list("TOOL_ALLOWED_HOSTS", &c.ToolAllowedHosts)
+
+	// Confidence calibration
+	env.str("CALIBRATION_FILE", &c.CalibrationFile)
+	env.str("CALIBRATION_SAMPLES_FILE", &c.CalibrationSamplesFile)
+
+	env.bool("OUTPUT_GUARD_ENABLED", &c.OutputGuardEnabled)
+
+	// Shadow traffic
+	env.str("SHADOW_MODEL", &c.ShadowModel)
+	env.str("SHADOW_LLM_BASE_URL", &c.ShadowLLMBaseURL)
+	env.str("SHADOW_LLM_API_KEY", &c.ShadowLLMAPIKey)
+	env.float("SHADOW_SAMPLE_RATE", &c.ShadowSampleRate)
+	env.int("SHADOW_MAX_CONCURRENCY", &c.ShadowMaxConcurrency)
+	env.int("SHADOW_MAX_RESULTS", &c.ShadowMaxResults)
+
+	env.int("CONFIG_RELOAD_SECONDS", &c.ConfigReloadSeconds)
+
+	return errors.Join(env.errs...)
+}
+
+// Validate checks every value and reports all problems at once.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
 	}
 
-	llmAPIKey := os.Getenv("LLM_API_KEY")
-	llmBaseURL := os.Getenv("LLM_BASE_URL")
-	llmDefaultModel := os.Getenv("LLM_DEFAULT_MODEL")
-	if llmDefaultModel == "" {
-		llmDefaultModel = "gpt-3.5-turbo"
+	port, err := strconv.Atoi(c.Port)
+	check(err == nil && port > 0 && port < 65536, "port: %q is not a valid TCP port", c.Port)
+	check(c.LLMProvider != "", "llm_provider: must not be empty")
+	check(c.LLMDefaultModel != "", "llm_default_model: must not be empty")
+	check(c.LLMMaxTokens > 0, "llm_max_tokens: must be > 0, got %d", c.LLMMaxTokens)
+	check(c.LLMTemperature >= 0 && c.LLMTemperature <= 2, "llm_temperature: must be between 0 and 2, got %v", c.LLMTemperature)
+	if _, err := llm.ParseConfidenceStrategy(c.LLMConfidenceStrategy); err != nil {
+		errs = append(errs, fmt.Errorf("llm_confidence_strategy: %w", err))
+	}
+	check(c.LLMTimeout > 0, "llm_timeout: must be > 0, got %d", c.LLMTimeout)
+	check(c.LLMMaxRetries >= 0, "llm_max_retries: must be >= 0, got %d", c.LLMMaxRetries)
+	check(c.LLMRetryDelay >= 0, "llm_retry_delay: must be >= 0, got %d", c.LLMRetryDelay)
+	for model, p := range c.LLMModelPrices {
+		check(p.PromptPer1K >= 0 && p.CompletionPer1K >= 0, "llm_model_prices[%s]: prices must be >= 0", model)
 	}
 
-	llmMaxTokens := getIntEnv("LLM_MAX_TOKENS", 500)
-	llmTemperature := getFloatEnv("LLM_TEMPERATURE", 0.7)
-	llmTimeout := getIntEnv("LLM_TIMEOUT", 30)
-	llmMaxRetries := getIntEnv("LLM_MAX_RETRIES", 3)
-	llmRetryDelay := getIntEnv("LLM_RETRY_DELAY", 100)
+	check(c.TenantRateLimitPerSec > 0, "tenant_rate_limit_per_sec: must be > 0, got %v", c.TenantRateLimitPerSec)
+	check(c.TenantRateLimitBurst > 0, "tenant_rate_limit_burst: must be > 0, got %d", c.TenantRateLimitBurst)
+	check(c.ResponseCacheTTLSeconds > 0, "response_cache_ttl_seconds: must be > 0, got %d", c.ResponseCacheTTLSeconds)
+	check(c.IdempotencyWindowSeconds >= 0, "idempotency_window_seconds: must be >= 0, got %d", c.IdempotencyWindowSeconds)
+	check(c.TokenUsageWindowHours > 0, "token_usage_window_hours: must be > 0, got %d", c.TokenUsageWindowHours)
+	check(c.TenantTokenBudget >= 0, "tenant_token_budget: must be >= 0, got %d", c.TenantTokenBudget)
 
-	// Reliability & cost control (Phase 5)
-	tenantRateLimitPerSec := getFloatEnv("TENANT_RATE_LIMIT_PER_SEC", 5.0)
-	tenantRateLimitBurst := getIntEnv("TENANT_RATE_LIMIT_BURST", 10)
-	responseCacheTTLSeconds := getIntEnv("RESPONSE_CACHE_TTL_SECONDS", 300)
-	tokenUsageWindowHours := getIntEnv("TOKEN_USAGE_WINDOW_HOURS", 24)
-	tenantTokenBudget := getIntEnv("TENANT_TOKEN_BUDGET", 0) // 0 = disabled
+	check(c.ConfidenceThreshold >= 0 && c.ConfidenceThreshold <= 1, "confidence_threshold: must be between 0 and 1, got %v", c.ConfidenceThreshold)
+	check(c.FallbackMessage != "", "fallback_message: must not be empty")
+	if _, err := pii.ParseKinds(c.PIIKinds); err != nil {
+		errs = append(errs, fmt.Errorf("pii_kinds: %w", err))
+	}
+	check(c.GuardMode == "block" || c.GuardMode == "fallback", "guard_mode: must be block or fallback, got %q", c.GuardMode)
+	check(c.GroundingWeight >= 0 && c.GroundingWeight <= 1, "grounding_weight: must be between 0 and 1, got %v", c.GroundingWeight)
+	check(c.LanguageDetectMinConfidence >= 0 && c.LanguageDetectMinConfidence <= 1, "language_detect_min_confidence: must be between 0 and 1, got %v", c.LanguageDetectMinConfidence)
+	check(c.FAQMatchThreshold > 0 && c.FAQMatchThreshold <= 1, "faq_match_threshold: must be > 0 and <= 1, got %v", c.FAQMatchThreshold)
+	check(c.BatchMaxItems > 0, "batch_max_items: must be > 0, got %d", c.BatchMaxItems)
+	check(c.BatchConcurrency > 0, "batch_concurrency: must be > 0, got %d", c.BatchConcurrency)
+	check(c.BatchRateWaitMs >= 0, "batch_rate_wait_ms: must be >= 0, got %d", c.BatchRateWaitMs)
+	check(c.JobsWorkers > 0, "jobs_workers: must be > 0, got %d", c.JobsWorkers)
+	check(c.JobsQueueSize > 0, "jobs_queue_size: must be > 0, got %d", c.JobsQueueSize)
+	check(c.JobsMaxAttempts > 0, "jobs_max_attempts: must be > 0, got %d", c.JobsMaxAttempts)
+	check(c.JobsRetentionHours > 0, "jobs_retention_hours: must be > 0, got %d", c.JobsRetentionHours)
+	check(c.JobsCallbackTimeoutMs > 0, "jobs_callback_timeout_ms: must be > 0, got %d", c.JobsCallbackTimeoutMs)
+	check(c.JobsCallbackMaxAttempts > 0, "jobs_callback_max_attempts: must be > 0, got %d", c.JobsCallbackMaxAttempts)
+	check(c.ToolTimeoutMs > 0, "tool_timeout_ms: must be > 0, got %d", c.ToolTimeoutMs)
+	check(c.ToolMaxIterations > 0, "tool_max_iterations: must be > 0, got %d", c.ToolMaxIterations)
+	check(c.ShadowSampleRate >= 0 && c.ShadowSampleRate <= 1, "shadow_sample_rate: must be between 0 and 1, got %v", c.ShadowSampleRate)
+	check(c.ShadowMaxConcurrency > 0, "shadow_max_concurrency: must be > 0, got %d", c.ShadowMaxConcurrency)
+	check(c.ShadowMaxResults > 0, "shadow_max_results: must be > 0, got %d", c.ShadowMaxResults)
+	check(c.ConfigReloadSeconds >= 0, "config_reload_seconds: must be >= 0, got %d", c.ConfigReloadSeconds)
 
-	return Config{
-		Port: port,
-		Env:  env,
-
-		LLMProvider:     llmProvider,
-		LLMAPIKey:       llmAPIKey,
-		LLMBaseURL:      llmBaseURL,
-		LLMDefaultModel: llmDefaultModel,
-		LLMMaxTokens:    llmMaxTokens,
-		LLMTemperature:  llmTemperature,
-		LLMTimeout:      llmTimeout,
-		LLMMaxRetries:   llmMaxRetries,
-		LLMRetryDelay:   llmRetryDelay,
-
-		TenantRateLimitPerSec:   tenantRateLimitPerSec,
-		TenantRateLimitBurst:    tenantRateLimitBurst,
-		ResponseCacheTTLSeconds: responseCacheTTLSeconds,
-		TokenUsageWindowHours:   tokenUsageWindowHours,
-		TenantTokenBudget:       tenantTokenBudget,
+	seen := map[string]bool{}
+	for i, t := range c.Tenants {
+		if t.ID == "" {
+			errs = append(errs, fmt.Errorf("tenants[%d]: id is required", i))
+			continue
+		}
+		check(!seen[t.ID], "tenants[%d]: duplicate id %q", i, t.ID)
+		seen[t.ID] = true
+		for _, l := range t.Languages {
+			check(SupportedLanguages[l], "tenants[%d]: unsupported language %q", i, l)
+		}
+		if err := t.Config.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("tenants[%d]: %w", i, err))
+		}
 	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid config: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// ModelPrice is what a model charges per 1,000 prompt and completion tokens,
+// in any currency as long as every model uses the same one.
+type ModelPrice struct {
+	PromptPer1K     float64 `yaml:"prompt_per_1k" json:"prompt_per_1k"`
+	CompletionPer1K float64 `yaml:"completion_per_1k" json:"completion_per_1k"`
 }
 
-func getIntEnv(key string, defaultValue int) int {
+// Cost prices a request's tokens.
+func (p ModelPrice) Cost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*p.PromptPer1K + float64(completionTokens)*p.CompletionPer1K) / 1000
+}
+
+// envReader applies environment overrides, collecting parse errors instead of
+// silently keeping defaults. Unset or empty variables leave the value untouched.
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) str(key string, dst *string) {
+	if value := os.Getenv(key); value != "" {
+		*dst = value
+	}
+}
+
+func (r *envReader) int(key string, dst *int) {
 	value := os.Getenv(key)
 	if value == "" {
-		return defaultValue
+		return
 	}
 	intValue, err := strconv.Atoi(value)
 	if err != nil {
-		return defaultValue
+		r.errs = append(r.errs, fmt.Errorf("%s: %q is not an integer", key, value))
+		return
 	}
-	return intValue
+	*dst = intValue
 }
 
-func getFloatEnv(key string, defaultValue float64) float64 {
+func (r *envReader) float(key string, dst *float64) {
 	value := os.Getenv(key)
 	if value == "" {
-		return defaultValue
+		return
 	}
 	floatValue, err := strconv.ParseFloat(value, 64)
 	if err != nil {
-		return defaultValue
+		r.errs = append(r.errs, fmt.Errorf("%s: %q is not a number", key, value))
+		return
+	}
+	*dst = floatValue
+}
+
+func (r *envReader) bool(key string, dst *bool) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	boolValue, err := strconv.ParseBool(value)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s: %q is not a boolean", key, value))
+		return
 	}
-	return floatValue
+	*dst = boolValue
+}
+
+func (r *envReader) list(key string, dst *[]string) {
+	value := os.Getenv(key)
+	if value == "" {
+		return
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*dst = items
+}
+
+func errInvalid(msg string) error {
+	return errors.New("invalid config: " + msg)
 }
@@ -2,7 +2,11 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/locale"
 )
 
 func TestLoad(t *testing.T) {
@@ -35,7 +39,10 @@ func TestLoad(t *testing.T) {
 	os.Unsetenv("APP_ENV")
 	os.Unsetenv("LLM_PROVIDER")
 
-	cfg := Load()
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
 	if cfg.Port != "8080" {
 		t.Errorf("Load() Port = %s, want 8080", cfg.Port)
 	}
@@ -51,7 +58,10 @@ func TestLoad(t *testing.T) {
 	os.Setenv("APP_ENV", "production")
 	os.Setenv("LLM_PROVIDER", "anthropic")
 
-	cfg = Load()
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
 	if cfg.Port != "9000" {
 		t.Errorf("Load() Port = %s, want 9000", cfg.Port)
 	}
@@ -64,3 +74,215 @@ func TestLoad(t *testing.T) {
 }
 
 
+
+func TestTenantConfig_Apply(t *testing.T) {
+	temperature := 0.7
+	base := TenantSettings{
+		RateLimitPerSec:     5,
+		RateLimitBurst:      10,
+		Model:               "gpt-3.5-turbo",
+		Temperature:         &temperature,
+		ConfidenceThreshold: 0.7,
+		FallbackMessage:     DefaultFallbackMessage,
+	}
+	rate := 50.0
+	threshold := 0.5
+	budget := 0
+
+	got := TenantConfig{
+		RateLimitPerSec:     &rate,
+		TokenBudget:         &budget,
+		Model:               "gpt-4o",
+		ConfidenceThreshold: &threshold,
+	}.Apply(base)
+
+	if got.RateLimitPerSec != 50 || got.RateLimitBurst != 10 {
+		t.Errorf("Apply() rate limits = %v/%v, want 50/10", got.RateLimitPerSec, got.RateLimitBurst)
+	}
+	if got.Model != "gpt-4o" || got.Temperature == nil || *got.Temperature != 0.7 {
+		t.Errorf("Apply() model = %s temperature = %v, want gpt-4o 0.7", got.Model, got.Temperature)
+	}
+	if got.ConfidenceThreshold != 0.5 || got.FallbackMessage != DefaultFallbackMessage {
+		t.Errorf("Apply() threshold = %v fallback = %q", got.ConfidenceThreshold, got.FallbackMessage)
+	}
+
+	base.CannedAnswers = map[string]string{"greeting": "Hi!"}
+	got = TenantConfig{CannedAnswers: map[string]string{"refund": "Refunds take 5 days."}}.Apply(base)
+	if got.CannedAnswers["greeting"] != "Hi!" || got.CannedAnswers["refund"] != "Refunds take 5 days." {
+		t.Errorf("Apply() canned answers = %v, want merged with base", got.CannedAnswers)
+	}
+}
+
+func TestTenantConfig_Validate(t *testing.T) {
+	bad := 1.5
+	if err := (TenantConfig{ConfidenceThreshold: &bad}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for threshold > 1")
+	}
+	negative := -1
+	if err := (TenantConfig{TokenBudget: &negative}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for negative budget")
+	}
+	if err := (TenantConfig{PIIKinds: []string{"email", "passport"}}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for unknown pii kind")
+	}
+	badTool := ToolConfig{Name: "order_status", URL: "file:///etc/passwd"}
+	if err := (TenantConfig{Tools: []ToolConfig{badTool}}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for non-http tool url")
+	}
+	badParam := ToolConfig{Name: "order_status", URL: "https://shop.example/orders/{id}", Parameters: []ToolParam{{Name: "id", Type: "uuid"}}}
+	if err := (TenantConfig{Tools: []ToolConfig{badParam}}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for unknown tool parameter type")
+	}
+	if err := (TenantConfig{IntentRoutes: map[string]string{"refund": "ignore"}}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for unknown intent route")
+	}
+	if err := (TenantConfig{CannedAnswers: map[string]string{"shipping": "..."}}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for canned answer of unknown intent")
+	}
+	dupFAQ := []FAQ{{ID: "refunds", Question: "Refund policy?", Answer: "30 days."}, {ID: "refunds", Question: "Returns?", Answer: "30 days."}}
+	if err := (TenantConfig{FAQs: dupFAQ}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for duplicate faq id")
+	}
+	if err := (TenantConfig{FAQs: []FAQ{{ID: "refunds", Question: "Refund policy?"}}}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for faq without answer")
+	}
+	if err := (TenantConfig{Translations: map[string]string{"ms": "th"}}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for translation into an unsupported language")
+	}
+	if err := (TenantConfig{Translations: map[string]string{"ms": "id", "vi": "en"}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil for valid translations", err)
+	}
+	if err := (TenantConfig{FallbackMessages: map[string]string{"portuguese": "..."}}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for invalid fallback language tag")
+	}
+	if err := (TenantConfig{FallbackMessages: map[string]string{"pt_br": "Fale conosco."}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil for valid fallback messages", err)
+	}
+	if err := (TenantConfig{Messages: map[string]map[string]string{"not_found": {"en": "..."}}}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for unknown message key")
+	}
+	if err := (TenantConfig{Messages: map[string]map[string]string{"fallback": {"en": "Call {support_phone}."}}}).Validate(); err == nil {
+		t.Error("Validate() error = nil, want error for undefined message placeholder")
+	}
+	if err := (TenantConfig{}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v, want nil for empty overrides", err)
+	}
+}
+
+func TestTenantSettings_FallbackFor(t *testing.T) {
+	settings := TenantConfig{
+		FallbackMessages: map[string]string{"pt-br": "Fale com o suporte.", "ms": "Hubungi kami."},
+	}.Apply(BuiltinTenantSettings())
+
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"pt-BR", "Fale com o suporte."},
+		{"ms-MY", "Hubungi kami."},
+		{"en", DefaultFallbackMessage},
+		{"xx", DefaultFallbackMessage},
+	}
+	for _, tt := range tests {
+		if got := settings.FallbackFor(tt.tag); got != tt.want {
+			t.Errorf("FallbackFor(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+	if got := settings.FallbackFor("id"); got == DefaultFallbackMessage {
+		t.Error("FallbackFor(id) = English default, want the Indonesian locale message")
+	}
+
+	settings = TenantConfig{
+		Messages:    map[string]map[string]string{"rate_limited": {"en": "Busy. Call {support_phone}."}},
+		MessageVars: map[string]string{"support_phone": "+62 21 555 0100"},
+	}.Apply(settings)
+	if got := settings.Message("rate_limited", "en-GB"); got != "Busy. Call +62 21 555 0100." {
+		t.Errorf("Message(rate_limited, en-GB) = %q", got)
+	}
+	if got := settings.FallbackFor("pt-BR"); got != "Fale com o suporte." {
+		t.Errorf("FallbackFor(pt-BR) = %q, want fallback_messages kept after merging messages", got)
+	}
+
+	settings.FallbackMessage = "Please email help@shop.example."
+	if got := settings.FallbackFor("id"); got != settings.FallbackMessage {
+		t.Errorf("FallbackFor(id) = %q, want the tenant's custom message", got)
+	}
+}
+
+func TestSupportedLanguagesHaveLocales(t *testing.T) {
+	for lang := range SupportedLanguages {
+		if _, ok := locale.Lookup(lang); !ok {
+			t.Errorf("supported language %q has no locale", lang)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := `
+llm_default_model: gpt-4o-mini
+tenant_rate_limit_per_sec: 20
+confidence_threshold: 0.6
+tenants:
+  - id: shop-enterprise
+    name: Enterprise Shop
+    languages: [en]
+    config:
+      model: gpt-4o
+      rate_limit_burst: 200
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	t.Setenv("TENANT_RATE_LIMIT_PER_SEC", "25")
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if cfg.LLMDefaultModel != "gpt-4o-mini" || cfg.ConfidenceThreshold != 0.6 {
+		t.Errorf("LoadFile() model = %s threshold = %v", cfg.LLMDefaultModel, cfg.ConfidenceThreshold)
+	}
+	if cfg.TenantRateLimitPerSec != 25 {
+		t.Errorf("LoadFile() TenantRateLimitPerSec = %v, want env override 25", cfg.TenantRateLimitPerSec)
+	}
+	if cfg.LLMMaxTokens != 500 {
+		t.Errorf("LoadFile() LLMMaxTokens = %d, want default 500", cfg.LLMMaxTokens)
+	}
+	if len(cfg.Tenants) != 1 || cfg.Tenants[0].Config.Model != "gpt-4o" || *cfg.Tenants[0].Config.RateLimitBurst != 200 {
+		t.Errorf("LoadFile() Tenants = %+v", cfg.Tenants)
+	}
+	if !cfg.Tenants[0].IsEnabled() {
+		t.Error("LoadFile() tenant enabled = false, want default true")
+	}
+}
+
+func TestLoadFile_StrictValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "unknown key", content: "llm_default_modle: gpt-4o\n", wantErr: "llm_default_modle"},
+		{name: "out of range", content: "confidence_threshold: 1.5\n", wantErr: "confidence_threshold"},
+		{name: "unsupported tenant language", content: "tenants:\n  - id: shop-1\n    languages: [xx]\n", wantErr: "unsupported language"},
+		{name: "bad env value", content: "", env: map[string]string{"LLM_MAX_TOKENS": "lots"}, wantErr: "LLM_MAX_TOKENS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := LoadFile(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadFile() error = %v, want mention of %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/goccy/go-yaml"
+)
+
+// TenantSpec declares a tenant in the config file.
+type TenantSpec struct {
+	ID        string       `yaml:"id" json:"id"`
+	Name      string       `yaml:"name" json:"name,omitempty"`
+	Enabled   *bool        `yaml:"enabled" json:"enabled,omitempty"` // Defaults to true
+	Languages []string     `yaml:"languages" json:"languages,omitempty"`
+	Config    TenantConfig `yaml:"config" json:"config"`
+}
+
+// IsEnabled reports whether the tenant should accept requests.
+func (s TenantSpec) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// readFile layers a YAML config file over c. Unknown keys are rejected so
+// typos fail startup instead of being ignored.
+func (c *Config) readFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.UnmarshalWithOptions(data, c, yaml.DisallowUnknownField()); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return nil
+}
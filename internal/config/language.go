@@ -1,5 +1,6 @@
 package config
 
+// SupportedLanguages is the platform-wide set of languages the tenant registry accepts.
 var SupportedLanguages = map[string]bool{
 	"en": true,
 	"id": true,
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+)
+
+// Reloader re-reads the config file on SIGHUP (delivered on the signals
+// channel) or when the file's modification time changes, and hands each valid
+// configuration to apply. Invalid files are logged and the running
+// configuration is kept.
+type Reloader struct {
+	path     string
+	interval time.Duration
+	signals  <-chan os.Signal
+	apply    func(Config) error
+
+	modTime time.Time
+}
+
+// NewReloader creates a reloader for path. An interval of 0 disables polling.
+func NewReloader(path string, interval time.Duration, signals <-chan os.Signal, apply func(Config) error) *Reloader {
+	r := &Reloader{path: path, interval: interval, signals: signals, apply: apply}
+	if info, err := os.Stat(path); err == nil {
+		r.modTime = info.ModTime()
+	}
+	return r
+}
+
+// Run blocks until ctx is cancelled.
+func (r *Reloader) Run(ctx context.Context) {
+	var tick <-chan time.Time
+	if r.interval > 0 && r.path != "" {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.signals:
+			r.reload("signal")
+		case <-tick:
+			info, err := os.Stat(r.path)
+			if err != nil || info.ModTime().Equal(r.modTime) {
+				continue
+			}
+			r.modTime = info.ModTime()
+			r.reload("file_changed")
+		}
+	}
+}
+
+func (r *Reloader) reload(trigger string) {
+	cfg, err := LoadFile(r.path)
+	if err != nil {
+		logger.Error("config reload rejected; keeping current config", map[string]interface{}{
+			"error":   err.Error(),
+			"trigger": trigger,
+		})
+		return
+	}
+	if err := r.apply(cfg); err != nil {
+		logger.Error("config reload failed; keeping current config", map[string]interface{}{
+			"error":   err.Error(),
+			"trigger": trigger,
+		})
+		return
+	}
+	logger.Info("config reloaded", map[string]interface{}{
+		"file":    r.path,
+		"trigger": trigger,
+	})
+}
+
+// RestartRequired lists settings that differ between prev and next but are only
+// read at startup, so operators know a reload did not apply them.
+func RestartRequired(prev, next Config) []string {
+	var fields []string
+	if prev.Port != next.Port {
+		fields = append(fields, "port")
+	}
+	if prev.LLMProvider != next.LLMProvider || prev.LLMAPIKey != next.LLMAPIKey || prev.LLMBaseURL != next.LLMBaseURL ||
+		prev.LLMTimeout != next.LLMTimeout || prev.LLMMaxRetries != next.LLMMaxRetries || prev.LLMRetryDelay != next.LLMRetryDelay ||
+		prev.LLMConfidenceStrategy != next.LLMConfidenceStrategy || prev.LLMStructuredOutput != next.LLMStructuredOutput {
+		fields = append(fields, "llm client")
+	}
+	if prev.ResponseCacheTTLSeconds != next.ResponseCacheTTLSeconds {
+		fields = append(fields, "response_cache_ttl_seconds")
+	}
+	if prev.IdempotencyWindowSeconds != next.IdempotencyWindowSeconds {
+		fields = append(fields, "idempotency_window_seconds")
+	}
+	if prev.TokenUsageWindowHours != next.TokenUsageWindowHours {
+		fields = append(fields, "token_usage_window_hours")
+	}
+	if prev.AuthEnabled != next.AuthEnabled || prev.AdminAPIKey != next.AdminAPIKey || prev.APIKeyStoreFile != next.APIKeyStoreFile ||
+		prev.JWTJWKSFile != next.JWTJWKSFile || prev.JWTJWKSURL != next.JWTJWKSURL ||
+		prev.JWTIssuer != next.JWTIssuer || prev.JWTAudience != next.JWTAudience ||
+		prev.JWTTenantClaim != next.JWTTenantClaim || prev.JWTCustomerClaim != next.JWTCustomerClaim || prev.JWTRolesClaim != next.JWTRolesClaim {
+		fields = append(fields, "authentication")
+	}
+	if prev.GuardEnabled != next.GuardEnabled || prev.GuardMode != next.GuardMode ||
+		prev.GuardLLMClassifier != next.GuardLLMClassifier || prev.GuardClassifierModel != next.GuardClassifierModel ||
+		prev.OutputGuardEnabled != next.OutputGuardEnabled {
+		fields = append(fields, "guard")
+	}
+	if prev.GroundingWeight != next.GroundingWeight || prev.GroundingJudge != next.GroundingJudge || prev.GroundingJudgeModel != next.GroundingJudgeModel {
+		fields = append(fields, "grounding")
+	}
+	if prev.LanguageDetectMinConfidence != next.LanguageDetectMinConfidence {
+		fields = append(fields, "language_detect_min_confidence")
+	}
+	if prev.TranslationModel != next.TranslationModel {
+		fields = append(fields, "translation_model")
+	}
+	if prev.IntentEnabled != next.IntentEnabled || prev.IntentLLMClassifier != next.IntentLLMClassifier || prev.IntentClassifierModel != next.IntentClassifierModel {
+		fields = append(fields, "intent")
+	}
+	if prev.BatchMaxItems != next.BatchMaxItems || prev.BatchConcurrency != next.BatchConcurrency || prev.BatchRateWaitMs != next.BatchRateWaitMs {
+		fields = append(fields, "batch")
+	}
+	if prev.JobsWorkers != next.JobsWorkers || prev.JobsQueueSize != next.JobsQueueSize || prev.JobsMaxAttempts != next.JobsMaxAttempts ||
+		prev.JobsRetentionHours != next.JobsRetentionHours || prev.JobsFile != next.JobsFile ||
+		prev.JobsCallbackTimeoutMs != next.JobsCallbackTimeoutMs || prev.JobsCallbackMaxAttempts != next.JobsCallbackMaxAttempts ||
+		prev.JobsCallbackSecret != next.JobsCallbackSecret ||
+		strings.Join(prev.JobsCallbackAllowedHosts, ",") != strings.Join(next.JobsCallbackAllowedHosts, ",") {
+		fields = append(fields, "jobs")
+	}
+	if prev.ToolTimeoutMs != next.ToolTimeoutMs || prev.ToolMaxIterations != next.ToolMaxIterations ||
+		strings.Join(prev.ToolAllowedHosts, ",") != strings.Join(next.ToolAllowedHosts, ",") {
+		fields = append(fields, "tools")
+	}
+	if prev.CalibrationFile != next.CalibrationFile || prev.CalibrationSamplesFile != next.CalibrationSamplesFile {
+		fields = append(fields, "calibration")
+	}
+	if prev.ShadowModel != next.ShadowModel || prev.ShadowLLMBaseURL != next.ShadowLLMBaseURL || prev.ShadowLLMAPIKey != next.ShadowLLMAPIKey ||
+		prev.ShadowSampleRate != next.ShadowSampleRate || prev.ShadowMaxConcurrency != next.ShadowMaxConcurrency || prev.ShadowMaxResults != next.ShadowMaxResults {
+		fields = append(fields, "shadow")
+	}
+	if prev.TenantRegistryFile != next.TenantRegistryFile {
+		fields = append(fields, "tenant_registry_file")
+	}
+	if prev.PromptStoreFile != next.PromptStoreFile {
+		fields = append(fields, "prompt_store_file")
+	}
+	return fields
+}
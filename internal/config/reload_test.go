@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReloader_Run(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Chtimes() error = %v", err)
+		}
+	}
+	start := time.Now().Add(-time.Hour)
+	write("confidence_threshold: 0.6\n", start)
+
+	applied := make(chan Config, 4)
+	signals := make(chan os.Signal, 1)
+	r := NewReloader(path, 10*time.Millisecond, signals, func(cfg Config) error {
+		applied <- cfg
+		return nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	next := func() (Config, bool) {
+		select {
+		case cfg := <-applied:
+			return cfg, true
+		case <-time.After(200 * time.Millisecond):
+			return Config{}, false
+		}
+	}
+
+	// An unchanged file is not re-applied by polling
+	if cfg, ok := next(); ok {
+		t.Fatalf("apply called without a change: %+v", cfg.ConfidenceThreshold)
+	}
+
+	signals <- os.Interrupt
+	if cfg, ok := next(); !ok || cfg.ConfidenceThreshold != 0.6 {
+		t.Fatalf("after signal applied = %v, %v; want threshold 0.6", cfg.ConfidenceThreshold, ok)
+	}
+
+	write("confidence_threshold: 1.5\n", start.Add(time.Minute))
+	if cfg, ok := next(); ok {
+		t.Fatalf("invalid file applied: %v", cfg.ConfidenceThreshold)
+	}
+
+	write("confidence_threshold: 0.8\n", start.Add(2*time.Minute))
+	if cfg, ok := next(); !ok || cfg.ConfidenceThreshold != 0.8 {
+		t.Fatalf("after file change applied = %v, %v; want threshold 0.8", cfg.ConfidenceThreshold, ok)
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	prev := Config{Port: "8080", ConfidenceThreshold: 0.7}
+	next := prev
+	next.ConfidenceThreshold = 0.8
+	if got := RestartRequired(prev, next); len(got) != 0 {
+		t.Errorf("RestartRequired(hot field) = %v, want none", got)
+	}
+
+	next.Port = "9090"
+	next.JobsCallbackAllowedHosts = []string{"hooks.example.com"}
+	if got, want := RestartRequired(prev, next), []string{"port", "jobs"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RestartRequired() = %v, want %v", got, want)
+	}
+}
@@ -1,6 +1,510 @@
 package config
 
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/intent"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/locale"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/messages"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/pii"
+)
+
+// Tenants seeds the tenant registry at startup. Tenants added at runtime via
+// the admin API are kept in the registry, not here.
 var Tenants = map[string]bool{
 	"shop-123": true,
 	"shop-456": true,
 }
+
+// DefaultFallbackMessage is returned when an answer cannot be given confidently.
+const DefaultFallbackMessage = messages.DefaultFallback
+
+// TenantConfig overrides global settings for a single tenant.
+// Nil pointers and empty strings inherit the global value.
+type TenantConfig struct {
+	RateLimitPerSec     *float64 `json:"rate_limit_per_sec,omitempty"`
+	RateLimitBurst      *int     `json:"rate_limit_burst,omitempty"`
+	TokenBudget         *int     `json:"token_budget,omitempty"` // 0 disables the budget for this tenant
+	Model               string   `json:"model,omitempty"`
+	MaxTokens           *int     `json:"max_tokens,omitempty"`
+	Temperature         *float64 `json:"temperature,omitempty"`
+	ConfidenceThreshold *float64 `json:"confidence_threshold,omitempty"`
+	FallbackMessage     string   `json:"fallback_message,omitempty"`
+	SystemPrompt        string   `json:"system_prompt,omitempty"`
+
+	// FallbackMessages holds fallback answers per language or locale tag,
+	// e.g. {"id": "...", "pt-BR": "..."}; shorthand for Messages["fallback"]
+	FallbackMessages map[string]string `json:"fallback_messages,omitempty"`
+
+	// Messages overrides catalog texts by message key, then language or
+	// locale tag. Texts may use {name} placeholders from MessageVars, e.g.
+	// {support_phone}. Both merge with the inherited values.
+	Messages    map[string]map[string]string `json:"messages,omitempty"`
+	MessageVars map[string]string            `json:"message_vars,omitempty"`
+
+	// Prompt template variables
+	BrandName  string            `json:"brand_name,omitempty"`
+	PromptVars map[string]string `json:"prompt_vars,omitempty"` // Available as {{.Vars.name}}
+
+	// PII redaction; PIIKinds replaces the per-language defaults
+	PIIRedaction *bool    `json:"pii_redaction,omitempty"`
+	PIIKinds     []string `json:"pii_kinds,omitempty"`
+
+	// OutputPolicy replaces the default answer policy
+	OutputPolicy *OutputPolicy `json:"output_policy,omitempty"`
+
+	// Tools replaces the tenant's HTTP tools the model may call
+	Tools []ToolConfig `json:"tools,omitempty"`
+
+	// IntentRoutes overrides intent.DefaultRoutes; CannedAnswers holds the
+	// replies for intents routed to "canned". Both merge with the defaults.
+	IntentRoutes  map[string]string `json:"intent_routes,omitempty"`
+	CannedAnswers map[string]string `json:"canned_answers,omitempty"`
+
+	// FAQs replaces the tenant's approved question/answer pairs; questions
+	// matching one at FAQThreshold or above get its answer without an LLM call
+	FAQs         []FAQ    `json:"faqs,omitempty"`
+	FAQThreshold *float64 `json:"faq_threshold,omitempty"`
+
+	// Translations maps a customer language the tenant has no content for to
+	// the knowledge base language questions are translated into, e.g. {"ms": "id"}
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+// FAQ is an approved answer returned verbatim for matching questions.
+type FAQ struct {
+	ID       string   `json:"id"`
+	Question string   `json:"question"`
+	Variants []string `json:"variants,omitempty"` // Paraphrases that should match too
+	Answer   string   `json:"answer"`
+	Language string   `json:"language,omitempty"` // Empty matches every language
+}
+
+// Output guard checks. Each name is also the fallback reason reported when it fails.
+const (
+	CheckCompetitorMention = "competitor_mention"
+	CheckRefundPromise     = "refund_promise"
+	CheckLegalAdvice       = "legal_advice"
+	CheckMedicalAdvice     = "medical_advice"
+	CheckPromptLeak        = "system_prompt_leak"
+	CheckUnknownURL        = "unknown_url"
+	CheckBlockedPhrase     = "blocked_phrase"
+)
+
+// OutputChecks lists every output guard check.
+var OutputChecks = []string{
+	CheckCompetitorMention, CheckRefundPromise, CheckLegalAdvice, CheckMedicalAdvice,
+	CheckPromptLeak, CheckUnknownURL, CheckBlockedPhrase,
+}
+
+// OutputPolicy configures the output guard for a tenant.
+type OutputPolicy struct {
+	Competitors    []string `json:"competitors,omitempty"`     // Brand names the answer must not mention
+	BlockedPhrases []string `json:"blocked_phrases,omitempty"` // Case-insensitive phrases the answer must not contain
+	AllowedDomains []string `json:"allowed_domains,omitempty"` // Link targets allowed even when absent from the knowledge base
+	DisabledChecks []string `json:"disabled_checks,omitempty"` // Checks from OutputChecks to skip
+}
+
+// Enabled reports whether a check runs under this policy.
+func (p OutputPolicy) Enabled(check string) bool {
+	for _, c := range p.DisabledChecks {
+		if c == check {
+			return false
+		}
+	}
+	return true
+}
+
+// ToolParamTypes lists the JSON types a tool parameter may have.
+var ToolParamTypes = []string{"string", "integer", "number", "boolean"}
+
+// ToolConfig is an HTTP endpoint the model may call for a tenant, such as an
+// order status or refund eligibility lookup. Arguments come from the model,
+// so a customer can ask about any order ID; backends must authorize every
+// call by the X-Customer-ID header, which carries the authenticated customer.
+type ToolConfig struct {
+	Name        string            `json:"name"` // Function name shown to the model
+	Description string            `json:"description"`
+	Method      string            `json:"method,omitempty"` // GET (default) sends arguments as query parameters, POST as a JSON body
+	URL         string            `json:"url"`              // {param} placeholders are filled from the arguments
+	Headers     map[string]string `json:"headers,omitempty"`
+	Parameters  []ToolParam       `json:"parameters,omitempty"`
+	TimeoutMs   int               `json:"timeout_ms,omitempty"` // 0 uses TOOL_TIMEOUT_MS
+}
+
+// ToolParam describes one argument of a tool.
+type ToolParam struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"` // One of ToolParamTypes
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Pattern     string `json:"pattern,omitempty"` // Regular expression string values must fully match
+}
+
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// localeTag matches a language code with an optional region, e.g. "pt-BR".
+var localeTag = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// Validate rejects tools the model could not call or the executor could not send.
+func (t ToolConfig) Validate() error {
+	if !toolNamePattern.MatchString(t.Name) {
+		return errInvalid("tools: name " + t.Name + " must be 1-64 letters, digits, _ or -")
+	}
+	if t.Method != "" && t.Method != "GET" && t.Method != "POST" {
+		return errInvalid("tools." + t.Name + ": method must be GET or POST")
+	}
+	u, err := url.Parse(t.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errInvalid("tools." + t.Name + ": url must be an absolute http(s) URL")
+	}
+	if t.TimeoutMs < 0 {
+		return errInvalid("tools." + t.Name + ": timeout_ms must be >= 0")
+	}
+	seen := make(map[string]bool, len(t.Parameters))
+	for _, p := range t.Parameters {
+		if p.Name == "" || seen[p.Name] {
+			return errInvalid("tools." + t.Name + ": parameter names must be unique and non-empty")
+		}
+		seen[p.Name] = true
+		if !validToolParamType(p.Type) {
+			return errInvalid("tools." + t.Name + "." + p.Name + ": unknown type " + p.Type)
+		}
+		if p.Pattern != "" {
+			if _, err := regexp.Compile(p.Pattern); err != nil {
+				return errInvalid("tools." + t.Name + "." + p.Name + ": invalid pattern")
+			}
+		}
+	}
+	return nil
+}
+
+func validToolParamType(typ string) bool {
+	for _, t := range ToolParamTypes {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// TenantSettings are the effective per-request settings for a tenant.
+type TenantSettings struct {
+	RateLimitPerSec     float64  `json:"rate_limit_per_sec"`
+	RateLimitBurst      int      `json:"rate_limit_burst"`
+	TokenBudget         int      `json:"token_budget"`
+	Model               string   `json:"model,omitempty"`
+	MaxTokens           int      `json:"max_tokens,omitempty"`
+	Temperature         *float64 `json:"temperature,omitempty"` // Nil uses the LLM client default
+	ConfidenceThreshold float64  `json:"confidence_threshold"`
+	FallbackMessage     string   `json:"fallback_message"`
+	SystemPrompt        string   `json:"system_prompt,omitempty"` // Empty uses the built-in prompt
+
+	Messages    map[string]map[string]string `json:"messages,omitempty"`
+	MessageVars map[string]string            `json:"message_vars,omitempty"`
+
+	BrandName  string            `json:"brand_name,omitempty"`
+	PromptVars map[string]string `json:"prompt_vars,omitempty"`
+
+	PIIRedaction bool     `json:"pii_redaction"`
+	PIIKinds     []string `json:"pii_kinds,omitempty"` // Empty uses the defaults for the request language
+
+	OutputPolicy OutputPolicy `json:"output_policy"`
+
+	Tools []ToolConfig `json:"tools,omitempty"`
+
+	IntentRoutes  map[string]string `json:"intent_routes,omitempty"`
+	CannedAnswers map[string]string `json:"canned_answers,omitempty"`
+
+	FAQs         []FAQ   `json:"faqs,omitempty"`
+	FAQThreshold float64 `json:"faq_threshold"`
+
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+// Catalog returns the tenant's message catalog.
+func (s TenantSettings) Catalog() messages.Catalog {
+	return messages.Catalog{Overrides: s.Messages, Vars: s.MessageVars}
+}
+
+// Message returns the customer-facing text for a message key in a language
+// or locale tag. A customised FallbackMessage is used for fallbacks in
+// locales the tenant has no fallback text for.
+func (s TenantSettings) Message(key, tag string) string {
+	catalog := s.Catalog()
+	if key == messages.Fallback && s.FallbackMessage != DefaultFallbackMessage {
+		if text, ok := catalog.Override(key, tag); ok {
+			return text
+		}
+		return catalog.Expand(s.FallbackMessage)
+	}
+	return catalog.Text(key, tag)
+}
+
+// FallbackFor returns the fallback answer for a language or locale tag.
+func (s TenantSettings) FallbackFor(tag string) string {
+	return s.Message(messages.Fallback, tag)
+}
+
+// DefaultFAQThreshold is the similarity a question needs to get an FAQ answer.
+const DefaultFAQThreshold = 0.8
+
+// DefaultGreetingAnswer is the canned reply to greetings.
+const DefaultGreetingAnswer = "Hello! How can I help you today?"
+
+// BuiltinTenantSettings are used when no configuration has been loaded. Zero
+// limits and the unset temperature defer to the rate limiter, budget guard
+// and LLM client defaults.
+func BuiltinTenantSettings() TenantSettings {
+	return TenantSettings{
+		ConfidenceThreshold: 0.7,
+		FallbackMessage:     DefaultFallbackMessage,
+		PIIRedaction:        true,
+		CannedAnswers:       map[string]string{intent.Greeting: DefaultGreetingAnswer},
+		FAQThreshold:        DefaultFAQThreshold,
+	}
+}
+
+// DefaultTenantSettings returns the global settings every tenant starts from.
+func (c Config) DefaultTenantSettings() TenantSettings {
+	temperature := c.LLMTemperature
+	return TenantSettings{
+		RateLimitPerSec:     c.TenantRateLimitPerSec,
+		RateLimitBurst:      c.TenantRateLimitBurst,
+		TokenBudget:         c.TenantTokenBudget,
+		Model:               c.LLMDefaultModel,
+		MaxTokens:           c.LLMMaxTokens,
+		Temperature:         &temperature,
+		ConfidenceThreshold: c.ConfidenceThreshold,
+		FallbackMessage:     c.FallbackMessage,
+		SystemPrompt:        c.SystemPrompt,
+		PIIRedaction:        c.PIIRedaction,
+		PIIKinds:            c.PIIKinds,
+		CannedAnswers:       map[string]string{intent.Greeting: DefaultGreetingAnswer},
+		FAQThreshold:        c.FAQMatchThreshold,
+	}
+}
+
+// Apply layers the tenant's overrides on top of base.
+func (tc TenantConfig) Apply(base TenantSettings) TenantSettings {
+	out := base
+	if tc.RateLimitPerSec != nil {
+		out.RateLimitPerSec = *tc.RateLimitPerSec
+	}
+	if tc.RateLimitBurst != nil {
+		out.RateLimitBurst = *tc.RateLimitBurst
+	}
+	if tc.TokenBudget != nil {
+		out.TokenBudget = *tc.TokenBudget
+	}
+	if tc.Model != "" {
+		out.Model = tc.Model
+	}
+	if tc.MaxTokens != nil {
+		out.MaxTokens = *tc.MaxTokens
+	}
+	if tc.Temperature != nil {
+		temperature := *tc.Temperature
+		out.Temperature = &temperature
+	}
+	if tc.ConfidenceThreshold != nil {
+		out.ConfidenceThreshold = *tc.ConfidenceThreshold
+	}
+	if tc.FallbackMessage != "" {
+		out.FallbackMessage = tc.FallbackMessage
+	}
+	if tc.SystemPrompt != "" {
+		out.SystemPrompt = tc.SystemPrompt
+	}
+	if len(tc.Messages) > 0 || len(tc.FallbackMessages) > 0 {
+		out.Messages = mergeMessages(base.Messages, tc.Messages)
+		if len(tc.FallbackMessages) > 0 {
+			out.Messages = mergeMessages(out.Messages, map[string]map[string]string{messages.Fallback: tc.FallbackMessages})
+		}
+	}
+	if len(tc.MessageVars) > 0 {
+		out.MessageVars = mergeStrings(base.MessageVars, tc.MessageVars)
+	}
+	if tc.BrandName != "" {
+		out.BrandName = tc.BrandName
+	}
+	if len(tc.PromptVars) > 0 {
+		out.PromptVars = mergeStrings(base.PromptVars, tc.PromptVars)
+	}
+	if tc.PIIRedaction != nil {
+		out.PIIRedaction = *tc.PIIRedaction
+	}
+	if len(tc.PIIKinds) > 0 {
+		out.PIIKinds = tc.PIIKinds
+	}
+	if tc.OutputPolicy != nil {
+		out.OutputPolicy = *tc.OutputPolicy
+	}
+	if len(tc.Tools) > 0 {
+		out.Tools = tc.Tools
+	}
+	if len(tc.IntentRoutes) > 0 {
+		out.IntentRoutes = mergeStrings(base.IntentRoutes, tc.IntentRoutes)
+	}
+	if len(tc.CannedAnswers) > 0 {
+		out.CannedAnswers = mergeStrings(base.CannedAnswers, tc.CannedAnswers)
+	}
+	if len(tc.FAQs) > 0 {
+		out.FAQs = tc.FAQs
+	}
+	if tc.FAQThreshold != nil {
+		out.FAQThreshold = *tc.FAQThreshold
+	}
+	if len(tc.Translations) > 0 {
+		out.Translations = mergeStrings(base.Translations, tc.Translations)
+	}
+	return out
+}
+
+// mergeStrings returns a copy of base with override's entries layered on top.
+func mergeStrings(base, override map[string]string) map[string]string {
+	out := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range override {
+		out[k] = v
+	}
+	return out
+}
+
+// mergeMessages layers override's texts on top of base, canonicalising locale tags.
+func mergeMessages(base, override map[string]map[string]string) map[string]map[string]string {
+	out := make(map[string]map[string]string, len(base)+len(override))
+	for key, texts := range base {
+		out[key] = texts
+	}
+	for key, texts := range override {
+		canonical := make(map[string]string, len(texts))
+		for tag, text := range texts {
+			canonical[locale.Canonical(tag)] = text
+		}
+		out[key] = mergeStrings(base[key], canonical)
+	}
+	return out
+}
+
+// Validate rejects override values that would break request handling.
+func (tc TenantConfig) Validate() error {
+	if tc.RateLimitPerSec != nil && *tc.RateLimitPerSec <= 0 {
+		return errInvalid("rate_limit_per_sec must be > 0")
+	}
+	if tc.RateLimitBurst != nil && *tc.RateLimitBurst <= 0 {
+		return errInvalid("rate_limit_burst must be > 0")
+	}
+	if tc.TokenBudget != nil && *tc.TokenBudget < 0 {
+		return errInvalid("token_budget must be >= 0")
+	}
+	if tc.MaxTokens != nil && *tc.MaxTokens <= 0 {
+		return errInvalid("max_tokens must be > 0")
+	}
+	if tc.Temperature != nil && (*tc.Temperature < 0 || *tc.Temperature > 2) {
+		return errInvalid("temperature must be between 0 and 2")
+	}
+	if tc.ConfidenceThreshold != nil && (*tc.ConfidenceThreshold < 0 || *tc.ConfidenceThreshold > 1) {
+		return errInvalid("confidence_threshold must be between 0 and 1")
+	}
+	if err := validateTexts("fallback_messages", tc.FallbackMessages, tc.MessageVars); err != nil {
+		return err
+	}
+	for key, texts := range tc.Messages {
+		if !messages.ValidKey(key) {
+			return errInvalid("messages: unknown message key " + key)
+		}
+		if err := validateTexts("messages."+key, texts, tc.MessageVars); err != nil {
+			return err
+		}
+	}
+	if _, err := pii.ParseKinds(tc.PIIKinds); err != nil {
+		return errInvalid("pii_kinds: " + err.Error())
+	}
+	if tc.OutputPolicy != nil {
+		for _, check := range tc.OutputPolicy.DisabledChecks {
+			if !validOutputCheck(check) {
+				return errInvalid("output_policy.disabled_checks: unknown check " + check)
+			}
+		}
+	}
+	names := make(map[string]bool, len(tc.Tools))
+	for _, tool := range tc.Tools {
+		if err := tool.Validate(); err != nil {
+			return err
+		}
+		if names[tool.Name] {
+			return errInvalid("tools: duplicate name " + tool.Name)
+		}
+		names[tool.Name] = true
+	}
+	for name, route := range tc.IntentRoutes {
+		if !intent.ValidIntent(name) {
+			return errInvalid("intent_routes: unknown intent " + name)
+		}
+		if !intent.ValidRoute(route) {
+			return errInvalid("intent_routes." + name + ": route must be escalate, canned or rag")
+		}
+	}
+	for name := range tc.CannedAnswers {
+		if !intent.ValidIntent(name) {
+			return errInvalid("canned_answers: unknown intent " + name)
+		}
+	}
+	if tc.FAQThreshold != nil && (*tc.FAQThreshold <= 0 || *tc.FAQThreshold > 1) {
+		return errInvalid("faq_threshold must be > 0 and <= 1")
+	}
+	for from, to := range tc.Translations {
+		if !languageCode.MatchString(from) || from == to {
+			return errInvalid("translations: invalid source language " + from)
+		}
+		if !SupportedLanguages[to] {
+			return errInvalid("translations." + from + ": " + to + " is not a supported language")
+		}
+	}
+	ids := make(map[string]bool, len(tc.FAQs))
+	for _, f := range tc.FAQs {
+		if f.ID == "" || ids[f.ID] {
+			return errInvalid("faqs: ids must be unique and non-empty")
+		}
+		ids[f.ID] = true
+		if strings.TrimSpace(f.Question) == "" || strings.TrimSpace(f.Answer) == "" {
+			return errInvalid("faqs." + f.ID + ": question and answer are required")
+		}
+	}
+	return nil
+}
+
+// validateTexts checks message texts keyed by locale tag. Placeholders must be
+// defined in vars, since inherited variables cannot be checked here.
+func validateTexts(field string, texts, vars map[string]string) error {
+	for tag, text := range texts {
+		if !localeTag.MatchString(locale.Canonical(tag)) {
+			return errInvalid(field + ": invalid language tag " + tag)
+		}
+		if strings.TrimSpace(text) == "" {
+			return errInvalid(field + "." + tag + ": must not be empty")
+		}
+		for _, name := range messages.Placeholders(text) {
+			if _, ok := vars[name]; !ok {
+				return errInvalid(field + "." + tag + ": {" + name + "} is not defined in message_vars")
+			}
+		}
+	}
+	return nil
+}
+
+func validOutputCheck(check string) bool {
+	for _, c := range OutputChecks {
+		if c == check {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,415 @@
+package experiment
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+)
+
+// FeedbackWindow is how long an answer stays eligible for feedback.
+const FeedbackWindow = 24 * time.Hour
+
+var (
+	ErrExperimentNotFound = errors.New("experiment not found")
+	ErrExperimentRunning  = errors.New("tenant already has a running experiment")
+	ErrInvalidExperiment  = errors.New("invalid experiment")
+	ErrResponseNotFound   = errors.New("response not found")
+	ErrFeedbackRecorded   = errors.New("feedback already recorded")
+	ErrResponseExists     = errors.New("response already recorded")
+)
+
+// Variant is one arm of an experiment. Zero-valued overrides keep the
+// tenant's effective settings, so a variant with no overrides is the control.
+type Variant struct {
+	Name          string   `json:"name"`
+	Weight        int      `json:"weight"`                   // Relative share of traffic
+	PromptVersion int      `json:"prompt_version,omitempty"` // 0 = tenant's active prompt
+	Model         string   `json:"model,omitempty"`
+	Temperature   *float64 `json:"temperature,omitempty"`
+}
+
+// Experiment splits a tenant's traffic between variants.
+type Experiment struct {
+	ID        string     `json:"id"`
+	TenantID  string     `json:"tenant_id"`
+	Name      string     `json:"name"`
+	Variants  []Variant  `json:"variants"`
+	Running   bool       `json:"running"`
+	CreatedAt time.Time  `json:"created_at"`
+	StoppedAt *time.Time `json:"stopped_at,omitempty"`
+}
+
+// Validate checks variant names and weights.
+func (e Experiment) Validate() error {
+	if e.TenantID == "" {
+		return fmt.Errorf("%w: tenant_id is required", ErrInvalidExperiment)
+	}
+	if len(e.Variants) < 2 {
+		return fmt.Errorf("%w: at least two variants are required", ErrInvalidExperiment)
+	}
+	seen := map[string]bool{}
+	for _, v := range e.Variants {
+		if v.Name == "" {
+			return fmt.Errorf("%w: variant name is required", ErrInvalidExperiment)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("%w: duplicate variant %q", ErrInvalidExperiment, v.Name)
+		}
+		seen[v.Name] = true
+		if v.Weight <= 0 {
+			return fmt.Errorf("%w: variant %q weight must be positive", ErrInvalidExperiment, v.Name)
+		}
+		if v.PromptVersion < 0 {
+			return fmt.Errorf("%w: variant %q prompt_version must not be negative", ErrInvalidExperiment, v.Name)
+		}
+		if v.Temperature != nil && (*v.Temperature < 0 || *v.Temperature > 2) {
+			return fmt.Errorf("%w: variant %q temperature must be between 0 and 2", ErrInvalidExperiment, v.Name)
+		}
+	}
+	return nil
+}
+
+// Outcome is what happened when a variant answered a request.
+type Outcome struct {
+	Fallback   bool
+	Confidence float64
+	Latency    time.Duration
+	TokensUsed int
+
+	// Model, PromptTokens and CompletionTokens price the request
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// VariantReport aggregates outcomes for one variant.
+type VariantReport struct {
+	Variant       string  `json:"variant"`
+	Requests      int64   `json:"requests"`
+	FallbackRate  float64 `json:"fallback_rate"`
+	AvgConfidence float64 `json:"avg_confidence"`
+	AvgLatencyMs  float64 `json:"avg_latency_ms"`
+	TokensTotal   int64   `json:"tokens_total"`
+	AvgTokens     float64 `json:"avg_tokens"`
+	CostTotal     float64 `json:"cost_total"`
+	AvgCost       float64 `json:"avg_cost"`           // Over priced requests
+	Unpriced      int64   `json:"unpriced,omitempty"` // Requests whose model has no price
+	FeedbackCount int64   `json:"feedback_count"`
+	AvgFeedback   float64 `json:"avg_feedback"` // Mean score (1-5); 0 when no feedback
+}
+
+// Report is the per-variant comparison for an experiment.
+type Report struct {
+	Experiment Experiment      `json:"experiment"`
+	Variants   []VariantReport `json:"variants"`
+}
+
+// Assignment records which variant served a response so feedback can be attributed.
+type Assignment struct {
+	ExperimentID string
+	TenantID     string
+	Variant      string
+	assignedAt   time.Time
+	feedback     bool
+}
+
+type stats struct {
+	requests      int64
+	fallbacks     int64
+	confidenceSum float64
+	latencySumMs  int64
+	tokens        int64
+	cost          float64
+	unpriced      int64
+	feedbackCount int64
+	feedbackSum   int64
+}
+
+// Store keeps experiments, their aggregated outcomes and recent response
+// assignments in memory. It is safe for concurrent use.
+type Store struct {
+	mu          sync.RWMutex
+	experiments map[string]*Experiment
+	stats       map[string]map[string]*stats // experiment ID -> variant -> stats
+	responses   map[string]*Assignment       // response ID -> assignment
+	prices      map[string]config.ModelPrice
+	lastPrune   time.Time
+	now         func() time.Time
+}
+
+// NewStore creates an empty experiment store
+func NewStore() *Store {
+	return &Store{
+		experiments: map[string]*Experiment{},
+		stats:       map[string]map[string]*stats{},
+		responses:   map[string]*Assignment{},
+		now:         time.Now,
+	}
+}
+
+// SetPrices replaces the token prices used for cost; later outcomes use them.
+func (s *Store) SetPrices(prices map[string]config.ModelPrice) {
+	s.mu.Lock()
+	s.prices = prices
+	s.mu.Unlock()
+}
+
+// Create starts a new experiment. A tenant can only run one experiment at a time.
+func (s *Store) Create(tenantID, name string, variants []Variant) (Experiment, error) {
+	exp := Experiment{TenantID: tenantID, Name: name, Variants: variants}
+	if err := exp.Validate(); err != nil {
+		return Experiment{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return Experiment{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.experiments {
+		if e.TenantID == tenantID && e.Running {
+			return Experiment{}, ErrExperimentRunning
+		}
+	}
+
+	exp.ID = id
+	exp.Variants = append([]Variant(nil), variants...)
+	exp.Running = true
+	exp.CreatedAt = s.now()
+	s.experiments[id] = &exp
+
+	byVariant := map[string]*stats{}
+	for _, v := range exp.Variants {
+		byVariant[v.Name] = &stats{}
+	}
+	s.stats[id] = byVariant
+	return exp, nil
+}
+
+// Get returns an experiment by ID.
+func (s *Store) Get(id string) (Experiment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.experiments[id]
+	if !ok {
+		return Experiment{}, ErrExperimentNotFound
+	}
+	return *e, nil
+}
+
+// List returns experiments sorted by creation time, optionally filtered by tenant.
+func (s *Store) List(tenantID string) []Experiment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Experiment, 0, len(s.experiments))
+	for _, e := range s.experiments {
+		if tenantID == "" || e.TenantID == tenantID {
+			out = append(out, *e)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out
+}
+
+// Stop ends an experiment. Its report remains available.
+func (s *Store) Stop(id string) (Experiment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.experiments[id]
+	if !ok {
+		return Experiment{}, ErrExperimentNotFound
+	}
+	if e.Running {
+		now := s.now()
+		e.Running = false
+		e.StoppedAt = &now
+	}
+	return *e, nil
+}
+
+// Assign picks a variant of the tenant's running experiment for stickyKey.
+// The same key always gets the same variant for a given experiment.
+func (s *Store) Assign(tenantID, stickyKey string) (Experiment, Variant, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, e := range s.experiments {
+		if e.TenantID != tenantID || !e.Running {
+			continue
+		}
+		return *e, pick(e, stickyKey), true
+	}
+	return Experiment{}, Variant{}, false
+}
+
+// Record adds the outcome of a request served by variant and remembers the
+// response ID for feedback. A response ID is recorded at most once, so its
+// feedback cannot be reset by replaying it.
+func (s *Store) Record(experimentID, variant, responseID string, outcome Outcome) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.stats[experimentID][variant]
+	e := s.experiments[experimentID]
+	if st == nil || e == nil {
+		return ErrExperimentNotFound
+	}
+	now := s.now()
+	if responseID != "" {
+		s.pruneLocked(now)
+		if _, exists := s.responses[responseID]; exists {
+			return ErrResponseExists
+		}
+	}
+	st.requests++
+	if outcome.Fallback {
+		st.fallbacks++
+	}
+	st.confidenceSum += outcome.Confidence
+	st.latencySumMs += outcome.Latency.Milliseconds()
+	st.tokens += int64(outcome.TokensUsed)
+	if price, ok := s.prices[outcome.Model]; ok {
+		st.cost += price.Cost(outcome.PromptTokens, outcome.CompletionTokens)
+	} else {
+		st.unpriced++
+	}
+
+	if responseID != "" {
+		s.responses[responseID] = &Assignment{
+			ExperimentID: experimentID,
+			TenantID:     e.TenantID,
+			Variant:      variant,
+			assignedAt:   now,
+		}
+	}
+	return nil
+}
+
+// Lookup returns the assignment for a response still inside the feedback window.
+func (s *Store) Lookup(responseID string) (Assignment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, ok := s.responses[responseID]
+	if !ok || s.now().Sub(a.assignedAt) > FeedbackWindow {
+		return Assignment{}, ErrResponseNotFound
+	}
+	return *a, nil
+}
+
+// RecordFeedback attributes a 1-5 score to the variant that produced the response.
+// Only the first feedback for a response counts.
+func (s *Store) RecordFeedback(responseID string, score int) (Assignment, error) {
+	if score < 1 || score > 5 {
+		return Assignment{}, fmt.Errorf("%w: score must be between 1 and 5", ErrInvalidExperiment)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.responses[responseID]
+	if !ok || s.now().Sub(a.assignedAt) > FeedbackWindow {
+		return Assignment{}, ErrResponseNotFound
+	}
+	if a.feedback {
+		return *a, ErrFeedbackRecorded
+	}
+	if st := s.stats[a.ExperimentID][a.Variant]; st != nil {
+		st.feedbackCount++
+		st.feedbackSum += int64(score)
+	}
+	a.feedback = true
+	return *a, nil
+}
+
+// Report aggregates outcomes per variant, in the experiment's variant order.
+func (s *Store) Report(id string) (Report, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.experiments[id]
+	if !ok {
+		return Report{}, ErrExperimentNotFound
+	}
+	report := Report{Experiment: *e, Variants: make([]VariantReport, 0, len(e.Variants))}
+	for _, v := range e.Variants {
+		st := s.stats[id][v.Name]
+		vr := VariantReport{
+			Variant:       v.Name,
+			Requests:      st.requests,
+			TokensTotal:   st.tokens,
+			CostTotal:     st.cost,
+			Unpriced:      st.unpriced,
+			FeedbackCount: st.feedbackCount,
+		}
+		if st.requests > 0 {
+			n := float64(st.requests)
+			vr.FallbackRate = float64(st.fallbacks) / n
+			vr.AvgConfidence = st.confidenceSum / n
+			vr.AvgLatencyMs = float64(st.latencySumMs) / n
+			vr.AvgTokens = float64(st.tokens) / n
+		}
+		if priced := st.requests - st.unpriced; priced > 0 {
+			vr.AvgCost = st.cost / float64(priced)
+		}
+		if st.feedbackCount > 0 {
+			vr.AvgFeedback = float64(st.feedbackSum) / float64(st.feedbackCount)
+		}
+		report.Variants = append(report.Variants, vr)
+	}
+	return report, nil
+}
+
+// pruneLocked drops assignments outside the feedback window, at most once a
+// minute. Caller holds s.mu.
+func (s *Store) pruneLocked(now time.Time) {
+	if now.Sub(s.lastPrune) < time.Minute {
+		return
+	}
+	s.lastPrune = now
+	for id, a := range s.responses {
+		if now.Sub(a.assignedAt) > FeedbackWindow {
+			delete(s.responses, id)
+		}
+	}
+}
+
+// pick maps stickyKey onto the cumulative variant weights.
+func pick(e *Experiment, stickyKey string) Variant {
+	total := 0
+	for _, v := range e.Variants {
+		total += v.Weight
+	}
+	h := fnv.New32a()
+	h.Write([]byte(e.ID))
+	h.Write([]byte{0})
+	h.Write([]byte(stickyKey))
+	bucket := int(h.Sum32() % uint32(total))
+	for _, v := range e.Variants {
+		if bucket < v.Weight {
+			return v
+		}
+		bucket -= v.Weight
+	}
+	return e.Variants[len(e.Variants)-1]
+}
+
+func newID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "exp_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,173 @@
+package experiment
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+)
+
+func twoVariants() []Variant {
+	return []Variant{
+		{Name: "control", Weight: 1},
+		{Name: "gpt-4o", Weight: 1, Model: "gpt-4o"},
+	}
+}
+
+func TestStore_CreateValidation(t *testing.T) {
+	s := NewStore()
+	tests := []struct {
+		name     string
+		variants []Variant
+	}{
+		{name: "single variant", variants: []Variant{{Name: "a", Weight: 1}}},
+		{name: "duplicate name", variants: []Variant{{Name: "a", Weight: 1}, {Name: "a", Weight: 1}}},
+		{name: "zero weight", variants: []Variant{{Name: "a", Weight: 1}, {Name: "b"}}},
+		{name: "missing name", variants: []Variant{{Name: "a", Weight: 1}, {Weight: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Create("shop-123", tt.name, tt.variants); !errors.Is(err, ErrInvalidExperiment) {
+				t.Errorf("Create() error = %v, want ErrInvalidExperiment", err)
+			}
+		})
+	}
+
+	if _, err := s.Create("shop-123", "first", twoVariants()); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := s.Create("shop-123", "second", twoVariants()); !errors.Is(err, ErrExperimentRunning) {
+		t.Errorf("Create() second running error = %v, want ErrExperimentRunning", err)
+	}
+}
+
+func TestStore_AssignIsStickyAndWeighted(t *testing.T) {
+	s := NewStore()
+	exp, err := s.Create("shop-123", "models", []Variant{
+		{Name: "control", Weight: 3},
+		{Name: "candidate", Weight: 1},
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	_, first, ok := s.Assign("shop-123", "conv:abc")
+	if !ok {
+		t.Fatal("expected assignment")
+	}
+	for i := 0; i < 10; i++ {
+		if _, v, _ := s.Assign("shop-123", "conv:abc"); v.Name != first.Name {
+			t.Fatalf("assignment not sticky: %s then %s", first.Name, v.Name)
+		}
+	}
+
+	counts := map[string]int{}
+	for i := 0; i < 4000; i++ {
+		_, v, _ := s.Assign("shop-123", fmt.Sprintf("cust:%d", i))
+		counts[v.Name]++
+	}
+	share := float64(counts["control"]) / 4000
+	if math.Abs(share-0.75) > 0.05 {
+		t.Errorf("control share = %.2f, want ~0.75", share)
+	}
+
+	if _, _, ok := s.Assign("shop-456", "conv:abc"); ok {
+		t.Error("tenant without experiment was assigned")
+	}
+	if _, err := s.Stop(exp.ID); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if _, _, ok := s.Assign("shop-123", "conv:abc"); ok {
+		t.Error("stopped experiment still assigns traffic")
+	}
+}
+
+func TestStore_Report(t *testing.T) {
+	s := NewStore()
+	s.SetPrices(map[string]config.ModelPrice{
+		"gpt-3.5-turbo": {PromptPer1K: 0.5, CompletionPer1K: 1.5},
+		"gpt-4o":        {PromptPer1K: 2.5, CompletionPer1K: 10},
+	})
+	exp, err := s.Create("shop-123", "models", twoVariants())
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	s.Record(exp.ID, "control", "r1", Outcome{Confidence: 0.9, Latency: 100 * time.Millisecond, TokensUsed: 100,
+		Model: "gpt-3.5-turbo", PromptTokens: 80, CompletionTokens: 20})
+	s.Record(exp.ID, "control", "r2", Outcome{Fallback: true, Confidence: 0.5, Latency: 300 * time.Millisecond, TokensUsed: 50,
+		Model: "gpt-3.5-turbo", PromptTokens: 40, CompletionTokens: 10})
+	s.Record(exp.ID, "gpt-4o", "r3", Outcome{Confidence: 0.8, Latency: 200 * time.Millisecond, TokensUsed: 80,
+		Model: "gpt-4o", PromptTokens: 60, CompletionTokens: 20})
+	s.Record(exp.ID, "gpt-4o", "r4", Outcome{Confidence: 0.8, TokensUsed: 80, Model: "gpt-4o-mini"})
+
+	if _, err := s.RecordFeedback("r1", 5); err != nil {
+		t.Fatalf("RecordFeedback() error = %v", err)
+	}
+	if _, err := s.RecordFeedback("r2", 2); err != nil {
+		t.Fatalf("RecordFeedback() error = %v", err)
+	}
+	if _, err := s.RecordFeedback("r1", 1); !errors.Is(err, ErrFeedbackRecorded) {
+		t.Errorf("duplicate feedback error = %v, want ErrFeedbackRecorded", err)
+	}
+	if _, err := s.RecordFeedback("unknown", 3); !errors.Is(err, ErrResponseNotFound) {
+		t.Errorf("unknown response error = %v, want ErrResponseNotFound", err)
+	}
+
+	report, err := s.Report(exp.ID)
+	if err != nil {
+		t.Fatalf("Report() error = %v", err)
+	}
+	control := report.Variants[0]
+	if control.Variant != "control" || control.Requests != 2 {
+		t.Fatalf("unexpected control report: %+v", control)
+	}
+	if control.FallbackRate != 0.5 || math.Abs(control.AvgConfidence-0.7) > 1e-9 {
+		t.Errorf("fallback rate = %v, avg confidence = %v", control.FallbackRate, control.AvgConfidence)
+	}
+	if control.AvgLatencyMs != 200 || control.TokensTotal != 150 || control.AvgFeedback != 3.5 {
+		t.Errorf("latency = %v, tokens = %d, feedback = %v", control.AvgLatencyMs, control.TokensTotal, control.AvgFeedback)
+	}
+	// (120*0.5 + 30*1.5) / 1000 over two requests
+	if math.Abs(control.CostTotal-0.105) > 1e-9 || math.Abs(control.AvgCost-0.0525) > 1e-9 || control.Unpriced != 0 {
+		t.Errorf("control cost = %v, avg = %v, unpriced = %d", control.CostTotal, control.AvgCost, control.Unpriced)
+	}
+	candidate := report.Variants[1]
+	if candidate.Requests != 2 || candidate.FeedbackCount != 0 {
+		t.Errorf("unexpected candidate report: %+v", candidate)
+	}
+	// Only the priced request counts towards the average: (60*2.5 + 20*10) / 1000
+	if math.Abs(candidate.CostTotal-0.35) > 1e-9 || math.Abs(candidate.AvgCost-0.35) > 1e-9 || candidate.Unpriced != 1 {
+		t.Errorf("candidate cost = %v, avg = %v, unpriced = %d", candidate.CostTotal, candidate.AvgCost, candidate.Unpriced)
+	}
+}
+
+func TestStore_FeedbackWindow(t *testing.T) {
+	s := NewStore()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	exp, err := s.Create("shop-123", "models", twoVariants())
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	s.Record(exp.ID, "control", "r1", Outcome{})
+
+	if _, err := s.RecordFeedback("r1", 5); err != nil {
+		t.Fatalf("RecordFeedback() error = %v", err)
+	}
+	if err := s.Record(exp.ID, "control", "r1", Outcome{}); !errors.Is(err, ErrResponseExists) {
+		t.Errorf("duplicate Record() error = %v, want ErrResponseExists", err)
+	}
+	if _, err := s.RecordFeedback("r1", 5); !errors.Is(err, ErrFeedbackRecorded) {
+		t.Errorf("RecordFeedback() after duplicate Record error = %v, want ErrFeedbackRecorded", err)
+	}
+
+	now = now.Add(FeedbackWindow + time.Minute)
+	if _, err := s.RecordFeedback("r1", 4); !errors.Is(err, ErrResponseNotFound) {
+		t.Errorf("expired feedback error = %v, want ErrResponseNotFound", err)
+	}
+}
@@ -0,0 +1,157 @@
+// Package faq matches customer questions against each tenant's approved
+// question/answer pairs so common questions skip the LLM.
+package faq
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+)
+
+// Match is the best FAQ entry for a question.
+type Match struct {
+	Entry config.FAQ
+	Score float64 // 0.0 (unrelated) to 1.0 (same content words)
+}
+
+// wordWeight is the share of the score taken from word overlap; the rest
+// comes from character trigrams, which tolerate typos and inflections.
+const wordWeight = 0.7
+
+var stopwords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`a an the is are was were be am do does did can could would should will shall may
+		i me my we our you your they their it its this that these those there here of to in on at for from by with about
+		and or but if so as what what's whats how how's where when which who why please hi hello thanks thank
+		apa apakah bagaimana gimana berapa kapan dimana mana siapa yang dan atau di ke dari untuk dengan ini itu
+		saya aku kami kita anda kamu adalah ada bisa dapat tolong mohon nya`) {
+		stopwords[w] = true
+	}
+}
+
+// Find returns the entry whose question or variants best match question, if
+// the score reaches threshold. Entries with a language only match that language.
+func Find(question, language string, entries []config.FAQ, threshold float64) (Match, bool) {
+	q := newText(question)
+	if len(q.words) == 0 {
+		return Match{}, false
+	}
+	var best Match
+	for _, e := range entries {
+		if e.Language != "" && language != "" && e.Language != language {
+			continue
+		}
+		for _, candidate := range append([]string{e.Question}, e.Variants...) {
+			if score := similarity(q, newText(candidate)); score > best.Score {
+				best = Match{Entry: e, Score: score}
+			}
+		}
+	}
+	return best, best.Score > 0 && best.Score >= threshold
+}
+
+// text is a question reduced to its stemmed content words and their trigrams.
+type text struct {
+	words    map[string]bool
+	trigrams map[string]bool
+}
+
+func newText(s string) text {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	t := text{words: map[string]bool{}, trigrams: map[string]bool{}}
+	var kept []string
+	for _, w := range fields {
+		w = strings.Trim(w, "'")
+		if len([]rune(w)) < 2 || stopwords[w] {
+			continue
+		}
+		w = stem(w)
+		t.words[w] = true
+		kept = append(kept, w)
+	}
+	runes := []rune(" " + strings.Join(kept, " ") + " ")
+	for i := 0; i+3 <= len(runes); i++ {
+		t.trigrams[string(runes[i:i+3])] = true
+	}
+	return t
+}
+
+// similarity blends the Dice coefficients of the word and trigram sets.
+// Words one typo apart count as shared.
+func similarity(a, b text) float64 {
+	if len(a.words) == 0 || len(b.words) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a.words {
+		if b.words[w] {
+			shared++
+			continue
+		}
+		for v := range b.words {
+			if typo(w, v) {
+				shared++
+				break
+			}
+		}
+	}
+	words := 2 * float64(shared) / float64(len(a.words)+len(b.words))
+	return wordWeight*words + (1-wordWeight)*dice(a.trigrams, b.trigrams)
+}
+
+// typo reports whether two words of at least four letters are one insertion,
+// deletion or substitution apart.
+func typo(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < 4 || len(rb) < 4 || len(ra)-len(rb) > 1 || len(rb)-len(ra) > 1 {
+		return false
+	}
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+	i, j, edits := 0, 0, 0
+	for i < len(ra) && j < len(rb) {
+		if ra[i] == rb[j] {
+			i++
+			j++
+			continue
+		}
+		edits++
+		if edits > 1 {
+			return false
+		}
+		if len(ra) == len(rb) {
+			j++
+		}
+		i++
+	}
+	return edits+len(ra)-i <= 1
+}
+
+func dice(a, b map[string]bool) float64 {
+	if len(a)+len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for k := range a {
+		if b[k] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+// stem strips a few common English and Indonesian suffixes so "refunds"
+// matches "refund" and "pesanannya" matches "pesanan".
+func stem(w string) string {
+	for _, suffix := range []string{"nya", "ing", "ed", "es", "s"} {
+		if strings.HasSuffix(w, suffix) && len(w)-len(suffix) >= 4 {
+			return strings.TrimSuffix(w, suffix)
+		}
+	}
+	return w
+}
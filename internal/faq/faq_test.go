@@ -0,0 +1,69 @@
+package faq
+
+import (
+	"testing"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+)
+
+var entries = []config.FAQ{
+	{
+		ID:       "refund-policy",
+		Question: "What is your refund policy?",
+		Variants: []string{"How do refunds work?", "Can I return an item for my money back?"},
+		Answer:   "Refunds are available within 30 days of delivery.",
+	},
+	{
+		ID:       "refund-policy-id",
+		Question: "Bagaimana kebijakan pengembalian dana?",
+		Answer:   "Pengembalian dana tersedia dalam 30 hari setelah pengiriman.",
+		Language: "id",
+	},
+	{
+		ID:       "opening-hours",
+		Question: "What are your customer service opening hours?",
+		Answer:   "Our team is available 9am to 6pm, Monday to Friday.",
+	},
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		question string
+		language string
+		want     string
+	}{
+		{"what's your refund policy", "en", "refund-policy"},
+		{"Refund policy?", "en", "refund-policy"},
+		{"how does a refund work", "en", "refund-policy"},
+		{"What is your refnd policy?", "en", "refund-policy"},
+		{"Apa kebijakan pengembalian dananya?", "id", "refund-policy-id"},
+		{"When are customer service hours?", "en", "opening-hours"},
+		// Related but different questions go to the LLM
+		{"I want a refund for order 123", "en", ""},
+		{"What is your shipping policy?", "en", ""},
+		{"hello", "en", ""},
+		// Language-specific entries only match their language
+		{"Bagaimana kebijakan pengembalian dana?", "en", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.question, func(t *testing.T) {
+			match, ok := Find(tt.question, tt.language, entries, config.DefaultFAQThreshold)
+			got := ""
+			if ok {
+				got = match.Entry.ID
+			}
+			if got != tt.want {
+				t.Errorf("Find(%q) = %q (score %.2f), want %q", tt.question, got, match.Score, tt.want)
+			}
+		})
+	}
+}
+
+func TestFind_Threshold(t *testing.T) {
+	if _, ok := Find("refund policy for opened items", "en", entries, 0.95); ok {
+		t.Error("Find() matched below the threshold")
+	}
+	if match, ok := Find("What is your refund policy?", "en", entries, 1.0); !ok || match.Score != 1.0 {
+		t.Errorf("Find() = %+v, %v, want exact match at 1.0", match, ok)
+	}
+}
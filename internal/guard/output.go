@@ -0,0 +1,183 @@
+package guard
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+)
+
+// Violation is a failed output check.
+type Violation struct {
+	Check  string `json:"check"` // One of config.OutputChecks
+	Detail string `json:"detail,omitempty"`
+}
+
+// OutputInput is what an answer is checked against.
+type OutputInput struct {
+	Answer        string
+	KnowledgeBase []string
+	SystemPrompt  string // The system message sent to the model
+	Policy        config.OutputPolicy
+}
+
+var (
+	urlPattern      = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>()"']+`)
+	numberPattern   = regexp.MustCompile(`\d+(?:[.,]\d+)?`)
+	sentenceSplit   = regexp.MustCompile(`[.!?\n]+\s*`)
+	refundPattern   = regexp.MustCompile(`(?i)\b(refunds?|refunded|money back|reimburse\w*|pengembalian dana|uang kembali|refund penuh)\b`)
+	promisePattern  = regexp.MustCompile(`(?i)\b(will|guarantee\w*|definitely|promise\w*|certainly|always|full|100%|akan|pasti|dijamin|menjamin)\b`)
+	leakPattern     = regexp.MustCompile(`(?i)\b(my|the) (system prompt|instructions|guidelines) (are|say|is)\b|\bi (was|am) instructed to\b`)
+	legalPatterns   = regexp.MustCompile(`(?i)\b(you (should|could|can|may) (sue|take legal action|file a (lawsuit|claim|complaint) in court)|this (is|constitutes) (a breach|illegal|unlawful)|you are legally entitled|legal advice|sebaiknya anda menggugat|tuntut secara hukum)\b`)
+	medicalPatterns = regexp.MustCompile(`(?i)\b(\d+\s?(mg|ml|milligrams?)\b|dosage|dosis|you (should|could) take (some )?\w*(medicine|medication|pills?|tablets?|ibuprofen|paracetamol|aspirin)|diagnos(is|e|ed)|medical advice|it (sounds|looks) like you have)\b`)
+)
+
+// OutputGuard checks generated answers against the tenant's policy before they
+// are returned. It is stateless and safe for concurrent use.
+type OutputGuard struct{}
+
+// NewOutputGuard creates an output guard.
+func NewOutputGuard() *OutputGuard {
+	return &OutputGuard{}
+}
+
+// Check returns every policy violation in the answer.
+func (g *OutputGuard) Check(in OutputInput) []Violation {
+	var violations []Violation
+	add := func(check, detail string) {
+		if in.Policy.Enabled(check) {
+			violations = append(violations, Violation{Check: check, Detail: detail})
+		}
+	}
+	answer := in.Answer
+	lower := strings.ToLower(answer)
+	kb := strings.ToLower(strings.Join(in.KnowledgeBase, "\n"))
+
+	for _, name := range in.Policy.Competitors {
+		if name != "" && containsWord(lower, strings.ToLower(name)) {
+			add(config.CheckCompetitorMention, name)
+			break
+		}
+	}
+	for _, phrase := range in.Policy.BlockedPhrases {
+		if phrase != "" && strings.Contains(lower, strings.ToLower(phrase)) {
+			add(config.CheckBlockedPhrase, phrase)
+			break
+		}
+	}
+	if sentence := unsupportedRefundPromise(answer, kb); sentence != "" {
+		add(config.CheckRefundPromise, sentence)
+	}
+	if m := legalPatterns.FindString(answer); m != "" {
+		add(config.CheckLegalAdvice, m)
+	}
+	if m := medicalPatterns.FindString(answer); m != "" {
+		add(config.CheckMedicalAdvice, m)
+	}
+	if leaksPrompt(answer, in.SystemPrompt, kb) {
+		add(config.CheckPromptLeak, "")
+	}
+	for _, u := range urlPattern.FindAllString(answer, -1) {
+		u = strings.TrimRight(u, ".,;:!?")
+		if !urlAllowed(u, kb, in.Policy.AllowedDomains) {
+			add(config.CheckUnknownURL, u)
+			break
+		}
+	}
+	return violations
+}
+
+// unsupportedRefundPromise returns the first sentence that promises a refund
+// the knowledge base does not back: either the knowledge base never mentions
+// refunds, or the sentence quotes numbers (days, amounts, percentages) the
+// knowledge base does not contain.
+func unsupportedRefundPromise(answer, kb string) string {
+	kbMentionsRefund := refundPattern.MatchString(kb)
+	kbNumbers := map[string]bool{}
+	for _, n := range numberPattern.FindAllString(kb, -1) {
+		kbNumbers[n] = true
+	}
+	for _, sentence := range sentenceSplit.Split(answer, -1) {
+		if !refundPattern.MatchString(sentence) || !promisePattern.MatchString(sentence) {
+			continue
+		}
+		if !kbMentionsRefund {
+			return strings.TrimSpace(sentence)
+		}
+		for _, n := range numberPattern.FindAllString(sentence, -1) {
+			if !kbNumbers[n] {
+				return strings.TrimSpace(sentence)
+			}
+		}
+	}
+	return ""
+}
+
+// leaksPrompt detects answers that quote the system prompt (any run of eight
+// consecutive words that is not also in the knowledge base) or describe it.
+func leaksPrompt(answer, systemPrompt, kb string) bool {
+	if leakPattern.MatchString(answer) {
+		return true
+	}
+	const n = 8
+	words := strings.Fields(normalizeWords(systemPrompt))
+	if len(words) < n {
+		return false
+	}
+	normAnswer := " " + normalizeWords(answer) + " "
+	normKB := " " + normalizeWords(kb) + " "
+	for i := 0; i+n <= len(words); i++ {
+		shingle := " " + strings.Join(words[i:i+n], " ") + " "
+		if strings.Contains(normAnswer, shingle) && !strings.Contains(normKB, shingle) {
+			return true
+		}
+	}
+	return false
+}
+
+// urlAllowed accepts links that appear in the knowledge base or whose host is
+// (a subdomain of) an allowed domain.
+func urlAllowed(raw, kb string, allowedDomains []string) bool {
+	if strings.Contains(kb, strings.ToLower(raw)) {
+		return true
+	}
+	target := raw
+	if !strings.Contains(target, "://") {
+		target = "https://" + target
+	}
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(strings.TrimPrefix(parsed.Hostname(), "www."))
+	for _, d := range allowedDomains {
+		d = strings.ToLower(strings.TrimPrefix(d, "www."))
+		if host == d || strings.HasSuffix(host, "."+d) {
+			return true
+		}
+	}
+	// A bare host mentioned in the knowledge base allows any path on it
+	return host != "" && strings.Contains(kb, host)
+}
+
+func containsWord(text, word string) bool {
+	re, err := regexp.Compile(`\b` + regexp.QuoteMeta(word) + `\b`)
+	if err != nil {
+		return strings.Contains(text, word)
+	}
+	return re.MatchString(text)
+}
+
+// normalizeWords lower-cases text and reduces it to words separated by single spaces.
+func normalizeWords(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if r == '\'' || r == '-' || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r > 127 {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
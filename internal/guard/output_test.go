@@ -0,0 +1,69 @@
+package guard
+
+import (
+	"testing"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+)
+
+func TestOutputGuard_Check(t *testing.T) {
+	kb := []string{
+		"Refunds are available within 30 days of delivery for unused items.",
+		"Track your order at https://shop.example.com/track.",
+	}
+	systemPrompt := "You are a helpful Tier-1 customer support assistant. Your role is to answer customer questions based on the provided knowledge base."
+	policy := config.OutputPolicy{
+		Competitors:    []string{"MegaMart"},
+		BlockedPhrases: []string{"free shipping forever"},
+		AllowedDomains: []string{"help.example.com"},
+	}
+
+	tests := []struct {
+		name   string
+		answer string
+		policy config.OutputPolicy
+		want   string // Expected first violation; empty for none
+	}{
+		{name: "clean answer", answer: "You can request a refund within 30 days of delivery.", policy: policy},
+		{name: "competitor", answer: "MegaMart sells it cheaper.", policy: policy, want: config.CheckCompetitorMention},
+		{name: "competitor substring is fine", answer: "Our megamartial arts kit ships tomorrow.", policy: policy},
+		{name: "blocked phrase", answer: "Enjoy free shipping forever!", policy: policy, want: config.CheckBlockedPhrase},
+		{name: "refund beyond policy", answer: "We will give you a full refund within 90 days.", policy: policy, want: config.CheckRefundPromise},
+		{name: "refund within policy", answer: "You will get a refund if you return it within 30 days.", policy: policy},
+		{name: "legal advice", answer: "You should sue the courier for this.", policy: policy, want: config.CheckLegalAdvice},
+		{name: "medical advice", answer: "Take 400 mg of ibuprofen twice a day.", policy: policy, want: config.CheckMedicalAdvice},
+		{name: "prompt leak", answer: "Sure: you are a helpful Tier-1 customer support assistant. Your role is to answer", policy: policy, want: config.CheckPromptLeak},
+		{name: "describes instructions", answer: "My instructions are to only use the knowledge base.", policy: policy, want: config.CheckPromptLeak},
+		{name: "url from kb", answer: "Track it at https://shop.example.com/track.", policy: policy},
+		{name: "allowed domain", answer: "See https://help.example.com/returns for details.", policy: policy},
+		{name: "unknown url", answer: "Download the form at http://evil.example.net/form.", policy: policy, want: config.CheckUnknownURL},
+		{name: "disabled check", answer: "MegaMart sells it cheaper.", policy: config.OutputPolicy{
+			Competitors:    []string{"MegaMart"},
+			DisabledChecks: []string{config.CheckCompetitorMention},
+		}},
+	}
+
+	g := NewOutputGuard()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := g.Check(OutputInput{Answer: tt.answer, KnowledgeBase: kb, SystemPrompt: systemPrompt, Policy: tt.policy})
+			got := ""
+			if len(violations) > 0 {
+				got = violations[0].Check
+			}
+			if got != tt.want {
+				t.Errorf("Check(%q) = %+v, want first violation %q", tt.answer, violations, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutputGuard_RefundWithoutPolicy(t *testing.T) {
+	violations := NewOutputGuard().Check(OutputInput{
+		Answer:        "Don't worry, we will refund you.",
+		KnowledgeBase: []string{"Orders ship within 2 business days."},
+	})
+	if len(violations) != 1 || violations[0].Check != config.CheckRefundPromise {
+		t.Errorf("Check() = %+v, want refund_promise", violations)
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/auth"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/middleware"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+// IssueAPIKeyRequest represents the request body for issuing an API key
+type IssueAPIKeyRequest struct {
+	TenantID string   `json:"tenant_id"`
+	Name     string   `json:"name"`
+	Scopes   []string `json:"scopes" binding:"required"`
+}
+
+// APIKeyResponse is returned when a key is issued or rotated. The plaintext
+// key is only ever returned here.
+type APIKeyResponse struct {
+	Key    auth.APIKey `json:"key"`
+	APIKey string      `json:"api_key,omitempty"`
+}
+
+// APIKeyHandler exposes API key management endpoints (admin scope)
+type APIKeyHandler struct {
+	keys    *auth.KeyStore
+	tenants *tenant.Registry
+}
+
+// NewAPIKeyHandler creates a new API key handler. When tenants is non-nil,
+// keys can only be issued for registered tenants.
+func NewAPIKeyHandler(keys *auth.KeyStore, tenants *tenant.Registry) *APIKeyHandler {
+	return &APIKeyHandler{keys: keys, tenants: tenants}
+}
+
+// Issue handles POST /v1/admin/keys
+func (h *APIKeyHandler) Issue(c *gin.Context) {
+	var req IssueAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: "+err.Error())
+		return
+	}
+
+	identity, _ := middleware.IdentityFrom(c)
+	tenantID := req.TenantID
+	if identity != nil && !identity.IsPlatform() {
+		if tenantID == "" {
+			tenantID = identity.TenantID
+		}
+		if tenantID != identity.TenantID {
+			respondError(c, http.StatusForbidden, "FORBIDDEN", "Cannot manage keys for another tenant")
+			return
+		}
+	}
+	if tenantID == "" {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: tenant_id is required")
+		return
+	}
+
+	if h.tenants != nil {
+		if _, err := h.tenants.Get(tenantID); err != nil {
+			respondTenantError(c, err)
+			return
+		}
+	}
+
+	scopes, err := auth.ParseScopes(req.Scopes)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: "+err.Error())
+		return
+	}
+
+	key, plaintext, err := h.keys.Issue(tenantID, req.Name, scopes)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: "+err.Error())
+		return
+	}
+
+	logger.Info("api key issued", map[string]interface{}{
+		"key_id":    key.ID,
+		"tenant_id": key.TenantID,
+		"issued_by": identity.Subject(),
+	})
+	c.JSON(http.StatusCreated, APIKeyResponse{Key: key, APIKey: plaintext})
+}
+
+// List handles GET /v1/admin/keys
+func (h *APIKeyHandler) List(c *gin.Context) {
+	identity, _ := middleware.IdentityFrom(c)
+	tenantID := c.Query("tenant_id")
+	if identity != nil && !identity.IsPlatform() {
+		if tenantID != "" && tenantID != identity.TenantID {
+			respondError(c, http.StatusForbidden, "FORBIDDEN", "Cannot manage keys for another tenant")
+			return
+		}
+		tenantID = identity.TenantID
+	}
+
+	c.JSON(http.StatusOK, gin.H{"keys": h.keys.List(tenantID)})
+}
+
+// Rotate handles POST /v1/admin/keys/:id/rotate
+func (h *APIKeyHandler) Rotate(c *gin.Context) {
+	identity, _ := middleware.IdentityFrom(c)
+	if _, ok := h.authorizeKey(c, identity); !ok {
+		return
+	}
+
+	key, plaintext, err := h.keys.Rotate(c.Param("id"))
+	if err != nil {
+		h.respondKeyError(c, err)
+		return
+	}
+
+	logger.Info("api key rotated", map[string]interface{}{
+		"old_key_id": c.Param("id"),
+		"key_id":     key.ID,
+		"tenant_id":  key.TenantID,
+		"rotated_by": identity.Subject(),
+	})
+	c.JSON(http.StatusCreated, APIKeyResponse{Key: key, APIKey: plaintext})
+}
+
+// Revoke handles DELETE /v1/admin/keys/:id
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	identity, _ := middleware.IdentityFrom(c)
+	if _, ok := h.authorizeKey(c, identity); !ok {
+		return
+	}
+
+	key, err := h.keys.Revoke(c.Param("id"))
+	if err != nil {
+		h.respondKeyError(c, err)
+		return
+	}
+
+	logger.Info("api key revoked", map[string]interface{}{
+		"key_id":     key.ID,
+		"tenant_id":  key.TenantID,
+		"revoked_by": identity.Subject(),
+	})
+	c.JSON(http.StatusOK, APIKeyResponse{Key: key})
+}
+
+// authorizeKey loads the key referenced by :id and checks the caller may manage it.
+func (h *APIKeyHandler) authorizeKey(c *gin.Context, identity *auth.Identity) (auth.APIKey, bool) {
+	key, err := h.keys.Get(c.Param("id"))
+	if err != nil {
+		h.respondKeyError(c, err)
+		return auth.APIKey{}, false
+	}
+	if identity != nil && !identity.CanAccessTenant(key.TenantID) {
+		// Do not reveal keys that belong to other tenants.
+		respondError(c, http.StatusNotFound, "KEY_NOT_FOUND", "API key not found")
+		return auth.APIKey{}, false
+	}
+	return key, true
+}
+
+func (h *APIKeyHandler) respondKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, auth.ErrKeyNotFound):
+		respondError(c, http.StatusNotFound, "KEY_NOT_FOUND", "API key not found")
+	case errors.Is(err, auth.ErrKeyRevoked):
+		respondError(c, http.StatusConflict, "KEY_REVOKED", "API key has been revoked")
+	default:
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to update API key")
+	}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// BatchQueryRequest is the body of POST /v1/support/query:batch. Items may
+// mix tenants and languages when the caller is a platform identity.
+type BatchQueryRequest struct {
+	Queries []SupportQueryRequest `json:"queries" binding:"required"`
+}
+
+// BatchQueryResult is the outcome of one item, in request order.
+type BatchQueryResult struct {
+	Index    int                   `json:"index"`
+	Status   int                   `json:"status"` // HTTP status the item would have had on its own
+	Response *SupportQueryResponse `json:"response,omitempty"`
+	Error    *queryError           `json:"error,omitempty"`
+}
+
+// BatchQueryResponse holds every item's result.
+type BatchQueryResponse struct {
+	Results   []BatchQueryResult `json:"results"`
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+}
+
+// QueryMethod handles POST /v1/support/query:<method>. The router reads
+// ":batch" as a path parameter, so unknown methods are rejected here.
+func (h *SupportHandler) QueryMethod(c *gin.Context) {
+	switch c.Param("method") {
+	case ":batch":
+		h.BatchQuery(c)
+	default:
+		respondError(c, http.StatusNotFound, "METHOD_NOT_FOUND", "Unknown query method")
+	}
+}
+
+// BatchQuery handles POST /v1/support/query:batch requests. Items are
+// answered like single queries, a bounded number at a time; each waits for
+// its tenant's rate limit and is checked against its tenant's budget.
+func (h *SupportHandler) BatchQuery(c *gin.Context) {
+	var req BatchQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: "+err.Error())
+		return
+	}
+	if len(req.Queries) == 0 || len(req.Queries) > h.batchMaxItems {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("Invalid request: queries must have between 1 and %d items", h.batchMaxItems))
+		return
+	}
+
+	identity, _ := middleware.IdentityFrom(c)
+	requestID := c.GetString(middleware.CtxRequestID)
+	ctx := c.Request.Context()
+
+	results := make([]BatchQueryResult, len(req.Queries))
+	sem := make(chan struct{}, h.batchConcurrency)
+	var wg sync.WaitGroup
+	for i, item := range req.Queries {
+		results[i].Index = i
+		if strings.TrimSpace(item.Question) == "" {
+			results[i].Status = http.StatusBadRequest
+			results[i].Error = &queryError{http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: question is required"}
+			continue
+		}
+		if qerr := resolveTenant(&item, identity); qerr != nil {
+			results[i].Status = qerr.Status
+			results[i].Error = qerr
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, item SupportQueryRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			// Each item gets its own response ID for feedback
+			resp, qerr := h.query(ctx, item, identity, requestID, newResponseID(), h.batchRateWait)
+			if qerr != nil {
+				results[i].Status = qerr.Status
+				results[i].Error = qerr
+				return
+			}
+			results[i].Status = http.StatusOK
+			results[i].Response = &resp
+		}(i, item)
+	}
+	wg.Wait()
+
+	out := BatchQueryResponse{Results: results}
+	for _, r := range results {
+		if r.Error != nil {
+			out.Failed++
+		} else {
+			out.Succeeded++
+		}
+	}
+	if h.metrics != nil {
+		h.metrics.BatchItemsTotal.Add(int64(len(results)))
+	}
+	logger.Info("batch query completed", map[string]interface{}{
+		"request_id": requestID,
+		"subject":    identity.Subject(),
+		"items":      len(results),
+		"succeeded":  out.Succeeded,
+		"failed":     out.Failed,
+	})
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/experiment"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/llm"
+	"github.com/gin-gonic/gin"
+)
+
+// fakeLLM answers with the question it was asked. Questions containing
+// "fail" return an error. When release is set, every call blocks until it
+// is closed; delay, when set, picks a per-question sleep.
+type fakeLLM struct {
+	release chan struct{}
+	delay   func(question string) time.Duration
+
+	mu          sync.Mutex
+	calls       int
+	inFlight    int
+	maxInFlight int
+}
+
+func (f *fakeLLM) GenerateAnswer(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	question := req.Messages[0].Content
+
+	f.mu.Lock()
+	f.calls++
+	f.inFlight++
+	if f.inFlight > f.maxInFlight {
+		f.maxInFlight = f.inFlight
+	}
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+
+	if f.release != nil {
+		select {
+		case <-f.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if f.delay != nil {
+		time.Sleep(f.delay(question))
+	}
+	if strings.Contains(question, "fail") {
+		return nil, errors.New("model unavailable")
+	}
+	return &llm.Response{Content: "answer to " + question, Confidence: 0.9, TokensUsed: 10}, nil
+}
+
+func (f *fakeLLM) stats() (calls, inFlight, maxInFlight int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls, f.inFlight, f.maxInFlight
+}
+
+// staticRetriever returns the same knowledge for every question, so queries
+// always reach the LLM.
+type staticRetriever struct{}
+
+func (staticRetriever) Retrieve(context.Context, string, string, string) ([]string, error) {
+	return []string{"Orders ship within 1-2 business days."}, nil
+}
+
+func newTestHandler(client llm.Client, opts ...SupportHandlerOption) *SupportHandler {
+	opts = append([]SupportHandlerOption{WithRetriever(staticRetriever{})}, opts...)
+	return NewSupportHandler(client, nil, nil, nil, nil, nil, opts...)
+}
+
+type batchResult struct {
+	Index    int                   `json:"index"`
+	Status   int                   `json:"status"`
+	Response *SupportQueryResponse `json:"response"`
+	Error    *struct {
+		Code string `json:"code"`
+	} `json:"error"`
+}
+
+func postBatch(t *testing.T, h *SupportHandler, questions ...string) (int, []batchResult, int, int) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/batch", h.BatchQuery)
+
+	queries := make([]SupportQueryRequest, len(questions))
+	for i, q := range questions {
+		queries[i] = SupportQueryRequest{TenantID: "shop-123", Language: "en", Question: q}
+	}
+	body, _ := json.Marshal(BatchQueryRequest{Queries: queries})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/batch", bytes.NewReader(body)))
+
+	var out struct {
+		Results   []batchResult `json:"results"`
+		Succeeded int           `json:"succeeded"`
+		Failed    int           `json:"failed"`
+	}
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+	}
+	return w.Code, out.Results, out.Succeeded, out.Failed
+}
+
+func TestBatchQuery_PreservesOrder(t *testing.T) {
+	// Earlier items finish last
+	client := &fakeLLM{delay: func(q string) time.Duration {
+		var i int
+		fmt.Sscanf(q, "Where is order %d?", &i)
+		return time.Duration(4-i) * 10 * time.Millisecond
+	}}
+	h := newTestHandler(client, WithBatchLimits(10, 4, time.Second))
+
+	questions := []string{"Where is order 0?", "Where is order 1?", "Where is order 2?", "Where is order 3?"}
+	status, results, succeeded, _ := postBatch(t, h, questions...)
+	if status != http.StatusOK || succeeded != len(questions) {
+		t.Fatalf("status = %d, succeeded = %d", status, succeeded)
+	}
+	ids := map[string]bool{}
+	for i, r := range results {
+		if r.Index != i || r.Response == nil || !strings.Contains(r.Response.Answer, questions[i]) {
+			t.Errorf("result %d = %+v, want answer to %q", i, r, questions[i])
+			continue
+		}
+		if !strings.HasPrefix(r.Response.ResponseID, "resp_") || ids[r.Response.ResponseID] {
+			t.Errorf("result %d response_id = %q, want a unique server-generated ID", i, r.Response.ResponseID)
+		}
+		ids[r.Response.ResponseID] = true
+	}
+}
+
+func TestBatchQuery_MaxItems(t *testing.T) {
+	client := &fakeLLM{}
+	h := newTestHandler(client, WithBatchLimits(2, 2, time.Second))
+
+	if status, _, _, _ := postBatch(t, h, "a?", "b?", "c?"); status != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400 over the item limit", status)
+	}
+	if calls, _, _ := client.stats(); calls != 0 {
+		t.Errorf("LLM calls = %d, want none for a rejected batch", calls)
+	}
+}
+
+func TestBatchQuery_ItemErrors(t *testing.T) {
+	h := newTestHandler(&fakeLLM{}, WithBatchLimits(10, 2, time.Second))
+
+	status, results, succeeded, failed := postBatch(t, h, "Where is my order?", "  ", "Please fail this one", "How do refunds work?")
+	if status != http.StatusOK || succeeded != 2 || failed != 2 {
+		t.Fatalf("status = %d, succeeded = %d, failed = %d; want 200, 2, 2", status, succeeded, failed)
+	}
+	want := []struct {
+		status int
+		code   string
+	}{
+		{http.StatusOK, ""},
+		{http.StatusBadRequest, "INVALID_REQUEST"},
+		{http.StatusInternalServerError, "INTERNAL_ERROR"},
+		{http.StatusOK, ""},
+	}
+	for i, w := range want {
+		r := results[i]
+		code := ""
+		if r.Error != nil {
+			code = r.Error.Code
+		}
+		if r.Status != w.status || code != w.code || (w.code == "") != (r.Response != nil) {
+			t.Errorf("result %d = status %d code %q, want %d %q", i, r.Status, code, w.status, w.code)
+		}
+	}
+}
+
+func TestBatchQuery_ConcurrencyLimit(t *testing.T) {
+	client := &fakeLLM{release: make(chan struct{})}
+	h := newTestHandler(client, WithBatchLimits(10, 2, time.Second))
+
+	done := make(chan int, 1)
+	go func() {
+		_, _, succeeded, _ := postBatch(t, h, "q1?", "q2?", "q3?", "q4?", "q5?", "q6?")
+		done <- succeeded
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, inFlight, _ := client.stats(); inFlight == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("batch never reached 2 concurrent LLM calls")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	// Give a third item the chance to start if the limit were not enforced
+	time.Sleep(50 * time.Millisecond)
+	if _, inFlight, _ := client.stats(); inFlight != 2 {
+		t.Errorf("in-flight LLM calls = %d, want 2", inFlight)
+	}
+	close(client.release)
+
+	if succeeded := <-done; succeeded != 6 {
+		t.Errorf("succeeded = %d, want 6", succeeded)
+	}
+	if calls, _, maxInFlight := client.stats(); calls != 6 || maxInFlight != 2 {
+		t.Errorf("calls = %d, max in flight = %d; want 6, 2", calls, maxInFlight)
+	}
+}
+
+func TestBatchQuery_ExperimentAssignsPerItem(t *testing.T) {
+	store := experiment.NewStore()
+	if _, err := store.Create("shop-123", "models", []experiment.Variant{
+		{Name: "control", Weight: 1},
+		{Name: "candidate", Weight: 1, Model: "gpt-4o"},
+	}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	h := newTestHandler(&fakeLLM{}, WithExperiments(store), WithBatchLimits(20, 4, time.Second))
+
+	questions := make([]string, 20)
+	for i := range questions {
+		questions[i] = fmt.Sprintf("Where is order %d?", i)
+	}
+	_, results, succeeded, _ := postBatch(t, h, questions...)
+	if succeeded != len(questions) {
+		t.Fatalf("succeeded = %d, want %d", succeeded, len(questions))
+	}
+	variants := map[string]int{}
+	for _, r := range results {
+		variants[r.Response.Variant]++
+	}
+	if variants["control"] == 0 || variants["candidate"] == 0 {
+		t.Errorf("variants = %v, want anonymous batch items spread across both", variants)
+	}
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+// respondError writes the standard error envelope used by every endpoint.
+func respondError(c *gin.Context, status int, code, message string) {
+	c.JSON(status, gin.H{
+		"error": gin.H{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+// queryError is a failed support query, written as the standard error
+// envelope or as one item of a batch.
+type queryError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *queryError) respond(c *gin.Context) {
+	respondError(c, e.Status, e.Code, e.Message)
+}
+
+// messageError is a queryError with a customer-facing message from the
+// tenant's catalog, in the customer's locale.
+func messageError(status int, code string, settings config.TenantSettings, key, tag string) *queryError {
+	return &queryError{Status: status, Code: code, Message: settings.Message(key, tag)}
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/experiment"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/middleware"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/prompts"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateExperimentRequest represents the request body for starting an experiment
+type CreateExperimentRequest struct {
+	TenantID string               `json:"tenant_id"`
+	Name     string               `json:"name"`
+	Variants []experiment.Variant `json:"variants" binding:"required"`
+}
+
+// ExperimentHandler exposes experiment endpoints (admin scope)
+type ExperimentHandler struct {
+	store   *experiment.Store
+	tenants *tenant.Registry
+	prompts *prompts.Store
+}
+
+// NewExperimentHandler creates a new experiment handler. When set, tenants
+// and prompts are used to validate new experiments.
+func NewExperimentHandler(store *experiment.Store, tenants *tenant.Registry, promptStore *prompts.Store) *ExperimentHandler {
+	return &ExperimentHandler{store: store, tenants: tenants, prompts: promptStore}
+}
+
+// Create handles POST /v1/admin/experiments
+func (h *ExperimentHandler) Create(c *gin.Context) {
+	var req CreateExperimentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: "+err.Error())
+		return
+	}
+
+	identity, _ := middleware.IdentityFrom(c)
+	if identity != nil && !identity.IsPlatform() {
+		if req.TenantID == "" {
+			req.TenantID = identity.TenantID
+		}
+		if req.TenantID != identity.TenantID {
+			respondError(c, http.StatusForbidden, "FORBIDDEN", "Cannot manage experiments for another tenant")
+			return
+		}
+	}
+	if h.tenants != nil && req.TenantID != "" {
+		if _, err := h.tenants.Get(req.TenantID); err != nil {
+			respondTenantError(c, err)
+			return
+		}
+	}
+	for _, v := range req.Variants {
+		if v.PromptVersion == 0 || h.prompts == nil {
+			continue
+		}
+		if _, err := h.prompts.Get(req.TenantID, v.PromptVersion); err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: variant "+v.Name+" references an unknown prompt version")
+			return
+		}
+	}
+
+	exp, err := h.store.Create(req.TenantID, req.Name, req.Variants)
+	if err != nil {
+		h.respondExperimentError(c, err)
+		return
+	}
+
+	logger.Audit("experiment started", map[string]interface{}{
+		"experiment_id": exp.ID,
+		"tenant_id":     exp.TenantID,
+		"variants":      len(exp.Variants),
+		"subject":       identity.Subject(),
+	})
+	c.JSON(http.StatusCreated, exp)
+}
+
+// List handles GET /v1/admin/experiments
+func (h *ExperimentHandler) List(c *gin.Context) {
+	identity, _ := middleware.IdentityFrom(c)
+	tenantID := c.Query("tenant_id")
+	if identity != nil && !identity.IsPlatform() {
+		if tenantID != "" && tenantID != identity.TenantID {
+			respondError(c, http.StatusForbidden, "FORBIDDEN", "Cannot manage experiments for another tenant")
+			return
+		}
+		tenantID = identity.TenantID
+	}
+	c.JSON(http.StatusOK, gin.H{"experiments": h.store.List(tenantID)})
+}
+
+// Get handles GET /v1/admin/experiments/:id
+func (h *ExperimentHandler) Get(c *gin.Context) {
+	exp, ok := h.authorizeExperiment(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, exp)
+}
+
+// Report handles GET /v1/admin/experiments/:id/report
+func (h *ExperimentHandler) Report(c *gin.Context) {
+	exp, ok := h.authorizeExperiment(c)
+	if !ok {
+		return
+	}
+	report, err := h.store.Report(exp.ID)
+	if err != nil {
+		h.respondExperimentError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Stop handles POST /v1/admin/experiments/:id/stop
+func (h *ExperimentHandler) Stop(c *gin.Context) {
+	exp, ok := h.authorizeExperiment(c)
+	if !ok {
+		return
+	}
+	exp, err := h.store.Stop(exp.ID)
+	if err != nil {
+		h.respondExperimentError(c, err)
+		return
+	}
+
+	identity, _ := middleware.IdentityFrom(c)
+	logger.Audit("experiment stopped", map[string]interface{}{
+		"experiment_id": exp.ID,
+		"tenant_id":     exp.TenantID,
+		"subject":       identity.Subject(),
+	})
+	c.JSON(http.StatusOK, exp)
+}
+
+// authorizeExperiment loads the experiment referenced by :id and checks the caller may manage it.
+func (h *ExperimentHandler) authorizeExperiment(c *gin.Context) (experiment.Experiment, bool) {
+	exp, err := h.store.Get(c.Param("id"))
+	if err != nil {
+		h.respondExperimentError(c, err)
+		return experiment.Experiment{}, false
+	}
+	if identity, ok := middleware.IdentityFrom(c); ok && !identity.CanAccessTenant(exp.TenantID) {
+		respondError(c, http.StatusNotFound, "EXPERIMENT_NOT_FOUND", "Experiment not found")
+		return experiment.Experiment{}, false
+	}
+	return exp, true
+}
+
+func (h *ExperimentHandler) respondExperimentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, experiment.ErrExperimentNotFound):
+		respondError(c, http.StatusNotFound, "EXPERIMENT_NOT_FOUND", "Experiment not found")
+	case errors.Is(err, experiment.ErrExperimentRunning):
+		respondError(c, http.StatusConflict, "EXPERIMENT_RUNNING", "Tenant already has a running experiment")
+	case errors.Is(err, experiment.ErrInvalidExperiment):
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: "+err.Error())
+	default:
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to update experiment")
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/calibration"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+func TestFeedback_CalibrationSamples(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "samples.jsonl")
+	settings := config.BuiltinTenantSettings()
+	settings.ConfidenceThreshold = 0.95 // The fake model's 0.9 falls back
+	h := newTestHandler(&fakeLLM{},
+		WithDefaultSettings(settings),
+		WithCalibrationSamples(calibration.NewRecorder(path)))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/feedback", h.Feedback)
+	feedback := func(body string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/feedback", bytes.NewReader([]byte(body))))
+		return w.Code
+	}
+
+	_, results, _, _ := postBatch(t, h, "Where is my order?", "How do refunds work?")
+	for i, r := range results {
+		if r.Response == nil || !r.Response.Fallback {
+			t.Fatalf("result %d = %+v, want a fallback answer", i, r)
+		}
+	}
+	if code := feedback(`{"response_id":"` + results[0].Response.ResponseID + `","escalated":true}`); code != http.StatusAccepted {
+		t.Errorf("escalated feedback status = %d, want 202", code)
+	}
+	if code := feedback(`{"response_id":"` + results[1].Response.ResponseID + `","score":5}`); code != http.StatusAccepted {
+		t.Errorf("scored feedback status = %d, want 202", code)
+	}
+	if code := feedback(`{"response_id":"` + results[1].Response.ResponseID + `"}`); code != http.StatusBadRequest {
+		t.Errorf("feedback without score status = %d, want 400", code)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	samples, err := calibration.ReadSamples(bytes.NewReader(data))
+	if err != nil || len(samples) != 2 {
+		t.Fatalf("samples = %+v, %v; want one per fallback answer", samples, err)
+	}
+	if !samples[0].Escalated || samples[0].Positive() || samples[0].Confidence != 0.9 {
+		t.Errorf("escalated sample = %+v, want unhelpful at the raw score", samples[0])
+	}
+	if !samples[1].Positive() {
+		t.Errorf("scored sample = %+v, want helpful", samples[1])
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/auth"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/middleware"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/reliability"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader names a request so retries can be recognised
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed for a retried key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// beginIdempotent claims the request's Idempotency-Key. It returns the store
+// key to Complete once the query succeeds, or done == true when the response
+// has already been written: a replay or an error. Keys are scoped to the
+// tenant and caller, so they never leak answers between customers.
+func (h *SupportHandler) beginIdempotent(c *gin.Context, req SupportQueryRequest, identity *auth.Identity) (key string, done bool) {
+	header := c.GetHeader(IdempotencyKeyHeader)
+	if header == "" || h.idempotency == nil {
+		return "", false
+	}
+	if len(header) > maxIdempotencyKeyLength {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: Idempotency-Key must be at most 255 characters")
+		return "", true
+	}
+
+	key = req.TenantID + "|" + identity.Subject() + "|" + header
+	cached, replay, err := h.idempotency.Begin(key, requestFingerprint(req))
+	switch {
+	case errors.Is(err, reliability.ErrIdempotencyKeyReused):
+		respondError(c, http.StatusUnprocessableEntity, "IDEMPOTENCY_KEY_REUSED", "Idempotency-Key was already used with a different request")
+		return "", true
+	case errors.Is(err, reliability.ErrIdempotencyInProgress):
+		respondError(c, http.StatusConflict, "IDEMPOTENCY_IN_PROGRESS", "A request with this Idempotency-Key is still in progress")
+		return "", true
+	case replay:
+		if h.metrics != nil {
+			h.metrics.IdempotentReplaysTotal.Add(1)
+		}
+		logger.Info("idempotent replay", map[string]interface{}{
+			"request_id":  c.GetString(middleware.CtxRequestID),
+			"tenant_id":   req.TenantID,
+			"subject":     identity.Subject(),
+			"response_id": cached.ResponseID,
+		})
+		c.Header(IdempotentReplayedHeader, "true")
+		c.JSON(http.StatusOK, cached)
+		return "", true
+	}
+	return key, false
+}
+
+// requestFingerprint identifies a request body regardless of formatting.
+func requestFingerprint(req SupportQueryRequest) string {
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
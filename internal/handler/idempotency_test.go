@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/auth"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/middleware"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/reliability"
+	"github.com/gin-gonic/gin"
+)
+
+func newIdempotentRouter(client *fakeLLM) *gin.Engine {
+	h := newTestHandler(client, WithIdempotency(reliability.NewIdempotencyStore[SupportQueryResponse](time.Hour)))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/query", func(c *gin.Context) {
+		// The same API key ID under each tenant, so only the tenant differs
+		c.Set(middleware.CtxIdentity, &auth.Identity{Method: "api_key", KeyID: "key-1", TenantID: c.GetHeader("X-Tenant")})
+		h.SupportQuery(c)
+	})
+	return router
+}
+
+// postQuery sends a query as tenantID's key with the given Idempotency-Key.
+func postQuery(router *gin.Engine, tenantID, key, question string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(SupportQueryRequest{Language: "en", Question: question})
+	req := httptest.NewRequest(http.MethodPost, "/query", bytes.NewReader(body))
+	req.Header.Set("X-Tenant", tenantID)
+	req.Header.Set(IdempotencyKeyHeader, key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var out struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &out)
+	return out.Error.Code
+}
+
+func TestSupportQuery_IdempotentReplay(t *testing.T) {
+	client := &fakeLLM{}
+	router := newIdempotentRouter(client)
+
+	first := postQuery(router, "shop-123", "order-1", "Where is my order?")
+	if first.Code != http.StatusOK {
+		t.Fatalf("first status = %d: %s", first.Code, first.Body.String())
+	}
+	replay := postQuery(router, "shop-123", "order-1", "Where is my order?")
+	if replay.Code != http.StatusOK || replay.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("replay status = %d, replayed header = %q", replay.Code, replay.Header().Get(IdempotentReplayedHeader))
+	}
+	if replay.Body.String() != first.Body.String() {
+		t.Errorf("replay body = %s, want %s", replay.Body.String(), first.Body.String())
+	}
+	if calls, _, _ := client.stats(); calls != 1 {
+		t.Errorf("LLM calls = %d, want 1", calls)
+	}
+
+	reused := postQuery(router, "shop-123", "order-1", "How do refunds work?")
+	if reused.Code != http.StatusUnprocessableEntity || errorCode(t, reused) != "IDEMPOTENCY_KEY_REUSED" {
+		t.Errorf("reused key = %d %s, want 422 IDEMPOTENCY_KEY_REUSED", reused.Code, errorCode(t, reused))
+	}
+}
+
+func TestSupportQuery_IdempotentInProgress(t *testing.T) {
+	client := &fakeLLM{release: make(chan struct{})}
+	router := newIdempotentRouter(client)
+
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() { done <- postQuery(router, "shop-123", "order-1", "Where is my order?") }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, inFlight, _ := client.stats(); inFlight == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("first request never reached the LLM")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	concurrent := postQuery(router, "shop-123", "order-1", "Where is my order?")
+	if concurrent.Code != http.StatusConflict || errorCode(t, concurrent) != "IDEMPOTENCY_IN_PROGRESS" {
+		t.Errorf("concurrent = %d %s, want 409 IDEMPOTENCY_IN_PROGRESS", concurrent.Code, errorCode(t, concurrent))
+	}
+	close(client.release)
+	if first := <-done; first.Code != http.StatusOK {
+		t.Errorf("first status = %d, want 200", first.Code)
+	}
+}
+
+func TestSupportQuery_IdempotentFailureRetries(t *testing.T) {
+	client := &fakeLLM{}
+	router := newIdempotentRouter(client)
+
+	for i := 0; i < 2; i++ {
+		w := postQuery(router, "shop-123", "order-1", "Please fail this one")
+		if w.Code != http.StatusInternalServerError || w.Header().Get(IdempotentReplayedHeader) != "" {
+			t.Errorf("attempt %d status = %d, replayed = %q; want a fresh 500", i+1, w.Code, w.Header().Get(IdempotentReplayedHeader))
+		}
+	}
+	if calls, _, _ := client.stats(); calls != 2 {
+		t.Errorf("LLM calls = %d, want the retry to run again", calls)
+	}
+}
+
+func TestSupportQuery_IdempotencyScopedToTenant(t *testing.T) {
+	router := newIdempotentRouter(&fakeLLM{})
+
+	first := postQuery(router, "shop-123", "order-1", "Where is my order?")
+	other := postQuery(router, "shop-456", "order-1", "Where is my order?")
+	if first.Code != http.StatusOK || other.Code != http.StatusOK {
+		t.Fatalf("status = %d, %d; want 200, 200", first.Code, other.Code)
+	}
+	if other.Header().Get(IdempotentReplayedHeader) != "" {
+		t.Error("another tenant's request with the same key was replayed")
+	}
+	var a, b SupportQueryResponse
+	json.Unmarshal(first.Body.Bytes(), &a)
+	json.Unmarshal(other.Body.Bytes(), &b)
+	if b.TenantID != "shop-456" || a.ResponseID == b.ResponseID {
+		t.Errorf("other tenant got %+v, want its own response", b)
+	}
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/auth"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/jobs"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/locale"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/middleware"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/observability"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/pii"
+	"github.com/gin-gonic/gin"
+)
+
+// jobRateWait is how long a job waits for its tenant's rate limit before the
+// attempt fails and is retried later; jobs have no caller waiting on them.
+const jobRateWait = 30 * time.Second
+
+// JobRequest is the body of POST /v1/support/jobs: a support query plus an
+// optional URL the finished job is POSTed to.
+type JobRequest struct {
+	SupportQueryRequest
+	CallbackURL string `json:"callback_url,omitempty"`
+}
+
+// jobPayload is what a job stores until it runs: the query, with personal
+// data already redacted, and the caller it runs as.
+type jobPayload struct {
+	Query  SupportQueryRequest `json:"query"`
+	Caller *jobCaller          `json:"caller,omitempty"`
+}
+
+// jobCaller is the part of the caller's identity a job needs: its tenant and
+// audit subject. Scopes, roles and the customer's name are not stored.
+type jobCaller struct {
+	Method     string `json:"method"`
+	KeyID      string `json:"key_id,omitempty"`
+	TenantID   string `json:"tenant_id,omitempty"`
+	CustomerID string `json:"customer_id,omitempty"`
+}
+
+// newJobPayload redacts the query the way the pipeline would, so the raw
+// question and knowledge base never reach the job store. Placeholders stay
+// in the answer, since the originals are not kept.
+func (h *SupportHandler) newJobPayload(req SupportQueryRequest, identity *auth.Identity) jobPayload {
+	if settings := h.settingsFor(req.TenantID); settings.PIIRedaction {
+		language := locale.BaseLanguage(locale.Canonical(req.Language))
+		req.Question, req.KnowledgeBase = redactInput(settings, language, req.Question, req.KnowledgeBase, pii.NewVault())
+	}
+	p := jobPayload{Query: req}
+	if identity != nil {
+		p.Caller = &jobCaller{
+			Method:     identity.Method,
+			KeyID:      identity.KeyID,
+			TenantID:   identity.TenantID,
+			CustomerID: identity.CustomerID,
+		}
+	}
+	return p
+}
+
+// identity rebuilds the caller for the pipeline.
+func (c *jobCaller) identity() *auth.Identity {
+	if c == nil {
+		return nil
+	}
+	return &auth.Identity{Method: c.Method, KeyID: c.KeyID, TenantID: c.TenantID, CustomerID: c.CustomerID}
+}
+
+// RunJob answers a queued query; it is the jobs.Func for the job queue. The
+// job ID becomes the response ID for feedback. Rate limits and internal
+// errors are retried by the queue.
+func (h *SupportHandler) RunJob(ctx context.Context, job jobs.Job, payload json.RawMessage) (json.RawMessage, *jobs.Error) {
+	var p jobPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, &jobs.Error{Code: "INVALID_REQUEST", Message: "Invalid request: " + err.Error()}
+	}
+	resp, qerr := h.query(ctx, p.Query, p.Caller.identity(), job.ID, job.ID, jobRateWait)
+	if qerr != nil {
+		return nil, &jobs.Error{
+			Code:      qerr.Code,
+			Message:   qerr.Message,
+			Retryable: qerr.Code == "RATE_LIMIT_EXCEEDED" || qerr.Status >= 500,
+		}
+	}
+	result, err := json.Marshal(resp)
+	if err != nil {
+		return nil, &jobs.Error{Code: "INTERNAL_ERROR", Message: err.Error(), Retryable: true}
+	}
+	return result, nil
+}
+
+// JobHandler handles asynchronous support queries.
+type JobHandler struct {
+	support  *SupportHandler
+	queue    *jobs.Queue
+	notifier *jobs.Notifier // Nil disables callbacks
+	metrics  *observability.Metrics
+}
+
+// NewJobHandler creates a job handler. The queue should run
+// support.RunJob.
+func NewJobHandler(support *SupportHandler, queue *jobs.Queue, notifier *jobs.Notifier, metrics *observability.Metrics) *JobHandler {
+	return &JobHandler{support: support, queue: queue, notifier: notifier, metrics: metrics}
+}
+
+// Submit handles POST /v1/support/jobs. The query is validated and queued;
+// the response is 202 with the job to poll.
+func (h *JobHandler) Submit(c *gin.Context) {
+	var req JobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(req.Question) == "" {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: question is required")
+		return
+	}
+	identity, _ := middleware.IdentityFrom(c)
+	if qerr := resolveTenant(&req.SupportQueryRequest, identity); qerr != nil {
+		qerr.respond(c)
+		return
+	}
+	c.Set(middleware.CtxTenantID, req.TenantID)
+
+	if req.CallbackURL != "" {
+		if h.notifier == nil {
+			respondError(c, http.StatusBadRequest, "INVALID_CALLBACK_URL", "Callbacks are not enabled")
+			return
+		}
+		if err := h.notifier.Check(req.CallbackURL); err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_CALLBACK_URL", "Invalid callback_url: "+err.Error())
+			return
+		}
+	}
+
+	payload, err := json.Marshal(h.support.newJobPayload(req.SupportQueryRequest, identity))
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to queue job")
+		return
+	}
+	job, err := h.queue.Submit(req.TenantID, payload, req.CallbackURL)
+	if errors.Is(err, jobs.ErrQueueFull) {
+		c.Header("Retry-After", "30")
+		respondError(c, http.StatusServiceUnavailable, "QUEUE_FULL", "Too many queued jobs. Please try again later.")
+		return
+	}
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to queue job")
+		return
+	}
+	if h.metrics != nil {
+		h.metrics.JobsSubmittedTotal.Add(1)
+	}
+
+	logger.Info("job queued", map[string]interface{}{
+		"request_id": c.GetString(middleware.CtxRequestID),
+		"job_id":     job.ID,
+		"tenant_id":  job.TenantID,
+		"subject":    identity.Subject(),
+		"callback":   job.CallbackURL != "",
+	})
+	c.Header("Location", "/v1/support/jobs/"+job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+// Get handles GET /v1/support/jobs/:id. Tenant identities only see their own
+// tenant's jobs; others are reported as not found.
+func (h *JobHandler) Get(c *gin.Context) {
+	job, err := h.queue.Get(c.Param("id"))
+	identity, _ := middleware.IdentityFrom(c)
+	if err != nil || (identity != nil && !identity.CanAccessTenant(job.TenantID)) {
+		respondError(c, http.StatusNotFound, "JOB_NOT_FOUND", "Job not found")
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/auth"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/jobs"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func TestJobHandler_SubmitStoresRedactedPayload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	support := newTestHandler(&fakeLLM{})
+	queue := jobs.NewQueue(support.RunJob, nil, jobs.Config{Path: path})
+	h := NewJobHandler(support, queue, nil, nil)
+
+	identity := &auth.Identity{
+		Method:       "jwt",
+		TenantID:     "shop-123",
+		Scopes:       []auth.Scope{auth.ScopeQuery},
+		CustomerID:   "cust-42",
+		CustomerName: "Jane Doe",
+		Roles:        []string{"customer"},
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/jobs", func(c *gin.Context) {
+		c.Set(middleware.CtxIdentity, identity)
+		h.Submit(c)
+	})
+	body, _ := json.Marshal(JobRequest{SupportQueryRequest: SupportQueryRequest{
+		Language: "en",
+		Question: "Where is my order? Reach me at jane@example.com",
+	}})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/jobs", bytes.NewReader(body)))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202: %s", w.Code, w.Body.String())
+	}
+
+	queue.Wait()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	for _, leaked := range []string{"jane@example.com", "Jane Doe", "roles", "scopes"} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("job store contains %q: %s", leaked, data)
+		}
+	}
+
+	var snapshot []struct {
+		Payload jobPayload `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &snapshot); err != nil || len(snapshot) != 1 {
+		t.Fatalf("snapshot = %s, %v", data, err)
+	}
+	caller := snapshot[0].Payload.Caller
+	if caller == nil || caller.TenantID != "shop-123" || caller.identity().Subject() != identity.Subject() {
+		t.Errorf("caller = %+v, want tenant and subject of %s", caller, identity.Subject())
+	}
+}
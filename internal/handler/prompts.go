@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/llm"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/locale"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/messages"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/middleware"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/pii"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/prompts"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePromptRequest represents the request body for a new prompt version
+type CreatePromptRequest struct {
+	System      string `json:"system" binding:"required"`
+	User        string `json:"user"`
+	Description string `json:"description"`
+	Activate    bool   `json:"activate"`
+}
+
+// PreviewPromptRequest represents the request body for rendering a prompt without calling the LLM
+type PreviewPromptRequest struct {
+	Question      string   `json:"question" binding:"required"`
+	Language      string   `json:"language" binding:"required"`
+	CustomerName  string   `json:"customer_name"`
+	Version       int      `json:"version"`        // 0 = active version (or built-in prompt)
+	KnowledgeBase []string `json:"knowledge_base"` // Appended to retrieved knowledge, as in queries
+}
+
+// PreviewPromptResponse shows exactly what would be sent to the LLM
+type PreviewPromptResponse struct {
+	TenantID string        `json:"tenant_id"`
+	Version  int           `json:"version"` // 0 = built-in prompt
+	Model    string        `json:"model,omitempty"`
+	Sources  []string      `json:"sources"`
+	Messages []llm.Message `json:"messages"`
+}
+
+// PromptHandler exposes tenant prompt template endpoints (admin scope)
+type PromptHandler struct {
+	store   *prompts.Store
+	tenants *tenant.Registry
+	support *SupportHandler
+}
+
+// NewPromptHandler creates a new prompt handler. Previews are rendered by
+// support, so they follow the same path as queries.
+func NewPromptHandler(store *prompts.Store, tenants *tenant.Registry, support *SupportHandler) *PromptHandler {
+	return &PromptHandler{
+		store:   store,
+		tenants: tenants,
+		support: support,
+	}
+}
+
+// Create handles POST /v1/admin/tenants/:id/prompts
+func (h *PromptHandler) Create(c *gin.Context) {
+	tenantID, ok := h.authorizeTenant(c)
+	if !ok {
+		return
+	}
+	var req CreatePromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: "+err.Error())
+		return
+	}
+
+	identity, _ := middleware.IdentityFrom(c)
+	v, err := h.store.Create(tenantID, req.System, req.User, req.Description, identity.Subject(), req.Activate)
+	if errors.Is(err, prompts.ErrInvalidTemplate) {
+		respondError(c, http.StatusBadRequest, "INVALID_TEMPLATE", err.Error())
+		return
+	}
+	if err != nil {
+		logger.Error("failed to create prompt version", map[string]interface{}{
+			"error":     err.Error(),
+			"tenant_id": tenantID,
+		})
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to create prompt version")
+		return
+	}
+
+	logger.Audit("prompt version created", map[string]interface{}{
+		"tenant_id": tenantID,
+		"version":   v.Version,
+		"activated": req.Activate,
+		"subject":   identity.Subject(),
+	})
+	c.JSON(http.StatusCreated, v)
+}
+
+// List handles GET /v1/admin/tenants/:id/prompts
+func (h *PromptHandler) List(c *gin.Context) {
+	tenantID, ok := h.authorizeTenant(c)
+	if !ok {
+		return
+	}
+	versions, active := h.store.List(tenantID)
+	c.JSON(http.StatusOK, gin.H{
+		"tenant_id":      tenantID,
+		"active_version": active,
+		"versions":       versions,
+	})
+}
+
+// Activate handles POST /v1/admin/tenants/:id/prompts/:version/activate.
+// Version 0 reverts the tenant to the built-in prompt.
+func (h *PromptHandler) Activate(c *gin.Context) {
+	tenantID, ok := h.authorizeTenant(c)
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 0 {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: version must be a non-negative integer")
+		return
+	}
+	err = h.store.Activate(tenantID, version)
+	if errors.Is(err, prompts.ErrVersionNotFound) {
+		respondError(c, http.StatusNotFound, "PROMPT_VERSION_NOT_FOUND", "Prompt version not found")
+		return
+	}
+	if err != nil {
+		logger.Error("failed to activate prompt version", map[string]interface{}{
+			"error":     err.Error(),
+			"tenant_id": tenantID,
+		})
+		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to activate prompt version")
+		return
+	}
+
+	identity, _ := middleware.IdentityFrom(c)
+	logger.Audit("prompt version activated", map[string]interface{}{
+		"tenant_id": tenantID,
+		"version":   version,
+		"subject":   identity.Subject(),
+	})
+	c.JSON(http.StatusOK, gin.H{"tenant_id": tenantID, "active_version": version})
+}
+
+// Preview handles POST /v1/admin/tenants/:id/prompts/preview. It retrieves
+// knowledge and renders the final messages exactly as a query would, but
+// never calls the LLM.
+func (h *PromptHandler) Preview(c *gin.Context) {
+	tenantID, ok := h.authorizeTenant(c)
+	if !ok {
+		return
+	}
+	var req PreviewPromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: "+err.Error())
+		return
+	}
+
+	version, err := h.resolveVersion(tenantID, req.Version)
+	if err != nil {
+		respondError(c, http.StatusNotFound, "PROMPT_VERSION_NOT_FOUND", "Prompt version not found")
+		return
+	}
+	preview, qerr := h.support.previewPrompt(c.Request.Context(), tenantID, req, version)
+	if qerr != nil {
+		qerr.respond(c)
+		return
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// previewPrompt renders the messages a query for req would send with the
+// given prompt version, taking the same locale, tenant, redaction and
+// retrieval steps as query. It never calls the LLM, so questions in
+// languages answered via translation are shown untranslated.
+func (h *SupportHandler) previewPrompt(ctx context.Context, tenantID string, req PreviewPromptRequest, version prompts.Version) (PreviewPromptResponse, *queryError) {
+	tag := locale.Canonical(req.Language)
+	language := locale.BaseLanguage(tag)
+	if tag == "" {
+		tag = language
+	}
+	promptLocale := tag
+	if kb, ok := h.translationTarget(tenantID, language); ok {
+		language, promptLocale = kb, kb
+	}
+
+	settings := h.defaults
+	if h.tenants != nil {
+		t, err := h.tenants.Resolve(tenantID, language)
+		if errors.Is(err, tenant.ErrUnsupportedLanguage) {
+			return PreviewPromptResponse{}, messageError(http.StatusBadRequest, "UNSUPPORTED_LANGUAGE", h.settingsFor(tenantID), messages.UnsupportedLanguage, tag)
+		}
+		if err != nil {
+			return PreviewPromptResponse{}, tenantError(err)
+		}
+		settings = h.tenants.Settings(t)
+	}
+
+	question, extraKB := req.Question, req.KnowledgeBase
+	if settings.PIIRedaction {
+		question, extraKB = redactInput(settings, language, question, extraKB, pii.NewVault())
+	}
+
+	var sources []string
+	if h.retriever != nil {
+		kb, err := h.retriever.Retrieve(ctx, tenantID, language, question)
+		if err != nil {
+			logger.Error("knowledge retrieval failed", map[string]interface{}{
+				"error":     err.Error(),
+				"tenant_id": tenantID,
+			})
+		}
+		sources = kb
+	}
+	sources = append(sources, extraKB...)
+
+	llmReq := h.promptRequest(tenantID, promptLocale, question, sources, settings, nil)
+	llmReq.CustomerName = req.CustomerName
+	llmReq.Template = version.Template()
+
+	if sources == nil {
+		sources = []string{}
+	}
+	return PreviewPromptResponse{
+		TenantID: tenantID,
+		Version:  version.Version,
+		Model:    settings.Model,
+		Sources:  sources,
+		Messages: h.promptBuilder.BuildMessages(llmReq),
+	}, nil
+}
+
+// resolveVersion returns the requested version, or the active one for 0.
+// A zero Version means the built-in prompt.
+func (h *PromptHandler) resolveVersion(tenantID string, version int) (prompts.Version, error) {
+	if version > 0 {
+		return h.store.Get(tenantID, version)
+	}
+	active, err := h.store.Active(tenantID)
+	if errors.Is(err, prompts.ErrNoActiveVersion) {
+		return prompts.Version{}, nil
+	}
+	return active, err
+}
+
+// authorizeTenant lets platform admins manage any tenant and tenant admins their own.
+func (h *PromptHandler) authorizeTenant(c *gin.Context) (string, bool) {
+	tenantID := c.Param("id")
+	if identity, ok := middleware.IdentityFrom(c); ok && !identity.CanAccessTenant(tenantID) {
+		respondError(c, http.StatusNotFound, "TENANT_NOT_FOUND", "Tenant not found")
+		return "", false
+	}
+	if h.tenants != nil {
+		if _, err := h.tenants.Get(tenantID); err != nil {
+			respondTenantError(c, err)
+			return "", false
+		}
+	}
+	return tenantID, true
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/middleware"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/shadow"
+	"github.com/gin-gonic/gin"
+)
+
+// ShadowHandler exposes shadow traffic comparisons (admin scope)
+type ShadowHandler struct {
+	runner *shadow.Runner
+}
+
+// NewShadowHandler creates a new shadow handler; runner is nil when shadow mode is off
+func NewShadowHandler(runner *shadow.Runner) *ShadowHandler {
+	return &ShadowHandler{runner: runner}
+}
+
+// List handles GET /v1/admin/shadow?tenant_id=&limit=
+func (h *ShadowHandler) List(c *gin.Context) {
+	if h.runner == nil {
+		respondError(c, http.StatusNotFound, "SHADOW_DISABLED", "Shadow mode is not enabled")
+		return
+	}
+
+	identity, _ := middleware.IdentityFrom(c)
+	tenantID := c.Query("tenant_id")
+	if identity != nil && !identity.IsPlatform() {
+		if tenantID != "" && tenantID != identity.TenantID {
+			respondError(c, http.StatusForbidden, "FORBIDDEN", "Cannot view shadow results for another tenant")
+			return
+		}
+		tenantID = identity.TenantID
+	}
+
+	limit := 50
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"summary":     h.runner.Summary(tenantID),
+		"comparisons": h.runner.List(tenantID, limit),
+	})
+}
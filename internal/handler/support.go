@@ -1,39 +1,99 @@
 package handler
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/RyoKusnadi/tier1-support-ai/internal/auth"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/calibration"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/experiment"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/faq"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/guard"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/intent"
 	"github.com/RyoKusnadi/tier1-support-ai/internal/knowledge"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/langdetect"
 	"github.com/RyoKusnadi/tier1-support-ai/internal/llm"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/locale"
 	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/messages"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/middleware"
 	"github.com/RyoKusnadi/tier1-support-ai/internal/observability"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/pii"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/prompts"
 	"github.com/RyoKusnadi/tier1-support-ai/internal/reliability"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/shadow"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/tenant"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/tools"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/translate"
 	"github.com/gin-gonic/gin"
 )
 
 // SupportQueryRequest represents the request body for support queries
 type SupportQueryRequest struct {
-	Question      string   `json:"question" binding:"required"`
-	TenantID      string   `json:"tenant_id" binding:"required"`
-	Language      string   `json:"language" binding:"required"`
-	KnowledgeBase []string `json:"knowledge_base,omitempty"` // Optional knowledge base for RAG
+	Question       string   `json:"question" binding:"required"`
+	TenantID       string   `json:"tenant_id"`                 // Ignored in favour of the API key's tenant when authenticated
+	Language       string   `json:"language,omitempty"`        // Language code or BCP-47 tag, e.g. "id" or "pt-BR"; detected when empty
+	KnowledgeBase  []string `json:"knowledge_base,omitempty"`  // Optional knowledge base for RAG
+	ConversationID string   `json:"conversation_id,omitempty"` // Keeps a conversation on one experiment variant
 }
 
 // SupportQueryResponse represents the response for support queries
 type SupportQueryResponse struct {
+	ResponseID string  `json:"response_id,omitempty"` // Reference for POST /v1/support/feedback
 	Answer     string  `json:"answer"`
 	Confidence float64 `json:"confidence"`
 	TenantID   string  `json:"tenant_id"`
 	Language   string  `json:"language"`
-	Fallback   bool    `json:"fallback,omitempty"`
+	// Locale is the BCP-47 tag the answer's fallback text and number
+	// formatting follow, e.g. "id-ID"
+	Locale string `json:"locale,omitempty"`
+	// DetectedLanguage is the language identified from the question
+	DetectedLanguage *langdetect.Result `json:"detected_language,omitempty"`
+	// KnowledgeLanguage is the language the answer was produced in before
+	// being translated into Language
+	KnowledgeLanguage string `json:"knowledge_language,omitempty"`
+	Fallback          bool   `json:"fallback,omitempty"`
+	// FallbackReason explains a fallback: no_knowledge, low_confidence,
+	// rejected_input, escalated, or the output guard check that failed
+	FallbackReason string `json:"fallback_reason,omitempty"`
+	Route          string `json:"route,omitempty"`  // escalate, canned or rag when intent routing is enabled
+	FAQID          string `json:"faq_id,omitempty"` // Tenant FAQ entry answered verbatim
+	ExperimentID   string `json:"experiment_id,omitempty"`
+	Variant        string `json:"variant,omitempty"`
+
+	// Intent comes from the intent classifier, or the model in structured output mode
+	Intent string `json:"intent,omitempty"`
+
+	// Structured output mode only
+	UsedSourceIDs  []string `json:"used_source_ids,omitempty"` // S<n> is the n-th knowledge base entry
+	NeedsHuman     bool     `json:"needs_human,omitempty"`
+	SelfConfidence *float64 `json:"self_confidence,omitempty"` // The model's own estimate; not used for fallback
+}
+
+// FeedbackRequest represents the request body for rating an answer
+type FeedbackRequest struct {
+	ResponseID string `json:"response_id" binding:"required"`
+	Score      int    `json:"score" binding:"omitempty,min=1,max=5"` // Required unless escalated
+	Comment    string `json:"comment,omitempty"`
+	// Escalated reports that the customer was handed to a human after the answer
+	Escalated bool `json:"escalated,omitempty"`
 }
 
 // SupportHandler handles support-related requests
 type SupportHandler struct {
-	llmClient           llm.Client
-	retriever           knowledge.Retriever
-	confidenceThreshold float64
+	llmClient llm.Client
+	retriever knowledge.Retriever
+
+	// defaults apply when no tenant registry is configured; otherwise the
+	// registry resolves per-tenant settings on every request
+	defaults config.TenantSettings
 
 	// Phase 5 — Reliability & Cost Control
 	rateLimiter   *reliability.TenantRateLimiter
@@ -43,6 +103,207 @@ type SupportHandler struct {
 
 	// Phase 6 — Observability
 	metrics *observability.Metrics
+
+	// Tenant registry; nil accepts any tenant and language
+	tenants *tenant.Registry
+
+	// Tenant prompt templates; nil always uses the built-in prompt
+	prompts *prompts.Store
+
+	// Prompt/model experiments; nil disables assignment and feedback tracking
+	experiments *experiment.Store
+
+	// Shadow traffic to a candidate model; nil disables it
+	shadow *shadow.Runner
+
+	// Prompt injection screening of customer input; nil disables it
+	inputGuard *guard.InputGuard
+	guardMode  guard.Mode
+
+	// Groundedness against retrieved knowledge, blended into confidence; nil disables it
+	grounding       *llm.GroundingScorer
+	groundingWeight float64
+
+	// Tenant HTTP tools the model may call; nil disables tool calling
+	tools             *tools.Registry
+	toolMaxIterations int
+
+	// Detected languages below this confidence are not trusted
+	languageMinConfidence float64
+
+	// Translates for tenants with translations configured; nil disables translation
+	translator translate.Translator
+
+	// Intent classification for routing; nil sends every request to RAG
+	intents *intent.IntentClassifier
+
+	// Ask the model for a JSON answer with sources, intent and escalation flag
+	structured bool
+
+	// Confidence calibration profile and feedback sample collection; nil disables each
+	calibration        *calibration.Profile
+	calibrationSamples *calibration.Recorder
+
+	// Policy checks on generated answers; nil disables them
+	outputGuard   *guard.OutputGuard
+	promptBuilder *llm.PromptBuilder
+
+	// Batch endpoint limits
+	batchMaxItems    int
+	batchConcurrency int
+	batchRateWait    time.Duration
+
+	// Responses replayed for retried Idempotency-Key requests; nil ignores the header
+	idempotency *reliability.IdempotencyStore[SupportQueryResponse]
+}
+
+// Fallback reasons not produced by the output guard
+const (
+	fallbackNoKnowledge   = "no_knowledge"
+	fallbackLowConfidence = "low_confidence"
+	fallbackRejectedInput = "rejected_input"
+	fallbackInvalidOutput = "invalid_output"
+	fallbackNeedsHuman    = "needs_human"
+	fallbackEscalated     = "escalated"
+)
+
+// SupportHandlerOption configures optional SupportHandler dependencies
+type SupportHandlerOption func(*SupportHandler)
+
+// WithDefaultSettings sets the settings used when no tenant registry is configured
+func WithDefaultSettings(settings config.TenantSettings) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.defaults = settings
+	}
+}
+
+// WithRetriever replaces the default in-memory knowledge retriever
+func WithRetriever(retriever knowledge.Retriever) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.retriever = retriever
+	}
+}
+
+// WithPromptStore renders each tenant's active prompt template
+func WithPromptStore(store *prompts.Store) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.prompts = store
+	}
+}
+
+// WithExperiments assigns requests to the tenant's running experiment
+func WithExperiments(store *experiment.Store) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.experiments = store
+	}
+}
+
+// WithShadow replays answered requests against a candidate model in the background
+func WithShadow(runner *shadow.Runner) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.shadow = runner
+	}
+}
+
+// WithInputGuard screens questions for prompt injection before they reach the LLM
+func WithInputGuard(g *guard.InputGuard, mode guard.Mode) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.inputGuard = g
+		h.guardMode = mode
+	}
+}
+
+// WithGrounding blends how well the answer is supported by the retrieved
+// knowledge into its confidence, so unsupported answers fall back
+func WithGrounding(scorer *llm.GroundingScorer, weight float64) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.grounding = scorer
+		h.groundingWeight = weight
+	}
+}
+
+// WithTools lets the model call the tenant's configured HTTP tools
+func WithTools(registry *tools.Registry, maxIterations int) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.tools = registry
+		h.toolMaxIterations = maxIterations
+	}
+}
+
+// WithLanguageDetection sets the confidence a detected language needs to be trusted
+func WithLanguageDetection(minConfidence float64) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.languageMinConfidence = minConfidence
+	}
+}
+
+// WithBatchLimits bounds batch queries: items per request, items answered at
+// once, and how long an item waits for its tenant's rate limit
+func WithBatchLimits(maxItems, concurrency int, rateWait time.Duration) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.batchMaxItems = maxItems
+		h.batchConcurrency = concurrency
+		h.batchRateWait = rateWait
+	}
+}
+
+// WithIdempotency replays responses to retried requests that send the same
+// Idempotency-Key header
+func WithIdempotency(store *reliability.IdempotencyStore[SupportQueryResponse]) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.idempotency = store
+	}
+}
+
+// WithTranslator answers customer languages the tenant has no content for by
+// translating through one of its knowledge base languages
+func WithTranslator(translator translate.Translator) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.translator = translator
+	}
+}
+
+// WithIntentClassifier routes requests by intent: sensitive intents escalate,
+// simple ones get the tenant's canned answer, the rest go to RAG
+func WithIntentClassifier(classifier *intent.IntentClassifier) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.intents = classifier
+	}
+}
+
+// WithStructuredOutput requests structured JSON answers from the LLM
+func WithStructuredOutput() SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.structured = true
+	}
+}
+
+// WithCalibration maps raw confidence to the observed helpful rate before the threshold check
+func WithCalibration(profile *calibration.Profile) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.calibration = profile
+	}
+}
+
+// WithCalibrationSamples records raw confidence with feedback labels for cmd/calibrate
+func WithCalibrationSamples(recorder *calibration.Recorder) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.calibrationSamples = recorder
+	}
+}
+
+// WithOutputGuard replaces answers that violate the tenant's output policy with the fallback
+func WithOutputGuard(g *guard.OutputGuard) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.outputGuard = g
+	}
+}
+
+// WithTenantRegistry rejects unknown or disabled tenants and unsupported languages
+func WithTenantRegistry(registry *tenant.Registry) SupportHandlerOption {
+	return func(h *SupportHandler) {
+		h.tenants = registry
+	}
 }
 
 // NewSupportHandler creates a new support handler
@@ -53,17 +314,29 @@ func NewSupportHandler(
 	tokenUsage *reliability.TokenUsageTracker,
 	budgetGuard *reliability.BudgetGuard,
 	metrics *observability.Metrics,
+	opts ...SupportHandlerOption,
 ) *SupportHandler {
-	return &SupportHandler{
-		llmClient:           llmClient,
-		retriever:           knowledge.NewInMemoryRetriever(),
-		confidenceThreshold: 0.7,
-		rateLimiter:         rateLimiter,
-		responseCache:       responseCache,
-		tokenUsage:          tokenUsage,
-		budgetGuard:         budgetGuard,
-		metrics:             metrics,
+	h := &SupportHandler{
+		llmClient:     llmClient,
+		retriever:     knowledge.NewInMemoryRetriever(),
+		defaults:      config.BuiltinTenantSettings(),
+		rateLimiter:   rateLimiter,
+		responseCache: responseCache,
+		tokenUsage:    tokenUsage,
+		budgetGuard:   budgetGuard,
+		metrics:       metrics,
+		promptBuilder: llm.NewPromptBuilder(),
+
+		languageMinConfidence: 0.8,
+
+		batchMaxItems:    100,
+		batchConcurrency: 4,
+		batchRateWait:    5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	return h
 }
 
 // SupportQuery handles POST /v1/support/query requests
@@ -76,40 +349,322 @@ func (h *SupportHandler) SupportQuery(c *gin.Context) {
 		if h.metrics != nil {
 			h.metrics.ErrorsTotal.Add(1)
 		}
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": gin.H{
-				"code":    "INVALID_REQUEST",
-				"message": "Invalid request: " + err.Error(),
-			},
-		})
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: "+err.Error())
+		return
+	}
+
+	identity, _ := middleware.IdentityFrom(c)
+	if qerr := resolveTenant(&req, identity); qerr != nil {
+		qerr.respond(c)
 		return
 	}
 
 	// Phase 6: attach tenant_id to request context for logging/middleware
-	c.Set("tenant_id", req.TenantID)
+	c.Set(middleware.CtxTenantID, req.TenantID)
+
+	// A retried request with the same Idempotency-Key gets the first answer
+	// instead of running again
+	idemKey, done := h.beginIdempotent(c, req, identity)
+	if done {
+		return
+	}
+	if idemKey != "" {
+		// Failed requests are not stored, so the retry runs again
+		defer h.idempotency.Release(idemKey)
+	}
+
+	resp, qerr := h.query(c.Request.Context(), req, identity, c.GetString(middleware.CtxRequestID), newResponseID(), 0)
+	if qerr != nil {
+		qerr.respond(c)
+		return
+	}
+	if idemKey != "" {
+		h.idempotency.Complete(idemKey, resp)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// resolveTenant derives the tenant from the authenticated identity rather
+// than trusting the body.
+func resolveTenant(req *SupportQueryRequest, identity *auth.Identity) *queryError {
+	if identity != nil && !identity.IsPlatform() {
+		if req.TenantID != "" && req.TenantID != identity.TenantID {
+			logger.Error("tenant mismatch", map[string]interface{}{
+				"tenant_id":      identity.TenantID,
+				"body_tenant_id": req.TenantID,
+				"subject":        identity.Subject(),
+			})
+			return &queryError{http.StatusForbidden, "TENANT_MISMATCH", "tenant_id does not match the authenticated tenant"}
+		}
+		req.TenantID = identity.TenantID
+	}
+	if req.TenantID == "" {
+		return &queryError{http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: tenant_id is required"}
+	}
+	return nil
+}
+
+// query answers one support question for an already resolved tenant. It is
+// shared by the single, batch and job endpoints. requestID correlates logs;
+// responseID is the server-generated key for feedback and must be unique.
+// A positive rateWait waits that long for the tenant's rate limit instead of
+// failing at once.
+func (h *SupportHandler) query(ctx context.Context, req SupportQueryRequest, identity *auth.Identity, requestID, responseID string, rateWait time.Duration) (SupportQueryResponse, *queryError) {
+	// A locale tag such as "pt-BR" selects formatting and fallback text; the
+	// registry, knowledge base and cache work with its language
+	tag := locale.Canonical(req.Language)
+	req.Language = locale.BaseLanguage(tag)
+
+	// Identify the question's language; it fills in a missing language and
+	// flags declared languages that disagree with the text
+	detected := langdetect.Detect(req.Question, nil)
+	reliable := detected.Reliable(req.Question, h.languageMinConfidence)
+	if req.Language == "" {
+		candidates := h.languagesFor(req.TenantID)
+		switch {
+		case reliable && !containsString(candidates, detected.Language) && !h.translatable(req.TenantID, detected.Language):
+			logger.Error("detected language not supported", map[string]interface{}{
+				"tenant_id":  req.TenantID,
+				"language":   detected.Language,
+				"confidence": detected.Confidence,
+			})
+			return SupportQueryResponse{}, messageError(http.StatusBadRequest, "UNSUPPORTED_LANGUAGE", h.settingsFor(req.TenantID), messages.UnsupportedLanguage, detected.Language)
+		case reliable:
+			req.Language = detected.Language
+		default:
+			// Too little evidence to trust; pick the closest language the tenant serves
+			if best := langdetect.Detect(req.Question, candidates); best.Language != "" {
+				req.Language = best.Language
+			} else if len(candidates) > 0 {
+				req.Language = candidates[0]
+			}
+		}
+	} else if reliable && !strings.EqualFold(detected.Language, req.Language) {
+		logger.Info("language mismatch", map[string]interface{}{
+			"request_id": requestID,
+			"tenant_id":  req.TenantID,
+			"declared":   req.Language,
+			"detected":   detected.Language,
+			"confidence": detected.Confidence,
+		})
+	}
+	var detectedLanguage *langdetect.Result
+	if detected.Language != "" {
+		detectedLanguage = &detected
+	}
+	if tag == "" {
+		tag = req.Language
+	}
+
+	// Languages without tenant content are answered in the knowledge base
+	// language configured for them and translated; from here on req.Language
+	// is the knowledge base language
+	var tr *translation
+	if kb, ok := h.translationTarget(req.TenantID, req.Language); ok {
+		tr = &translation{customer: req.Language, kb: kb}
+		req.Language = kb
+	}
+
+	// Only registered, enabled tenants may spend budget, in languages they support.
+	// The registry also resolves the tenant's effective settings for this request.
+	settings := h.defaults
+	if h.tenants != nil {
+		t, err := h.tenants.Resolve(req.TenantID, req.Language)
+		if err != nil {
+			logger.Error("tenant validation failed", map[string]interface{}{
+				"error":     err.Error(),
+				"tenant_id": req.TenantID,
+				"language":  req.Language,
+			})
+			if errors.Is(err, tenant.ErrUnsupportedLanguage) {
+				return SupportQueryResponse{}, messageError(http.StatusBadRequest, "UNSUPPORTED_LANGUAGE", h.settingsFor(req.TenantID), messages.UnsupportedLanguage, tag)
+			}
+			return SupportQueryResponse{}, tenantError(err)
+		}
+		settings = h.tenants.Settings(t)
+	}
 
-	if h.rateLimiter != nil && !h.rateLimiter.Allow(req.TenantID) {
+	if h.rateLimiter != nil && !h.allowRate(ctx, req.TenantID, settings, rateWait) {
 		logger.Error("rate limit exceeded", map[string]interface{}{
 			"tenant_id": req.TenantID,
 		})
-		c.JSON(http.StatusTooManyRequests, gin.H{
-			"error": gin.H{
-				"code":    "RATE_LIMIT_EXCEEDED",
-				"message": "Rate limit exceeded for tenant",
-			},
+		return SupportQueryResponse{}, messageError(http.StatusTooManyRequests, "RATE_LIMIT_EXCEEDED", settings, messages.RateLimited, tag)
+	}
+
+	// Screen untrusted input before it can reach the cache, retrieval or the LLM
+	if h.inputGuard != nil {
+		verdict := h.inputGuard.Check(ctx, append([]string{req.Question}, req.KnowledgeBase...)...)
+		if verdict.Blocked {
+			if h.metrics != nil {
+				h.metrics.InjectionBlockedTotal.Add(1)
+			}
+			logger.Audit("prompt injection blocked", map[string]interface{}{
+				"request_id": requestID,
+				"tenant_id":  req.TenantID,
+				"subject":    identity.Subject(),
+				"score":      verdict.Score,
+				"reasons":    verdict.Reasons,
+				"mode":       string(h.guardMode),
+			})
+			if h.guardMode == guard.ModeFallback {
+				return h.reply(ctx, SupportQueryResponse{
+					ResponseID:       responseID,
+					Answer:           settings.FallbackFor(tag),
+					TenantID:         req.TenantID,
+					Language:         req.Language,
+					DetectedLanguage: detectedLanguage,
+					Fallback:         true,
+					FallbackReason:   fallbackRejectedInput,
+				}, nil, tr, tag), nil
+			}
+			return SupportQueryResponse{}, messageError(http.StatusBadRequest, "REJECTED_INPUT", settings, messages.RejectedInput, tag)
+		}
+	}
+
+	// Redact personal data before the question reaches the cache, the LLM or
+	// stored comparisons; placeholders are restored in the answer
+	vault := pii.NewVault()
+	question := req.Question
+	extraKB := req.KnowledgeBase
+	if settings.PIIRedaction {
+		question, extraKB = redactInput(settings, req.Language, question, extraKB, vault)
+		if vault.Len() > 0 {
+			logger.Info("pii redacted", map[string]interface{}{
+				"request_id": requestID,
+				"tenant_id":  req.TenantID,
+				"kinds":      vault.Kinds(),
+			})
+		}
+	}
+
+	// Translate the redacted question so routing, FAQs, the cache and retrieval
+	// all work in the knowledge base language
+	if tr != nil {
+		if h.budgetGuard != nil && !h.budgetGuard.AllowBudget(req.TenantID, settings.TokenBudget) {
+			if h.metrics != nil {
+				h.metrics.BudgetBlockedTotal.Add(1)
+			}
+			return SupportQueryResponse{}, messageError(http.StatusTooManyRequests, "BUDGET_EXCEEDED", settings, messages.BudgetExceeded, tag)
+		}
+		translated, err := h.translator.Translate(ctx, question, tr.customer, tr.kb)
+		h.recordTranslation(req.TenantID, translated.TokensUsed)
+		if err != nil {
+			logger.Error("failed to translate question", map[string]interface{}{
+				"error":     err.Error(),
+				"tenant_id": req.TenantID,
+				"from":      tr.customer,
+				"to":        tr.kb,
+			})
+			if h.metrics != nil {
+				h.metrics.ErrorsTotal.Add(1)
+			}
+			return SupportQueryResponse{}, messageError(http.StatusInternalServerError, "INTERNAL_ERROR", settings, messages.InternalError, tag)
+		}
+		question = translated.Text
+	}
+
+	// Route by intent before anything is spent on retrieval or the LLM
+	var classified intent.Result
+	if h.intents != nil {
+		classified = h.intents.Classify(ctx, question)
+		route := intent.RouteFor(classified.Intent, settings.IntentRoutes)
+		routed := SupportQueryResponse{
+			ResponseID:       responseID,
+			TenantID:         req.TenantID,
+			Language:         req.Language,
+			DetectedLanguage: detectedLanguage,
+			Intent:           classified.Intent,
+			Route:            string(route),
+		}
+		switch route {
+		case intent.RouteEscalate:
+			if h.metrics != nil {
+				h.metrics.EscalatedTotal.Add(1)
+			}
+			logger.Audit("support query escalated", map[string]interface{}{
+				"request_id": requestID,
+				"tenant_id":  req.TenantID,
+				"subject":    identity.Subject(),
+				"intent":     classified.Intent,
+				"source":     classified.Source,
+			})
+			routed.Answer = settings.FallbackFor(tag)
+			routed.Fallback = true
+			routed.FallbackReason = fallbackEscalated
+			return h.reply(ctx, routed, vault, tr, tag), nil
+		case intent.RouteCanned:
+			// Without a canned answer for the intent the request continues to RAG
+			if answer := settings.CannedAnswers[classified.Intent]; answer != "" {
+				if h.metrics != nil {
+					h.metrics.CannedAnswersTotal.Add(1)
+				}
+				logger.Audit("support query answered", map[string]interface{}{
+					"request_id": requestID,
+					"tenant_id":  req.TenantID,
+					"subject":    identity.Subject(),
+					"intent":     classified.Intent,
+					"route":      string(route),
+				})
+				routed.Answer = answer
+				routed.Confidence = 1.0
+				return h.reply(ctx, routed, vault, tr, tag), nil
+			}
+		}
+	}
+
+	// Approved FAQ answers are returned verbatim without spending tokens
+	if match, ok := faq.Find(question, req.Language, settings.FAQs, settings.FAQThreshold); ok {
+		if h.metrics != nil {
+			h.metrics.CannedAnswersTotal.Add(1)
+		}
+		logger.Audit("support query answered", map[string]interface{}{
+			"request_id": requestID,
+			"tenant_id":  req.TenantID,
+			"subject":    identity.Subject(),
+			"faq_id":     match.Entry.ID,
+			"score":      match.Score,
 		})
-		return
+		faqResp := SupportQueryResponse{
+			ResponseID:       responseID,
+			Answer:           match.Entry.Answer,
+			Confidence:       1.0,
+			TenantID:         req.TenantID,
+			Language:         req.Language,
+			DetectedLanguage: detectedLanguage,
+			FAQID:            match.Entry.ID,
+		}
+		if h.intents != nil {
+			faqResp.Intent = classified.Intent
+			faqResp.Route = string(intent.RouteCanned)
+		}
+		return h.reply(ctx, faqResp, vault, tr, tag), nil
 	}
 
-	// Phase 5: response caching (keyed by tenant, language, question)
-	if h.responseCache != nil {
-		cacheKey := buildCacheKey(req.TenantID, req.Language, req.Question)
+	// Experiment assignment is sticky per conversation, then per customer
+	var (
+		exp          experiment.Experiment
+		variant      experiment.Variant
+		inExperiment bool
+	)
+	if h.experiments != nil {
+		exp, variant, inExperiment = h.experiments.Assign(req.TenantID, stickyKey(req, identity, responseID))
+	}
+
+	// Phase 5: response caching (keyed by tenant, language, question).
+	// Experiment traffic bypasses the cache so every variant generates its own answers.
+	if h.responseCache != nil && !inExperiment {
+		cacheKey := buildCacheKey(req.TenantID, req.Language, question)
 		if cached, ok := h.responseCache.Get(cacheKey); ok {
 			if h.metrics != nil {
 				h.metrics.CacheHitsTotal.Add(1)
 			}
-			c.JSON(http.StatusOK, cached)
-			return
+			cached.ResponseID = responseID
+			cached.DetectedLanguage = detectedLanguage
+			if cached.Fallback {
+				// Customers sharing a knowledge base language may use different locales
+				cached.Answer = settings.FallbackFor(tag)
+			}
+			return h.reply(ctx, cached, vault, tr, tag), nil
 		}
 		if h.metrics != nil {
 			h.metrics.CacheMissesTotal.Add(1)
@@ -119,7 +674,7 @@ func (h *SupportHandler) SupportQuery(c *gin.Context) {
 	// Retrieve relevant knowledge (Phase 4 - Knowledge Retrieval)
 	var retrievedKB []string
 	if h.retriever != nil {
-		kb, err := h.retriever.Retrieve(c.Request.Context(), req.TenantID, req.Language, req.Question)
+		kb, err := h.retriever.Retrieve(ctx, req.TenantID, req.Language, question)
 		if err != nil {
 			logger.Error("knowledge retrieval failed", map[string]interface{}{
 				"error":     err.Error(),
@@ -131,57 +686,72 @@ func (h *SupportHandler) SupportQuery(c *gin.Context) {
 		}
 	}
 
-	// Fallback when no relevant knowledge is found (Phase 4 requirement)
-	if len(retrievedKB) == 0 {
-		c.JSON(http.StatusOK, SupportQueryResponse{
-			Answer:     "We are unable to confidently answer your question. Please contact customer support.",
-			Confidence: 0.0,
-			TenantID:   req.TenantID,
-			Language:   req.Language,
-			Fallback:   true,
-		})
-		return
+	// Fallback when no relevant knowledge is found (Phase 4 requirement);
+	// tenants with tools may still answer from live data
+	hasTools := h.tools != nil && len(settings.Tools) > 0
+	if len(retrievedKB) == 0 && !hasTools {
+		noKnowledge := SupportQueryResponse{
+			ResponseID:       responseID,
+			Answer:           settings.FallbackFor(tag),
+			Confidence:       0.0,
+			TenantID:         req.TenantID,
+			Language:         req.Language,
+			DetectedLanguage: detectedLanguage,
+			Fallback:         true,
+			FallbackReason:   fallbackNoKnowledge,
+		}
+		if h.intents != nil {
+			noKnowledge.Intent = classified.Intent
+			noKnowledge.Route = string(intent.RouteRAG)
+		}
+		return h.reply(ctx, noKnowledge, vault, tr, tag), nil
 	}
 
 	// Merge retrieved knowledge with any explicit knowledge from the request
-	mergedKB := append(retrievedKB, req.KnowledgeBase...)
+	mergedKB := append(retrievedKB, extraKB...)
 
 	// Create LLM request (RAG-style: question + retrieved knowledge)
-	llmReq := &llm.Request{
-		Messages: []llm.Message{
-			{
-				Role:    "user",
-				Content: req.Question,
-			},
-		},
-		KnowledgeBase: mergedKB,
-		Language:      req.Language,
-		TenantID:      req.TenantID,
+	// The model writes in the customer's locale unless the answer is translated
+	promptLocale := tag
+	if tr != nil {
+		promptLocale = req.Language
+	}
+	llmReq := h.promptRequest(req.TenantID, promptLocale, question, mergedKB, settings, identity)
+	if inExperiment {
+		h.applyVariant(llmReq, variant)
+	}
+	if hasTools {
+		// Arguments get real values back for the backend; results are redacted for the model
+		executor := h.tools.Executor(req.TenantID, requestID, identity, settings.Tools)
+		executor.Restore = vault.Restore
+		if settings.PIIRedaction {
+			redactor := redactorFor(settings, req.Language)
+			executor.Redact = func(s string) string { return redactor.Redact(s, vault) }
+		}
+		llmReq.Tools = h.tools.Definitions(settings.Tools)
+		llmReq.ToolExecutor = executor
+		llmReq.MaxToolIterations = h.toolMaxIterations
 	}
 
 	// Generate answer using LLM
 	// Phase 5: budget guardrails (pre-call check)
-	if h.budgetGuard != nil && h.budgetGuard.Enabled() && !h.budgetGuard.Allow(req.TenantID) {
+	if h.budgetGuard != nil && !h.budgetGuard.AllowBudget(req.TenantID, settings.TokenBudget) {
 		if h.metrics != nil {
 			h.metrics.BudgetBlockedTotal.Add(1)
 		}
-		remaining, enabled, resetAt := h.budgetGuard.Remaining(req.TenantID)
+		remaining, enabled, resetAt := h.budgetGuard.RemainingBudget(req.TenantID, settings.TokenBudget)
 		logger.Error("token budget exceeded", map[string]interface{}{
 			"tenant_id": req.TenantID,
 			"remaining": remaining,
 			"enabled":   enabled,
 			"reset_at":  resetAt.Format(time.RFC3339),
 		})
-		c.JSON(http.StatusTooManyRequests, gin.H{
-			"error": gin.H{
-				"code":    "BUDGET_EXCEEDED",
-				"message": "Token budget exceeded for tenant",
-			},
-		})
-		return
+		return SupportQueryResponse{}, messageError(http.StatusTooManyRequests, "BUDGET_EXCEEDED", settings, messages.BudgetExceeded, tag)
 	}
 
-	resp, err := h.llmClient.GenerateAnswer(c.Request.Context(), llmReq)
+	start := time.Now()
+	resp, err := h.llmClient.GenerateAnswer(ctx, llmReq)
+	latency := time.Since(start)
 	if err != nil {
 		logger.Error("failed to generate answer", map[string]interface{}{
 			"error":     err.Error(),
@@ -191,49 +761,507 @@ func (h *SupportHandler) SupportQuery(c *gin.Context) {
 		if h.metrics != nil {
 			h.metrics.ErrorsTotal.Add(1)
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": gin.H{
-				"code":    "INTERNAL_ERROR",
-				"message": "Failed to generate answer",
-			},
-		})
-		return
+		return SupportQueryResponse{}, messageError(http.StatusInternalServerError, "INTERNAL_ERROR", settings, messages.InternalError, tag)
 	}
 
 	// Phase 5: token usage tracking (post-call)
 	if h.tokenUsage != nil {
-		usage := h.tokenUsage.Add(req.TenantID, resp.TokensUsed)
-		logger.Info("token usage updated", map[string]interface{}{
-			"tenant_id":   usage.TenantID,
-			"tokens_used": usage.TokensUsed,
-			"requests":    usage.Requests,
-			"window":      usage.Window.String(),
-		})
+		logUsage(h.tokenUsage.Add(req.TenantID, resp.TokensUsed))
+	}
+
+	// Successful tool results are knowledge the answer may rely on
+	answerKB := mergedKB
+	for _, call := range resp.ToolCalls {
+		if call.Err == nil {
+			answerKB = append(answerKB, call.Result)
+		}
+	}
+
+	// Lower confidence for answers the knowledge base does not support
+	groundedness := -1.0
+	if h.grounding != nil && len(answerKB) > 0 {
+		g := h.grounding.Score(ctx, resp.Content, question, answerKB)
+		groundedness = g.Score
+		resp.Confidence = llm.BlendConfidence(resp.Confidence, g.Score, h.groundingWeight)
+		if len(g.UnsupportedNumbers) > 0 {
+			logger.Info("answer contains numbers not found in knowledge base", map[string]interface{}{
+				"request_id": requestID,
+				"tenant_id":  req.TenantID,
+				"count":      len(g.UnsupportedNumbers),
+			})
+		}
+	}
+
+	// Calibrate the score so the threshold compares against an observed helpful rate
+	rawConfidence := resp.Confidence
+	if h.calibration != nil {
+		resp.Confidence = h.calibration.Apply(req.TenantID, resp.Confidence)
 	}
 
 	// Apply confidence-based fallback (Phase 4 + API contract)
-	isFallback := resp.Confidence < h.confidenceThreshold
+	isFallback := resp.Confidence < settings.ConfidenceThreshold
+	var fallbackReason string
+	if llmReq.Structured && resp.Structured == nil {
+		// The model never produced valid JSON, so Content is not a usable answer
+		isFallback = true
+		fallbackReason = fallbackInvalidOutput
+	} else if resp.Structured != nil && resp.Structured.NeedsHuman {
+		isFallback = true
+		fallbackReason = fallbackNeedsHuman
+	} else if isFallback {
+		fallbackReason = fallbackLowConfidence
+	} else if h.outputGuard != nil {
+		// Policy checks on confident answers (competitors, refund promises, advice, leaks, links)
+		violations := h.outputGuard.Check(guard.OutputInput{
+			Answer:        resp.Content,
+			KnowledgeBase: answerKB,
+			SystemPrompt:  h.systemMessage(llmReq),
+			Policy:        settings.OutputPolicy,
+		})
+		if len(violations) > 0 {
+			isFallback = true
+			fallbackReason = violations[0].Check
+			if h.metrics != nil {
+				h.metrics.OutputBlockedTotal.Add(1)
+			}
+			checks := make([]string, len(violations))
+			for i, v := range violations {
+				checks[i] = v.Check
+			}
+			logger.Audit("answer blocked by output guard", map[string]interface{}{
+				"request_id": requestID,
+				"tenant_id":  req.TenantID,
+				"checks":     checks,
+			})
+		}
+	}
 	answer := resp.Content
 	if isFallback {
-		answer = "We are unable to confidently answer your question. Please contact customer support."
+		answer = settings.FallbackFor(tag)
+	}
+	if h.calibrationSamples != nil {
+		// Feedback on this answer becomes a calibration sample for the raw
+		// score. Fallbacks are tracked too, so the curve sees scores below the
+		// threshold; answers the model hands to a human are labelled at once.
+		err := h.calibrationSamples.Track(responseID, req.TenantID, rawConfidence)
+		if err == nil && fallbackReason == fallbackNeedsHuman {
+			_, err = h.calibrationSamples.Escalate(responseID)
+		}
+		if err != nil {
+			logger.Error("failed to track calibration sample", map[string]interface{}{
+				"error":       err.Error(),
+				"request_id":  requestID,
+				"response_id": responseID,
+			})
+		}
 	}
 
 	// Return response
 	finalResp := SupportQueryResponse{
-		Answer:     answer,
-		Confidence: resp.Confidence,
-		TenantID:   req.TenantID,
-		Language:   req.Language,
-		Fallback:   isFallback,
+		Answer:           answer,
+		Confidence:       resp.Confidence,
+		TenantID:         req.TenantID,
+		Language:         req.Language,
+		DetectedLanguage: detectedLanguage,
+		Fallback:         isFallback,
+		FallbackReason:   fallbackReason,
+	}
+	if h.intents != nil {
+		finalResp.Intent = classified.Intent
+		finalResp.Route = string(intent.RouteRAG)
+	}
+	if s := resp.Structured; s != nil {
+		finalResp.NeedsHuman = s.NeedsHuman
+		// The classifier's specific intent wins over the model's guess
+		if finalResp.Intent == "" || finalResp.Intent == intent.General {
+			finalResp.Intent = s.Intent
+		}
+		finalResp.SelfConfidence = &s.SelfConfidence
+		if !isFallback {
+			finalResp.UsedSourceIDs = s.UsedSourceIDs
+		}
 	}
 
-	// Store in cache for subsequent identical questions
-	if h.responseCache != nil {
-		cacheKey := buildCacheKey(req.TenantID, req.Language, req.Question)
+	// Store in cache for subsequent identical questions; personalised answers are
+	// specific to one customer and must not be served to others, and answers
+	// built from live tool data go stale.
+	if h.responseCache != nil && llmReq.CustomerName == "" && !inExperiment && len(resp.ToolCalls) == 0 {
+		cacheKey := buildCacheKey(req.TenantID, req.Language, question)
 		h.responseCache.Set(cacheKey, finalResp)
 	}
 
-	c.JSON(http.StatusOK, finalResp)
+	// Shadow the request to the candidate model; this never blocks or changes the answer.
+	// Answers that needed tools are skipped since the shadow model cannot call them.
+	if h.shadow != nil && len(resp.ToolCalls) == 0 {
+		h.shadow.Submit(shadow.Comparison{
+			RequestID:           requestID,
+			TenantID:            req.TenantID,
+			Language:            req.Language,
+			Question:            question,
+			ConfidenceThreshold: settings.ConfidenceThreshold,
+			Primary: shadow.Result{
+				Model:      resp.Model,
+				Answer:     resp.Content,
+				Confidence: resp.Confidence,
+				TokensUsed: resp.TokensUsed,
+				LatencyMs:  latency.Milliseconds(),
+				Fallback:   isFallback,
+			},
+		}, llmReq)
+	}
+
+	finalResp.ResponseID = responseID
+	if inExperiment {
+		finalResp.ExperimentID = exp.ID
+		finalResp.Variant = variant.Name
+		model := llmReq.Model
+		if model == "" {
+			model = resp.Model
+		}
+		err := h.experiments.Record(exp.ID, variant.Name, responseID, experiment.Outcome{
+			Fallback:         isFallback,
+			Confidence:       resp.Confidence,
+			Latency:          latency,
+			TokensUsed:       resp.TokensUsed,
+			Model:            model,
+			PromptTokens:     resp.PromptTokens,
+			CompletionTokens: resp.CompletionTokens,
+		})
+		if err != nil {
+			logger.Error("failed to record experiment outcome", map[string]interface{}{
+				"error":       err.Error(),
+				"request_id":  requestID,
+				"response_id": responseID,
+			})
+		}
+	}
+
+	auditFields := map[string]interface{}{
+		"request_id":  requestID,
+		"response_id": responseID,
+		"tenant_id":   req.TenantID,
+		"subject":     identity.Subject(),
+		"variant":     finalResp.Variant,
+		"fallback":    finalResp.Fallback,
+		"confidence":  finalResp.Confidence,
+		"tokens":      resp.TokensUsed,
+		"tool_calls":  len(resp.ToolCalls),
+	}
+	if finalResp.Intent != "" {
+		auditFields["intent"] = finalResp.Intent
+	}
+	if groundedness >= 0 {
+		auditFields["groundedness"] = groundedness
+	}
+	if h.calibration != nil {
+		auditFields["raw_confidence"] = rawConfidence
+	}
+	if tr != nil {
+		auditFields["customer_language"] = tr.customer
+	}
+	logger.Audit("support query answered", auditFields)
+
+	return h.reply(ctx, finalResp, vault, tr, tag), nil
+}
+
+// Feedback handles POST /v1/support/feedback. Scores are attributed to the
+// experiment variant that produced the answer.
+func (h *SupportHandler) Feedback(c *gin.Context) {
+	var req FeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: "+err.Error())
+		return
+	}
+	if req.Score == 0 && !req.Escalated {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: score is required")
+		return
+	}
+
+	identity, _ := middleware.IdentityFrom(c)
+	tracked := false
+	if h.experiments != nil && req.Score > 0 {
+		assignment, err := h.experiments.Lookup(req.ResponseID)
+		if err == nil && identity != nil && !identity.CanAccessTenant(assignment.TenantID) {
+			err = experiment.ErrResponseNotFound
+		}
+		if err == nil {
+			_, err = h.experiments.RecordFeedback(req.ResponseID, req.Score)
+		}
+		switch {
+		case err == nil:
+			tracked = true
+		case errors.Is(err, experiment.ErrFeedbackRecorded):
+			respondError(c, http.StatusConflict, "FEEDBACK_EXISTS", "Feedback already recorded for this response")
+			return
+		case !errors.Is(err, experiment.ErrResponseNotFound):
+			respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to record feedback")
+			return
+		}
+	}
+
+	if h.calibrationSamples != nil {
+		tenantID, err := h.calibrationSamples.Tenant(req.ResponseID)
+		if err == nil && (identity == nil || identity.CanAccessTenant(tenantID)) {
+			// Scores of 4 and 5 count as helpful; an escalation never does
+			if req.Escalated {
+				_, err = h.calibrationSamples.Escalate(req.ResponseID)
+			} else {
+				_, err = h.calibrationSamples.Label(req.ResponseID, req.Score >= 4)
+			}
+			if err == nil {
+				tracked = true
+			} else if !errors.Is(err, calibration.ErrResponseNotFound) {
+				logger.Error("failed to record calibration sample", map[string]interface{}{
+					"error":       err.Error(),
+					"response_id": req.ResponseID,
+				})
+			}
+		}
+	}
+
+	logger.Audit("support feedback received", map[string]interface{}{
+		"response_id": req.ResponseID,
+		"score":       req.Score,
+		"escalated":   req.Escalated,
+		"comment":     req.Comment,
+		"tracked":     tracked,
+		"subject":     identity.Subject(),
+	})
+	c.JSON(http.StatusAccepted, gin.H{"response_id": req.ResponseID, "tracked": tracked})
+}
+
+// promptRequest builds the LLM request for a query with the tenant's active
+// prompt template, the caller's personalisation and the output mode. Prompt
+// previews use it too, so they match what queries send.
+func (h *SupportHandler) promptRequest(tenantID, language, question string, kb []string, settings config.TenantSettings, identity *auth.Identity) *llm.Request {
+	llmReq := newLLMRequest(tenantID, language, question, kb, settings)
+	if identity != nil {
+		llmReq.CustomerID = identity.CustomerID
+		llmReq.CustomerName = identity.CustomerName
+	}
+	if h.prompts != nil {
+		if active, err := h.prompts.Active(tenantID); err == nil {
+			llmReq.Template = active.Template()
+		}
+	}
+	llmReq.Structured = h.structured
+	return llmReq
+}
+
+// applyVariant overrides the prompt, model and temperature for an experiment variant.
+func (h *SupportHandler) applyVariant(llmReq *llm.Request, variant experiment.Variant) {
+	if variant.PromptVersion > 0 && h.prompts != nil {
+		v, err := h.prompts.Get(llmReq.TenantID, variant.PromptVersion)
+		if err != nil {
+			logger.Error("experiment prompt version unavailable", map[string]interface{}{
+				"error":          err.Error(),
+				"tenant_id":      llmReq.TenantID,
+				"variant":        variant.Name,
+				"prompt_version": variant.PromptVersion,
+			})
+		} else {
+			llmReq.Template = v.Template()
+		}
+	}
+	if variant.Model != "" {
+		llmReq.Model = variant.Model
+	}
+	if variant.Temperature != nil {
+		llmReq.Temperature = llm.Temp(*variant.Temperature)
+	}
+}
+
+// systemMessage returns the system message the model received for llmReq.
+func (h *SupportHandler) systemMessage(llmReq *llm.Request) string {
+	for _, m := range h.promptBuilder.BuildMessages(llmReq) {
+		if m.Role == "system" {
+			return m.Content
+		}
+	}
+	return ""
+}
+
+// allowRate takes a rate limit token for the tenant, waiting up to wait for one.
+func (h *SupportHandler) allowRate(ctx context.Context, tenantID string, settings config.TenantSettings, wait time.Duration) bool {
+	if wait <= 0 {
+		return h.rateLimiter.AllowRate(tenantID, settings.RateLimitPerSec, settings.RateLimitBurst)
+	}
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	return h.rateLimiter.WaitRate(ctx, tenantID, settings.RateLimitPerSec, settings.RateLimitBurst) == nil
+}
+
+// translation is a request answered in a knowledge base language and
+// translated for the customer.
+type translation struct {
+	customer string // Language the customer wrote in
+	kb       string // Knowledge base language the request is processed in
+}
+
+// reply translates the answer for the customer when needed and restores
+// redacted personal data. Fallback answers are already in the customer's
+// locale. A failed translation keeps the knowledge base language answer
+// rather than failing the request.
+func (h *SupportHandler) reply(ctx context.Context, resp SupportQueryResponse, vault *pii.Vault, tr *translation, tag string) SupportQueryResponse {
+	if l, ok := locale.Lookup(tag); ok {
+		resp.Locale = l.Tag
+	}
+	if tr != nil && resp.Fallback {
+		resp.Language = tr.customer
+		resp.KnowledgeLanguage = tr.kb
+	} else if tr != nil {
+		translated, err := h.translator.Translate(ctx, resp.Answer, tr.kb, tr.customer)
+		h.recordTranslation(resp.TenantID, translated.TokensUsed)
+		if err != nil {
+			logger.Error("failed to translate answer", map[string]interface{}{
+				"error":     err.Error(),
+				"tenant_id": resp.TenantID,
+				"from":      tr.kb,
+				"to":        tr.customer,
+			})
+		} else {
+			resp.Answer = translated.Text
+			resp.Language = tr.customer
+		}
+		resp.KnowledgeLanguage = tr.kb
+	}
+	if vault != nil {
+		resp.Answer = vault.Restore(resp.Answer)
+	}
+	return resp
+}
+
+// recordTranslation counts translation tokens towards the tenant's budget.
+func (h *SupportHandler) recordTranslation(tenantID string, tokens int) {
+	if h.tokenUsage != nil && tokens > 0 {
+		logUsage(h.tokenUsage.AddTranslation(tenantID, tokens))
+	}
+}
+
+func logUsage(usage reliability.TokenUsage) {
+	logger.Info("token usage updated", map[string]interface{}{
+		"tenant_id":          usage.TenantID,
+		"tokens_used":        usage.TokensUsed,
+		"translation_tokens": usage.TranslationTokens,
+		"requests":           usage.Requests,
+		"window":             usage.Window.String(),
+	})
+}
+
+// translationTarget returns the knowledge base language to answer in when the
+// tenant has no content in language but translates it.
+func (h *SupportHandler) translationTarget(tenantID, language string) (string, bool) {
+	if h.translator == nil || containsString(h.languagesFor(tenantID), language) {
+		return "", false
+	}
+	kb, ok := h.settingsFor(tenantID).Translations[language]
+	return kb, ok
+}
+
+// translatable reports whether the tenant translates language.
+func (h *SupportHandler) translatable(tenantID, language string) bool {
+	_, ok := h.translationTarget(tenantID, language)
+	return ok
+}
+
+// settingsFor returns a tenant's settings before the request language is
+// known; unknown tenants get the defaults and fail later in Resolve.
+func (h *SupportHandler) settingsFor(tenantID string) config.TenantSettings {
+	if h.tenants != nil {
+		if t, err := h.tenants.Get(tenantID); err == nil {
+			return h.tenants.Settings(t)
+		}
+	}
+	return h.defaults
+}
+
+// languagesFor returns the languages a tenant may be answered in, sorted.
+func (h *SupportHandler) languagesFor(tenantID string) []string {
+	if h.tenants != nil {
+		return h.tenants.Languages(tenantID)
+	}
+	languages := make([]string, 0, len(config.SupportedLanguages))
+	for l, ok := range config.SupportedLanguages {
+		if ok {
+			languages = append(languages, l)
+		}
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// newResponseID returns a random ID for feedback on an answer. Response IDs
+// are never taken from the client, so a response cannot be replayed to reset
+// its feedback.
+func newResponseID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return "resp_" + hex.EncodeToString(b[:])
+}
+
+// redactInput replaces personal data in a question and request-supplied
+// knowledge with placeholders recorded in vault.
+func redactInput(settings config.TenantSettings, language, question string, kb []string, vault *pii.Vault) (string, []string) {
+	redactor := redactorFor(settings, language)
+	question = redactor.Redact(question, vault)
+	redacted := make([]string, len(kb))
+	for i, k := range kb {
+		redacted[i] = redactor.Redact(k, vault)
+	}
+	return question, redacted
+}
+
+// redactorFor returns the tenant's PII redactor, falling back to the defaults
+// for the request language.
+func redactorFor(settings config.TenantSettings, language string) *pii.Redactor {
+	if kinds, err := pii.ParseKinds(settings.PIIKinds); err == nil && len(kinds) > 0 {
+		return pii.New(kinds)
+	}
+	return pii.New(pii.DefaultKinds(language))
+}
+
+// stickyKey keeps a conversation, or else a customer, on the same variant.
+// Anonymous one-off requests are assigned per response, so batch items are
+// spread across variants too.
+func stickyKey(req SupportQueryRequest, identity *auth.Identity, responseID string) string {
+	switch {
+	case req.ConversationID != "":
+		return "conv:" + req.ConversationID
+	case identity != nil && identity.CustomerID != "":
+		return "cust:" + identity.CustomerID
+	default:
+		return "resp:" + responseID
+	}
+}
+
+// newLLMRequest builds the RAG request for a question using the tenant's effective settings.
+func newLLMRequest(tenantID, language, question string, kb []string, settings config.TenantSettings) *llm.Request {
+	return &llm.Request{
+		Messages: []llm.Message{
+			{
+				Role:    "user",
+				Content: question,
+			},
+		},
+		MaxTokens:     settings.MaxTokens,
+		Temperature:   settings.Temperature,
+		Model:         settings.Model,
+		SystemPrompt:  settings.SystemPrompt,
+		KnowledgeBase: kb,
+		Language:      language,
+		TenantID:      tenantID,
+		BrandName:     settings.BrandName,
+		PromptVars:    settings.PromptVars,
+	}
 }
 
 func buildCacheKey(tenantID, language, question string) string {
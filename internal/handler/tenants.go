@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/middleware"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateTenantRequest represents the request body for registering a tenant
+type CreateTenantRequest struct {
+	ID        string              `json:"id" binding:"required"`
+	Name      string              `json:"name"`
+	Languages []string            `json:"languages"`
+	Config    config.TenantConfig `json:"config"`
+}
+
+// TenantConfigResponse shows a tenant's overrides alongside the settings they resolve to
+type TenantConfigResponse struct {
+	TenantID  string                `json:"tenant_id"`
+	Config    config.TenantConfig   `json:"config"`
+	Effective config.TenantSettings `json:"effective"`
+}
+
+// TenantHandler exposes tenant registry endpoints (platform admin only)
+type TenantHandler struct {
+	registry *tenant.Registry
+}
+
+// NewTenantHandler creates a new tenant handler
+func NewTenantHandler(registry *tenant.Registry) *TenantHandler {
+	return &TenantHandler{registry: registry}
+}
+
+// Create handles POST /v1/admin/tenants
+func (h *TenantHandler) Create(c *gin.Context) {
+	if !requirePlatformAdmin(c) {
+		return
+	}
+	var req CreateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: "+err.Error())
+		return
+	}
+
+	t, err := h.registry.Add(tenant.Tenant{ID: req.ID, Name: req.Name, Languages: req.Languages, Config: req.Config})
+	if err != nil {
+		respondTenantError(c, err)
+		return
+	}
+
+	identity, _ := middleware.IdentityFrom(c)
+	logger.Audit("tenant created", map[string]interface{}{
+		"tenant_id": t.ID,
+		"subject":   identity.Subject(),
+	})
+	c.JSON(http.StatusCreated, t)
+}
+
+// List handles GET /v1/admin/tenants
+func (h *TenantHandler) List(c *gin.Context) {
+	if !requirePlatformAdmin(c) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"tenants":             h.registry.List(),
+		"supported_languages": h.registry.SupportedLanguages(),
+	})
+}
+
+// Get handles GET /v1/admin/tenants/:id
+func (h *TenantHandler) Get(c *gin.Context) {
+	if !requirePlatformAdmin(c) {
+		return
+	}
+	t, err := h.registry.Get(c.Param("id"))
+	if err != nil {
+		respondTenantError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, t)
+}
+
+// GetConfig handles GET /v1/admin/tenants/:id/config
+func (h *TenantHandler) GetConfig(c *gin.Context) {
+	if !requirePlatformAdmin(c) {
+		return
+	}
+	t, err := h.registry.Get(c.Param("id"))
+	if err != nil {
+		respondTenantError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, TenantConfigResponse{TenantID: t.ID, Config: t.Config, Effective: h.registry.Settings(t)})
+}
+
+// UpdateConfig handles PUT /v1/admin/tenants/:id/config, replacing all overrides
+func (h *TenantHandler) UpdateConfig(c *gin.Context) {
+	if !requirePlatformAdmin(c) {
+		return
+	}
+	var cfg config.TenantConfig
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: "+err.Error())
+		return
+	}
+
+	t, err := h.registry.SetConfig(c.Param("id"), cfg)
+	if err != nil {
+		respondTenantError(c, err)
+		return
+	}
+
+	identity, _ := middleware.IdentityFrom(c)
+	logger.Audit("tenant config updated", map[string]interface{}{
+		"tenant_id": t.ID,
+		"subject":   identity.Subject(),
+	})
+	c.JSON(http.StatusOK, TenantConfigResponse{TenantID: t.ID, Config: t.Config, Effective: h.registry.Settings(t)})
+}
+
+// Enable handles POST /v1/admin/tenants/:id/enable
+func (h *TenantHandler) Enable(c *gin.Context) {
+	h.setEnabled(c, true)
+}
+
+// Disable handles POST /v1/admin/tenants/:id/disable
+func (h *TenantHandler) Disable(c *gin.Context) {
+	h.setEnabled(c, false)
+}
+
+func (h *TenantHandler) setEnabled(c *gin.Context, enabled bool) {
+	if !requirePlatformAdmin(c) {
+		return
+	}
+	t, err := h.registry.SetEnabled(c.Param("id"), enabled)
+	if err != nil {
+		respondTenantError(c, err)
+		return
+	}
+
+	identity, _ := middleware.IdentityFrom(c)
+	logger.Audit("tenant updated", map[string]interface{}{
+		"tenant_id": t.ID,
+		"enabled":   t.Enabled,
+		"subject":   identity.Subject(),
+	})
+	c.JSON(http.StatusOK, t)
+}
+
+// requirePlatformAdmin rejects tenant-bound identities; the registry spans all tenants.
+func requirePlatformAdmin(c *gin.Context) bool {
+	if identity, ok := middleware.IdentityFrom(c); ok && !identity.IsPlatform() {
+		respondError(c, http.StatusForbidden, "FORBIDDEN", "Platform admin credentials required")
+		return false
+	}
+	return true
+}
+
+// respondTenantError maps registry errors onto the standard error envelope.
+func respondTenantError(c *gin.Context, err error) {
+	tenantError(err).respond(c)
+}
+
+// tenantError maps a registry error onto a queryError.
+func tenantError(err error) *queryError {
+	switch {
+	case errors.Is(err, tenant.ErrTenantNotFound):
+		return &queryError{http.StatusNotFound, "TENANT_NOT_FOUND", "Tenant not found"}
+	case errors.Is(err, tenant.ErrTenantDisabled):
+		return &queryError{http.StatusForbidden, "TENANT_DISABLED", "Tenant is disabled"}
+	case errors.Is(err, tenant.ErrUnsupportedLanguage):
+		return &queryError{http.StatusBadRequest, "UNSUPPORTED_LANGUAGE", "Unsupported language"}
+	case errors.Is(err, tenant.ErrInvalidTenant):
+		return &queryError{http.StatusBadRequest, "INVALID_REQUEST", "Invalid request: " + err.Error()}
+	case errors.Is(err, tenant.ErrTenantExists):
+		return &queryError{http.StatusConflict, "TENANT_EXISTS", "Tenant already exists"}
+	default:
+		logger.Error("tenant registry error", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return &queryError{http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to update tenant registry"}
+	}
+}
@@ -0,0 +1,96 @@
+package intent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/llm"
+)
+
+type stubClassifier struct {
+	intent string
+	err    error
+	calls  int
+}
+
+func (s *stubClassifier) Classify(context.Context, string) (string, error) {
+	s.calls++
+	return s.intent, s.err
+}
+
+func TestClassify_Rules(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Where is my order #123?", OrderStatus},
+		{"Has my package shipped yet?", OrderStatus},
+		{"Di mana pesanan saya?", OrderStatus},
+		{"I want a refund for the broken blender", Refund},
+		{"Bagaimana cara pengembalian dana?", Refund},
+		{"Please delete my account and all my data", AccountDeletion},
+		{"Tolong hapus akun saya", AccountDeletion},
+		{"Your courier was rude and this is unacceptable", Complaint},
+		{"Saya sangat kecewa dengan layanan ini", Complaint},
+		{"Hello!", Greeting},
+		{"terima kasih", Greeting},
+		// A complaint that mentions a refund is still sensitive
+		{"This is a scam, I want my money back", Complaint},
+	}
+
+	c := New(nil)
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := c.Classify(context.Background(), tt.text)
+			if got.Intent != tt.want || got.Source != "rules" {
+				t.Errorf("Classify(%q) = %+v, want %s from rules", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassify_LLMFallback(t *testing.T) {
+	stub := &stubClassifier{intent: Refund}
+	c := New(stub)
+
+	if got := c.Classify(context.Background(), "Where is my order?"); got.Source != "rules" || stub.calls != 0 {
+		t.Errorf("Classify() = %+v, calls = %d, want rules without LLM", got, stub.calls)
+	}
+	if got := c.Classify(context.Background(), "The blender I got is not what I ordered"); got.Intent != Refund || got.Source != "llm" {
+		t.Errorf("Classify() = %+v, want refund from llm", got)
+	}
+
+	stub.err = errors.New("timeout")
+	if got := c.Classify(context.Background(), "Something odd happened"); got.Intent != General || got.Source != "default" {
+		t.Errorf("Classify() = %+v, want general default on classifier error", got)
+	}
+}
+
+func TestRouteFor(t *testing.T) {
+	if got := RouteFor(AccountDeletion, nil); got != RouteEscalate {
+		t.Errorf("RouteFor(account_deletion) = %s, want escalate", got)
+	}
+	if got := RouteFor(Refund, nil); got != RouteRAG {
+		t.Errorf("RouteFor(refund) = %s, want rag", got)
+	}
+	if got := RouteFor(Complaint, map[string]string{Complaint: "rag"}); got != RouteRAG {
+		t.Errorf("RouteFor(complaint) with override = %s, want rag", got)
+	}
+}
+
+type labelClient struct{ label string }
+
+func (l labelClient) GenerateAnswer(context.Context, *llm.Request) (*llm.Response, error) {
+	return &llm.Response{Content: l.label}, nil
+}
+
+func TestLLMClassifier(t *testing.T) {
+	got, err := NewLLMClassifier(labelClient{"Order_Status."}, "").Classify(context.Background(), "hm")
+	if err != nil || got != OrderStatus {
+		t.Errorf("Classify() = %q, %v, want order_status", got, err)
+	}
+	if _, err := NewLLMClassifier(labelClient{"shipping"}, "").Classify(context.Background(), "hm"); err == nil {
+		t.Error("Classify() error = nil, want error for unknown label")
+	}
+}
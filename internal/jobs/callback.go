@@ -0,0 +1,169 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/netguard"
+)
+
+// ErrCallbackNotAllowed is returned for callback URLs that are not http(s) or
+// whose host is not allowed.
+var ErrCallbackNotAllowed = errors.New("callback url not allowed")
+
+// SignatureHeader carries the HMAC-SHA256 of the callback body, hex encoded
+// and prefixed with "sha256=", when a callback secret is configured.
+const SignatureHeader = "X-Signature"
+
+// Delivery records the outcome of a job's callback.
+type Delivery struct {
+	Delivered bool   `json:"delivered"`
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// Notifier POSTs finished jobs to their callback URL.
+type Notifier struct {
+	client       *http.Client
+	secret       []byte
+	allowedHosts map[string]bool
+	maxAttempts  int
+	retryDelay   time.Duration
+}
+
+// NewNotifier creates a notifier. A non-empty allowedHosts restricts which
+// hosts callbacks may be sent to; without one, callbacks may not reach
+// loopback, private or link-local addresses. Redirects are not followed, and
+// an empty secret leaves callbacks unsigned.
+func NewNotifier(timeout time.Duration, secret string, allowedHosts []string, maxAttempts int) *Notifier {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	n := &Notifier{
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		secret:      []byte(secret),
+		maxAttempts: maxAttempts,
+		retryDelay:  time.Second,
+	}
+	if len(allowedHosts) > 0 {
+		n.allowedHosts = make(map[string]bool, len(allowedHosts))
+		for _, h := range allowedHosts {
+			n.allowedHosts[strings.ToLower(h)] = true
+		}
+	} else {
+		n.client.Transport = netguard.Transport()
+	}
+	return n
+}
+
+// Check validates a callback URL before a job is accepted.
+func (n *Notifier) Check(rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: %s", ErrCallbackNotAllowed, rawURL)
+	}
+	if n.allowedHosts != nil && !n.allowedHosts[strings.ToLower(target.Hostname())] {
+		return fmt.Errorf("%w: %s", ErrCallbackNotAllowed, target.Hostname())
+	}
+	return nil
+}
+
+// Notify POSTs the job to its callback URL, retrying failed deliveries with
+// backoff. Any 2xx response counts as delivered.
+func (n *Notifier) Notify(ctx context.Context, job Job) Delivery {
+	var d Delivery
+	body, err := json.Marshal(job)
+	if err != nil {
+		d.LastError = err.Error()
+		return d
+	}
+
+	delay := n.retryDelay
+	for d.Attempts < n.maxAttempts {
+		d.Attempts++
+		err := n.post(ctx, job.CallbackURL, body)
+		if err == nil {
+			d.Delivered = true
+			d.LastError = ""
+			break
+		}
+		d.LastError = err.Error()
+		if d.Attempts == n.maxAttempts {
+			break
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return d
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+
+	fields := map[string]interface{}{
+		"job_id":    job.ID,
+		"tenant_id": job.TenantID,
+		"delivered": d.Delivered,
+		"attempts":  d.Attempts,
+	}
+	if d.Delivered {
+		logger.Info("job callback delivered", fields)
+	} else {
+		fields["error"] = d.LastError
+		logger.Error("job callback failed", fields)
+	}
+	return d
+}
+
+func (n *Notifier) post(ctx context.Context, rawURL string, body []byte) error {
+	if err := n.Check(rawURL); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(n.secret, body))
+	}
+	resp, err := n.client.Do(req)
+	if errors.Is(err, netguard.ErrBlockedAddress) {
+		return fmt.Errorf("%w: %v", ErrCallbackNotAllowed, err)
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("callback returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Sign returns the signature header value for body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
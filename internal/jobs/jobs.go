@@ -0,0 +1,497 @@
+// Package jobs runs support queries asynchronously: an in-process worker pool
+// with a persisted queue, status polling and completion callbacks.
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+)
+
+// Status is where a job is in its lifecycle.
+type Status string
+
+const (
+	StatusQueued    Status = "queued"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+var (
+	ErrQueueFull   = errors.New("job queue is full")
+	ErrJobNotFound = errors.New("job not found")
+)
+
+// Error is why a job failed. Retryable errors are run again, up to MaxAttempts.
+type Error struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Retryable bool   `json:"-"`
+}
+
+// Job is the public view of a job.
+type Job struct {
+	ID          string          `json:"id"`
+	TenantID    string          `json:"tenant_id"`
+	Status      Status          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	Error       *Error          `json:"error,omitempty"`
+	CallbackURL string          `json:"callback_url,omitempty"`
+	Callback    *Delivery       `json:"callback,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+}
+
+// Done reports whether the job has finished.
+func (j Job) Done() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed
+}
+
+// record is a job with its payload, as kept in memory and on disk. The
+// payload is dropped once the job is done so customer questions are not kept
+// longer than needed.
+type record struct {
+	Job
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// Func runs a job's payload and returns its result.
+type Func func(ctx context.Context, job Job, payload json.RawMessage) (json.RawMessage, *Error)
+
+// Config bounds the queue.
+type Config struct {
+	Workers       int           // Jobs run at once
+	QueueSize     int           // Jobs waiting to run; Submit fails beyond it
+	MaxAttempts   int           // Runs per job, including retries
+	RetryDelay    time.Duration // First retry delay, doubled on each retry
+	Retention     time.Duration // How long finished jobs can be polled
+	Path          string        // JSON snapshot file; empty keeps jobs in memory
+	FlushInterval time.Duration // How often status changes are written to Path
+}
+
+// Queue runs jobs on a pool of workers. It is safe for concurrent use.
+type Queue struct {
+	cfg      Config
+	run      Func
+	notifier *Notifier
+
+	mu      sync.Mutex
+	jobs    map[string]*record
+	pending chan string
+	waiting int // Queued jobs, including those waiting for a retry
+	dirty   bool
+	changed chan struct{}
+	wg      sync.WaitGroup
+	now     func() time.Time
+
+	saveMu sync.Mutex // Serialises snapshot writes
+}
+
+// NewQueue creates a queue running jobs with run. A nil notifier disables callbacks.
+func NewQueue(run Func, notifier *Notifier, cfg Config) *Queue {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 1
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 100
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = time.Second
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	return &Queue{
+		cfg:      cfg,
+		run:      run,
+		notifier: notifier,
+		jobs:     map[string]*record{},
+		pending:  make(chan string, cfg.QueueSize),
+		changed:  make(chan struct{}, 1),
+		now:      time.Now,
+	}
+}
+
+// Load restores jobs from the snapshot file. Jobs that were queued or running
+// when the process stopped are queued again, and undelivered callbacks of
+// finished jobs are retried once workers start. A missing file is not an error.
+func (q *Queue) Load() error {
+	if q.cfg.Path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(q.cfg.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read jobs: %w", err)
+	}
+	var snapshot []*record
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse jobs: %w", err)
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var requeue []string
+	for _, r := range snapshot {
+		q.jobs[r.ID] = r
+		if !r.Done() {
+			r.Status = StatusQueued
+			requeue = append(requeue, r.ID)
+		}
+	}
+	// Restored jobs may exceed QueueSize; they are never dropped
+	if len(requeue) > cap(q.pending) {
+		q.pending = make(chan string, len(requeue)+q.cfg.QueueSize)
+	}
+	for _, id := range requeue {
+		q.pending <- id
+	}
+	q.waiting = len(requeue)
+	return nil
+}
+
+// Start runs the workers until ctx is done. Jobs interrupted by shutdown stay
+// queued and run again after the next Load. Status changes are written to the
+// snapshot file at most once per FlushInterval.
+func (q *Queue) Start(ctx context.Context) {
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.wg.Add(1)
+		go q.worker(ctx)
+	}
+	q.mu.Lock()
+	var redeliver []Job
+	for _, r := range q.jobs {
+		if r.Done() && q.undelivered(r) {
+			redeliver = append(redeliver, r.Job)
+		}
+	}
+	q.mu.Unlock()
+	for _, job := range redeliver {
+		q.deliver(ctx, job)
+	}
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				q.prune()
+			}
+		}
+	}()
+
+	q.wg.Add(1)
+	go q.flusher(ctx)
+}
+
+// Wait blocks until the workers and callback deliveries have stopped, then
+// writes any unsaved changes.
+func (q *Queue) Wait() {
+	q.wg.Wait()
+	q.flush()
+}
+
+// Submit queues a job for tenantID. The returned job has its ID and status.
+// With a Path, the job is written to the snapshot before Submit returns; if
+// that fails the job is dropped and the write error is returned.
+func (q *Queue) Submit(tenantID string, payload json.RawMessage, callbackURL string) (Job, error) {
+	q.mu.Lock()
+	if q.waiting >= q.cfg.QueueSize {
+		q.mu.Unlock()
+		return Job{}, ErrQueueFull
+	}
+	now := q.now()
+	r := &record{
+		Job: Job{
+			ID:          newID(),
+			TenantID:    tenantID,
+			Status:      StatusQueued,
+			CallbackURL: callbackURL,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+		Payload: payload,
+	}
+	// The slot is reserved while the job is written; it only runs once saved
+	q.jobs[r.ID] = r
+	q.waiting++
+	q.markDirtyLocked()
+	q.mu.Unlock()
+
+	err := q.flush()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err == nil {
+		select {
+		case q.pending <- r.ID:
+			return r.Job, nil
+		default:
+			err = ErrQueueFull
+		}
+	}
+	delete(q.jobs, r.ID)
+	q.waiting--
+	q.markDirtyLocked()
+	return Job{}, err
+}
+
+// Get returns a job by ID.
+func (q *Queue) Get(id string) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	r, ok := q.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	return r.Job, nil
+}
+
+func (q *Queue) worker(ctx context.Context) {
+	defer q.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-q.pending:
+			q.process(ctx, id)
+		}
+	}
+}
+
+func (q *Queue) process(ctx context.Context, id string) {
+	q.mu.Lock()
+	r, ok := q.jobs[id]
+	if !ok {
+		q.waiting--
+		q.mu.Unlock()
+		return
+	}
+	q.waiting--
+	r.Status = StatusRunning
+	r.Attempts++
+	r.UpdatedAt = q.now()
+	job, payload := r.Job, r.Payload
+	q.markDirtyLocked()
+	q.mu.Unlock()
+
+	result, jobErr := q.run(ctx, job, payload)
+	if ctx.Err() != nil {
+		// Shutting down; the job is queued again on the next start
+		return
+	}
+
+	q.mu.Lock()
+	now := q.now()
+	r.UpdatedAt = now
+	if jobErr != nil && jobErr.Retryable && r.Attempts < q.cfg.MaxAttempts {
+		r.Status = StatusQueued
+		r.Error = jobErr
+		q.waiting++
+		q.markDirtyLocked()
+		q.mu.Unlock()
+		q.retryLater(ctx, id, q.cfg.RetryDelay<<(r.Attempts-1))
+		return
+	}
+	if jobErr != nil {
+		r.Status = StatusFailed
+		r.Error = jobErr
+	} else {
+		r.Status = StatusSucceeded
+		r.Result = result
+		r.Error = nil
+	}
+	r.CompletedAt = &now
+	r.Payload = nil
+	job = r.Job
+	q.markDirtyLocked()
+	q.mu.Unlock()
+
+	logger.Info("job completed", map[string]interface{}{
+		"job_id":    job.ID,
+		"tenant_id": job.TenantID,
+		"status":    string(job.Status),
+		"attempts":  job.Attempts,
+	})
+	if job.CallbackURL != "" {
+		q.deliver(ctx, job)
+	}
+}
+
+// retryLater queues a job again after delay, unless the queue is shutting down.
+func (q *Queue) retryLater(ctx context.Context, id string, delay time.Duration) {
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+			select {
+			case q.pending <- id:
+			case <-ctx.Done():
+			}
+		}
+	}()
+}
+
+// deliver sends the callback in the background and records the outcome.
+func (q *Queue) deliver(ctx context.Context, job Job) {
+	if q.notifier == nil {
+		return
+	}
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		delivery := q.notifier.Notify(ctx, job)
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if r, ok := q.jobs[job.ID]; ok {
+			r.Callback = &delivery
+			q.markDirtyLocked()
+		}
+	}()
+}
+
+func (q *Queue) undelivered(r *record) bool {
+	return r.CallbackURL != "" && q.notifier != nil && (r.Callback == nil || !r.Callback.Delivered)
+}
+
+// prune forgets finished jobs older than the retention window.
+func (q *Queue) prune() {
+	if q.cfg.Retention <= 0 {
+		return
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	cutoff := q.now().Add(-q.cfg.Retention)
+	removed := false
+	for id, r := range q.jobs {
+		if r.Done() && r.CompletedAt.Before(cutoff) {
+			delete(q.jobs, id)
+			removed = true
+		}
+	}
+	if removed {
+		q.markDirtyLocked()
+	}
+}
+
+// markDirtyLocked notes that the snapshot is out of date and wakes the flusher.
+func (q *Queue) markDirtyLocked() {
+	if q.cfg.Path == "" {
+		return
+	}
+	q.dirty = true
+	select {
+	case q.changed <- struct{}{}:
+	default:
+	}
+}
+
+// flusher writes the snapshot after changes, batching those made within
+// FlushInterval, until ctx is done.
+func (q *Queue) flusher(ctx context.Context) {
+	defer q.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.changed:
+		}
+		timer := time.NewTimer(q.cfg.FlushInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		q.flush()
+	}
+}
+
+// flush writes the snapshot atomically (temp file + rename) if it is out of
+// date. Jobs are copied under the lock and written outside it. Failures are
+// logged and retried on the next flush; the in-memory queue keeps working.
+func (q *Queue) flush() error {
+	q.saveMu.Lock()
+	defer q.saveMu.Unlock()
+
+	q.mu.Lock()
+	if !q.dirty {
+		q.mu.Unlock()
+		return nil
+	}
+	snapshot := make([]record, 0, len(q.jobs))
+	for _, r := range q.jobs {
+		snapshot = append(snapshot, *r)
+	}
+	q.dirty = false
+	q.mu.Unlock()
+
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].CreatedAt.Before(snapshot[j].CreatedAt) })
+	if err := writeFile(q.cfg.Path, snapshot); err != nil {
+		q.mu.Lock()
+		q.markDirtyLocked()
+		q.mu.Unlock()
+		logger.Error("failed to persist jobs", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return err
+	}
+	return nil
+}
+
+func writeFile(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".jobs-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func newID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "job_" + hex.EncodeToString(b)
+}
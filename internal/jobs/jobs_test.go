@@ -0,0 +1,303 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func echo(ctx context.Context, job Job, payload json.RawMessage) (json.RawMessage, *Error) {
+	return payload, nil
+}
+
+// waitDone polls until the job has finished.
+func waitDone(t *testing.T, q *Queue, id string) Job {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		job, err := q.Get(id)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if job.Done() {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return Job{}
+}
+
+func TestQueue_RunsJobs(t *testing.T) {
+	q := NewQueue(echo, nil, Config{Workers: 2, QueueSize: 10})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q.Start(ctx)
+
+	job, err := q.Submit("shop-123", json.RawMessage(`{"answer":"42"}`), "")
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if job.Status != StatusQueued || job.ID == "" {
+		t.Fatalf("Submit() = %+v, want a queued job with an ID", job)
+	}
+
+	done := waitDone(t, q, job.ID)
+	if done.Status != StatusSucceeded || string(done.Result) != `{"answer":"42"}` || done.Attempts != 1 {
+		t.Errorf("job = %+v, want succeeded with the echoed result", done)
+	}
+	if done.CompletedAt == nil {
+		t.Error("CompletedAt not set")
+	}
+	if _, err := q.Get("job_missing"); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrJobNotFound", err)
+	}
+}
+
+func TestQueue_RetriesRetryableErrors(t *testing.T) {
+	var calls int32
+	run := func(ctx context.Context, job Job, payload json.RawMessage) (json.RawMessage, *Error) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			return nil, &Error{Code: "RATE_LIMIT_EXCEEDED", Message: "slow down", Retryable: true}
+		}
+		return json.RawMessage(`"ok"`), nil
+	}
+	q := NewQueue(run, nil, Config{Workers: 1, MaxAttempts: 3, RetryDelay: time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q.Start(ctx)
+
+	job, _ := q.Submit("shop-123", nil, "")
+	done := waitDone(t, q, job.ID)
+	if done.Status != StatusSucceeded || done.Attempts != 3 || done.Error != nil {
+		t.Errorf("job = %+v, want succeeded after 3 attempts", done)
+	}
+
+	// Non-retryable errors fail at once
+	q = NewQueue(func(ctx context.Context, job Job, payload json.RawMessage) (json.RawMessage, *Error) {
+		return nil, &Error{Code: "TENANT_DISABLED", Message: "disabled"}
+	}, nil, Config{Workers: 1, MaxAttempts: 3, RetryDelay: time.Millisecond})
+	q.Start(ctx)
+	job, _ = q.Submit("shop-123", nil, "")
+	done = waitDone(t, q, job.ID)
+	if done.Status != StatusFailed || done.Attempts != 1 || done.Error == nil || done.Error.Code != "TENANT_DISABLED" {
+		t.Errorf("job = %+v, want failed after 1 attempt", done)
+	}
+}
+
+func TestQueue_QueueFull(t *testing.T) {
+	q := NewQueue(echo, nil, Config{Workers: 1, QueueSize: 1})
+	if _, err := q.Submit("shop-123", nil, ""); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	if _, err := q.Submit("shop-123", nil, ""); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Submit() error = %v, want ErrQueueFull", err)
+	}
+}
+
+func TestQueue_PersistsAndRequeues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+
+	// Not started or waited on: an accepted job is already saved when the
+	// process "stops"
+	q := NewQueue(echo, nil, Config{Path: path})
+	job, err := q.Submit("shop-123", json.RawMessage(`{"q":1}`), "")
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	restarted := NewQueue(echo, nil, Config{Path: path})
+	if err := restarted.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	restarted.Start(ctx)
+	done := waitDone(t, restarted, job.ID)
+	if done.Status != StatusSucceeded || string(done.Result) != `{"q":1}` {
+		t.Errorf("job = %+v, want the restored job to run", done)
+	}
+	cancel()
+	restarted.Wait()
+
+	// Finished jobs are kept without their payload
+	final := NewQueue(echo, nil, Config{Path: path})
+	if err := final.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if r := final.jobs[job.ID]; r == nil || r.Status != StatusSucceeded || r.Payload != nil {
+		t.Errorf("restored record = %+v, want succeeded without payload", r)
+	}
+
+	missing := NewQueue(echo, nil, Config{Path: filepath.Join(t.TempDir(), "none.json")})
+	if err := missing.Load(); err != nil {
+		t.Errorf("Load(missing file) error = %v, want nil", err)
+	}
+}
+
+func TestQueue_SubmitFailsWhenNotSaved(t *testing.T) {
+	q := NewQueue(echo, nil, Config{Path: filepath.Join(t.TempDir(), "missing", "jobs.json")})
+	if _, err := q.Submit("shop-123", json.RawMessage(`{"q":1}`), ""); err == nil || errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Submit() error = %v, want the write error", err)
+	}
+	if len(q.jobs) != 0 || q.waiting != 0 || len(q.pending) != 0 {
+		t.Errorf("jobs = %d, waiting = %d, pending = %d; want the job dropped", len(q.jobs), q.waiting, len(q.pending))
+	}
+}
+
+func TestQueue_FlushesInBackground(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.json")
+	block := make(chan struct{})
+	defer close(block)
+	run := func(ctx context.Context, job Job, payload json.RawMessage) (json.RawMessage, *Error) {
+		<-block
+		return payload, nil
+	}
+	q := NewQueue(run, nil, Config{Path: path, FlushInterval: 10 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q.Start(ctx)
+
+	job, err := q.Submit("shop-123", json.RawMessage(`{"q":1}`), "")
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(path); err == nil && strings.Contains(string(data), job.ID) {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("running job not written to the snapshot")
+}
+
+func TestQueue_PrunesFinishedJobs(t *testing.T) {
+	q := NewQueue(echo, nil, Config{Retention: time.Hour})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q.Start(ctx)
+	job, _ := q.Submit("shop-123", nil, "")
+	waitDone(t, q, job.ID)
+
+	q.prune()
+	if _, err := q.Get(job.ID); err != nil {
+		t.Fatalf("job pruned before retention: %v", err)
+	}
+	q.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	q.prune()
+	if _, err := q.Get(job.ID); !errors.Is(err, ErrJobNotFound) {
+		t.Errorf("Get() error = %v, want job pruned", err)
+	}
+}
+
+func TestNotifier_DeliversSignedCallback(t *testing.T) {
+	var attempts int32
+	received := make(chan *http.Request, 1)
+	var body []byte
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ = io.ReadAll(r.Body)
+		received <- r
+	}))
+	defer backend.Close()
+
+	notifier := NewNotifier(time.Second, "s3cret", []string{"127.0.0.1"}, 3)
+	notifier.retryDelay = time.Millisecond
+	q := NewQueue(echo, notifier, Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	q.Start(ctx)
+
+	job, _ := q.Submit("shop-123", json.RawMessage(`"hi"`), backend.URL+"/hooks/support")
+	var r *http.Request
+	select {
+	case r = <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("callback not delivered")
+	}
+	if got, want := r.Header.Get(SignatureHeader), Sign([]byte("s3cret"), body); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	var delivered Job
+	if err := json.Unmarshal(body, &delivered); err != nil {
+		t.Fatalf("callback body: %v", err)
+	}
+	if delivered.ID != job.ID || delivered.Status != StatusSucceeded || string(delivered.Result) != `"hi"` {
+		t.Errorf("callback job = %+v", delivered)
+	}
+
+	// The outcome is recorded on the job once the delivery returns
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if got, _ := q.Get(job.ID); got.Callback != nil {
+			if !got.Callback.Delivered || got.Callback.Attempts != 2 {
+				t.Errorf("callback = %+v, want delivered on attempt 2", got.Callback)
+			}
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Error("callback outcome not recorded")
+}
+
+func TestNotifier_BlocksInternalAddresses(t *testing.T) {
+	var hits int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer backend.Close()
+
+	// Without an allowlist, loopback is refused at connect time
+	job := Job{ID: "job_1", CallbackURL: backend.URL}
+	if d := NewNotifier(time.Second, "", nil, 1).Notify(context.Background(), job); d.Delivered {
+		t.Errorf("Notify(loopback) = %+v, want not delivered", d)
+	}
+
+	// Allowed hosts may not redirect elsewhere
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, backend.URL, http.StatusTemporaryRedirect)
+	}))
+	defer redirect.Close()
+	job.CallbackURL = redirect.URL
+	if d := NewNotifier(time.Second, "", []string{"127.0.0.1"}, 1).Notify(context.Background(), job); d.Delivered {
+		t.Errorf("Notify(redirect) = %+v, want not delivered", d)
+	}
+	if n := atomic.LoadInt32(&hits); n != 0 {
+		t.Errorf("internal server received %d requests, want 0", n)
+	}
+}
+
+func TestNotifier_Check(t *testing.T) {
+	n := NewNotifier(time.Second, "", []string{"hooks.example.com"}, 1)
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{url: "https://hooks.example.com/support"},
+		{url: "http://HOOKS.example.com:8080/support"},
+		{url: "https://evil.example.com/support", wantErr: true},
+		{url: "ftp://hooks.example.com/support", wantErr: true},
+		{url: "not a url", wantErr: true},
+	}
+	for _, tt := range tests {
+		err := n.Check(tt.url)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Check(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrCallbackNotAllowed) {
+			t.Errorf("Check(%q) error = %v, want ErrCallbackNotAllowed", tt.url, err)
+		}
+	}
+}
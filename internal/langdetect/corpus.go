@@ -0,0 +1,100 @@
+package langdetect
+
+// corpus is the training text for each language profile. It mixes customer
+// support phrasing with general prose so short questions and longer messages
+// both score well.
+var corpus = map[string]string{
+	"en": `Where is my order? I placed an order last week and it still has not arrived.
+Can you tell me when my package will be delivered? I would like to return this item and get a refund.
+How do I change my shipping address? My payment was declined but the money was taken from my account.
+Please cancel my subscription. I forgot my password and cannot log in to my account.
+What is your refund policy for items that arrived damaged? The product I received is not what I ordered.
+Thank you for your help, that answers my question. Is there a discount for new customers?
+The quick brown fox jumps over the lazy dog. We are happy to help you with anything you need.
+Our support team is available every day from nine in the morning until six in the evening.
+You can track the status of your order from the orders page in your account settings.
+It usually takes three to five business days for the courier to deliver your parcel.
+If you have any other questions, please let us know and we will get back to you as soon as possible.
+Which payment methods do you accept? Could I pay with a credit card or through a bank transfer?
+The weather was nice today, so they walked through the park and talked about their plans for the weekend.`,
+
+	"id": `Di mana pesanan saya? Saya sudah memesan minggu lalu tetapi barangnya belum sampai.
+Kapan paket saya akan dikirim? Saya ingin mengembalikan barang ini dan mendapatkan pengembalian dana.
+Bagaimana cara mengubah alamat pengiriman? Pembayaran saya ditolak tetapi uangnya sudah terpotong dari rekening.
+Tolong batalkan langganan saya. Saya lupa kata sandi dan tidak bisa masuk ke akun saya.
+Apa kebijakan pengembalian untuk barang yang rusak saat diterima? Produk yang saya terima tidak sesuai dengan pesanan.
+Terima kasih atas bantuannya, pertanyaan saya sudah terjawab. Apakah ada diskon untuk pelanggan baru?
+Tim dukungan kami tersedia setiap hari mulai pukul sembilan pagi sampai pukul enam sore.
+Anda dapat melacak status pesanan melalui halaman pesanan di pengaturan akun Anda.
+Biasanya kurir membutuhkan waktu tiga sampai lima hari kerja untuk mengantarkan paket Anda.
+Jika masih ada pertanyaan lain, silakan hubungi kami dan kami akan segera membalas.
+Metode pembayaran apa saja yang diterima? Bisakah saya membayar dengan kartu kredit atau transfer bank?
+Cuaca hari ini sangat cerah, jadi mereka berjalan-jalan di taman dan membicarakan rencana akhir pekan.
+Mengapa pesanannya belum dikirim juga? Berapa lama proses pengembalian dana biasanya berlangsung?
+Kak, barang saya kapan sampai ya? Saya mau refund karena barangnya nggak sesuai. Tolong hapus akun saya sekarang.
+Gimana cara ubah kata sandi akun? Kok pesanan saya belum dikirim, padahal sudah bayar dari kemarin.
+Saya sudah menunggu lama sekali, tolong bantu cek status pesanan saya. Bisakah saya ganti nomor telepon di akun?
+Apakah barang ini masih ada stoknya? Ongkos kirim ke Surabaya berapa? Terima kasih banyak, Kak.
+Uang saya belum dikembalikan sampai sekarang. Saya ingin menghapus data pribadi saya dari aplikasi ini.`,
+
+	"es": `¿Dónde está mi pedido? Hice un pedido la semana pasada y todavía no ha llegado.
+¿Cuándo se entregará mi paquete? Me gustaría devolver este artículo y obtener un reembolso.
+¿Cómo puedo cambiar mi dirección de envío? Mi pago fue rechazado pero el dinero se descontó de mi cuenta.
+Por favor, cancelen mi suscripción. Olvidé mi contraseña y no puedo iniciar sesión en mi cuenta.
+¿Cuál es su política de reembolso para los productos que llegaron dañados? Gracias por su ayuda.
+Nuestro equipo de atención está disponible todos los días de nueve de la mañana a seis de la tarde.
+Puede consultar el estado de su pedido en la página de pedidos de su cuenta.`,
+
+	"fr": `Où est ma commande ? J'ai passé une commande la semaine dernière et elle n'est toujours pas arrivée.
+Quand mon colis sera-t-il livré ? Je voudrais retourner cet article et obtenir un remboursement.
+Comment puis-je modifier mon adresse de livraison ? Mon paiement a été refusé mais l'argent a été débité de mon compte.
+Veuillez annuler mon abonnement. J'ai oublié mon mot de passe et je ne peux pas me connecter à mon compte.
+Quelle est votre politique de remboursement pour les articles arrivés endommagés ? Merci pour votre aide.
+Notre équipe d'assistance est disponible tous les jours de neuf heures du matin à six heures du soir.
+Vous pouvez suivre l'état de votre commande depuis la page des commandes de votre compte.`,
+
+	"de": `Wo ist meine Bestellung? Ich habe letzte Woche bestellt und sie ist immer noch nicht angekommen.
+Wann wird mein Paket geliefert? Ich möchte diesen Artikel zurückgeben und eine Rückerstattung erhalten.
+Wie kann ich meine Lieferadresse ändern? Meine Zahlung wurde abgelehnt, aber das Geld wurde von meinem Konto abgebucht.
+Bitte kündigen Sie mein Abonnement. Ich habe mein Passwort vergessen und kann mich nicht bei meinem Konto anmelden.
+Wie lauten Ihre Rückerstattungsrichtlinien für beschädigt angekommene Artikel? Vielen Dank für Ihre Hilfe.
+Unser Support-Team ist jeden Tag von neun Uhr morgens bis sechs Uhr abends erreichbar.
+Sie können den Status Ihrer Bestellung auf der Bestellseite in Ihrem Konto verfolgen.`,
+
+	"pt": `Onde está o meu pedido? Fiz um pedido na semana passada e ainda não chegou.
+Quando o meu pacote será entregue? Gostaria de devolver este item e receber um reembolso.
+Como posso alterar o meu endereço de entrega? O meu pagamento foi recusado, mas o dinheiro foi descontado da minha conta.
+Por favor, cancelem a minha assinatura. Esqueci a minha senha e não consigo entrar na minha conta.
+Qual é a política de reembolso para produtos que chegaram danificados? Obrigado pela ajuda.
+A nossa equipe de suporte está disponível todos os dias das nove da manhã às seis da tarde.
+Você pode acompanhar o status do seu pedido na página de pedidos da sua conta.`,
+	"ms": `Di manakah pesanan saya? Saya telah membuat pesanan minggu lepas tetapi barang itu masih belum sampai.
+Bilakah bungkusan saya akan dihantar? Saya mahu memulangkan barang ini dan mendapatkan bayaran balik.
+Bagaimanakah saya boleh menukar alamat penghantaran? Bayaran saya ditolak tetapi wang telah ditolak daripada akaun bank saya.
+Sila batalkan langganan saya. Saya terlupa kata laluan dan tidak boleh log masuk ke akaun saya.
+Apakah polisi bayaran balik untuk barang yang rosak semasa diterima? Produk yang saya terima tidak sama dengan pesanan saya.
+Terima kasih atas bantuan anda, soalan saya sudah terjawab. Adakah diskaun untuk pelanggan baharu?
+Pasukan sokongan kami boleh dihubungi setiap hari dari pukul sembilan pagi hingga pukul enam petang.
+Anda boleh menjejaki status pesanan melalui halaman pesanan dalam tetapan akaun anda.
+Biasanya kurier mengambil masa tiga hingga lima hari bekerja untuk menghantar bungkusan anda.
+Kalau ada soalan lain, sila hubungi kami dan kami akan membalas secepat mungkin.
+Kaedah pembayaran apa yang diterima? Bolehkah saya bayar dengan kad kredit atau pindahan bank?
+Kenapa pesanan saya belum dihantar lagi? Berapa lama proses bayaran balik biasanya mengambil masa?
+Saya nak tahu bila barang saya sampai sebab saya dah tunggu lama sangat.`,
+
+	"th": `คำสั่งซื้อของฉันอยู่ที่ไหน ฉันสั่งซื้อสินค้าเมื่อสัปดาห์ที่แล้วแต่ยังไม่ได้รับเลย
+พัสดุของฉันจะจัดส่งเมื่อไหร่ ฉันต้องการคืนสินค้าชิ้นนี้และขอเงินคืน
+ฉันจะเปลี่ยนที่อยู่จัดส่งได้อย่างไร การชำระเงินของฉันถูกปฏิเสธแต่เงินถูกหักจากบัญชีแล้ว
+กรุณายกเลิกการสมัครสมาชิกของฉัน ฉันลืมรหัสผ่านและเข้าสู่ระบบบัญชีไม่ได้
+นโยบายการคืนเงินสำหรับสินค้าที่ได้รับแล้วชำรุดเป็นอย่างไร ขอบคุณสำหรับความช่วยเหลือครับ
+ทีมงานบริการลูกค้าของเราพร้อมให้บริการทุกวันตั้งแต่เก้าโมงเช้าถึงหกโมงเย็น
+คุณสามารถติดตามสถานะคำสั่งซื้อได้จากหน้าคำสั่งซื้อในบัญชีของคุณ`,
+
+	"vi": `Đơn hàng của tôi đang ở đâu? Tôi đã đặt hàng tuần trước nhưng vẫn chưa nhận được.
+Khi nào gói hàng của tôi sẽ được giao? Tôi muốn trả lại sản phẩm này và được hoàn tiền.
+Làm thế nào để thay đổi địa chỉ giao hàng? Thanh toán của tôi bị từ chối nhưng tiền đã bị trừ khỏi tài khoản.
+Vui lòng hủy gói đăng ký của tôi. Tôi quên mật khẩu và không thể đăng nhập vào tài khoản.
+Chính sách hoàn tiền cho sản phẩm bị hư hỏng khi nhận là gì? Cảm ơn bạn đã giúp đỡ.
+Đội ngũ hỗ trợ của chúng tôi làm việc mỗi ngày từ chín giờ sáng đến sáu giờ chiều.
+Bạn có thể theo dõi trạng thái đơn hàng trên trang đơn hàng trong tài khoản của bạn.`,
+}
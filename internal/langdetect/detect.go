@@ -0,0 +1,168 @@
+// Package langdetect identifies the language of a short text offline using
+// character n-gram profiles.
+package langdetect
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxN is the longest n-gram in a profile.
+const maxN = 3
+
+// wordWeight is how much more a whole word counts than one n-gram.
+const wordWeight = 4
+
+// MinLetters is how many letters a text needs before a detection can be reliable.
+const MinLetters = 8
+
+// Result is a detection.
+type Result struct {
+	Language   string  `json:"language"`   // ISO 639-1 code; empty when the text has no letters
+	Confidence float64 `json:"confidence"` // Posterior probability of Language among the candidates
+}
+
+// Reliable reports whether the detection reaches minConfidence on enough text.
+func (r Result) Reliable(text string, minConfidence float64) bool {
+	return r.Language != "" && r.Confidence >= minConfidence && letters(text) >= MinLetters
+}
+
+// profile holds the log probability of every n-gram seen in a language's corpus.
+type profile struct {
+	logProb map[string]float64
+	unseen  float64 // Log probability of an n-gram not in the corpus
+}
+
+var profiles = map[string]profile{}
+
+func init() {
+	for lang, text := range corpus {
+		profiles[lang] = newProfile(text)
+	}
+}
+
+// newProfile counts n-grams with add-one smoothing.
+func newProfile(text string) profile {
+	counts := map[string]int{}
+	total := 0
+	for _, g := range ngrams(text) {
+		counts[g]++
+		total++
+	}
+	vocab := float64(len(counts) + 1)
+	p := profile{
+		logProb: make(map[string]float64, len(counts)),
+		unseen:  math.Log(1 / (float64(total) + vocab)),
+	}
+	for g, n := range counts {
+		p.logProb[g] = math.Log((float64(n) + 1) / (float64(total) + vocab))
+	}
+	return p
+}
+
+// Languages lists the languages with a profile, sorted.
+func Languages() []string {
+	out := make([]string, 0, len(profiles))
+	for l := range profiles {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Detect returns the most likely language of text among candidates, or among
+// every profiled language when candidates is empty. Candidates without a
+// profile are ignored.
+func Detect(text string, candidates []string) Result {
+	grams := ngrams(text)
+	if len(grams) == 0 {
+		return Result{}
+	}
+	if len(candidates) == 0 {
+		candidates = Languages()
+	}
+
+	scores := make(map[string]float64, len(candidates))
+	for _, lang := range candidates {
+		p, ok := profiles[lang]
+		if !ok {
+			continue
+		}
+		score, weights := 0.0, 0.0
+		for _, g := range grams {
+			w := 1.0
+			if len(g) > maxN {
+				w = wordWeight
+			}
+			lp, ok := p.logProb[g]
+			if !ok {
+				lp = p.unseen
+			}
+			score += w * lp
+			weights += w
+		}
+		// Average per n-gram so long texts don't saturate the posterior;
+		// the scale keeps short texts from looking certain
+		scores[lang] = score / weights * posteriorScale(len(grams))
+	}
+	if len(scores) == 0 {
+		return Result{}
+	}
+
+	best, bestScore := "", math.Inf(-1)
+	for lang, s := range scores {
+		if s > bestScore || (s == bestScore && lang < best) {
+			best, bestScore = lang, s
+		}
+	}
+	sum := 0.0
+	for _, s := range scores {
+		sum += math.Exp(s - bestScore)
+	}
+	return Result{Language: best, Confidence: 1 / sum}
+}
+
+// posteriorScale grows with the amount of evidence, up to a cap.
+func posteriorScale(grams int) float64 {
+	return math.Min(2*math.Sqrt(float64(grams)), 40)
+}
+
+// ngrams returns each word and its 1- to maxN-grams, padded with spaces so
+// prefixes and suffixes are distinct. Digits and punctuation are dropped.
+func ngrams(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		// Marks carry vowels and tones in scripts such as Thai
+		return !unicode.IsLetter(r) && !unicode.IsMark(r) && r != '\''
+	})
+	var out []string
+	for _, w := range words {
+		runes := []rune(" " + strings.Trim(w, "'") + " ")
+		if len(runes) <= 2 {
+			continue
+		}
+		// Whole words separate close languages such as Indonesian and Malay
+		out = append(out, string(runes))
+		for n := 1; n <= maxN; n++ {
+			for i := 0; i+n <= len(runes); i++ {
+				g := string(runes[i : i+n])
+				if g == " " {
+					continue
+				}
+				out = append(out, g)
+			}
+		}
+	}
+	return out
+}
+
+func letters(text string) int {
+	n := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			n++
+		}
+	}
+	return n
+}
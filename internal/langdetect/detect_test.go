@@ -0,0 +1,57 @@
+package langdetect
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Where is my order?", "en"},
+		{"I want a refund for the broken blender", "en"},
+		{"Bagaimana cara membatalkan pesanan?", "id"},
+		{"tolong hapus akun saya", "id"},
+		{"Saya lupa kata sandi akun saya", "id"},
+		{"Saya lupa kata laluan akaun saya", "ms"},
+		{"Bolehkah saya tukar alamat penghantaran?", "ms"},
+		{"คำสั่งซื้อของฉันอยู่ที่ไหน", "th"},
+		{"Đơn hàng của tôi ở đâu?", "vi"},
+		{"¿Dónde está mi pedido?", "es"},
+		{"Où est ma commande ?", "fr"},
+		{"Wo ist meine Bestellung?", "de"},
+		{"Onde está o meu pedido?", "pt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := Detect(tt.text, nil)
+			if got.Language != tt.want || !got.Reliable(tt.text, 0.8) {
+				t.Errorf("Detect(%q) = %+v, want reliable %s", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetect_Candidates(t *testing.T) {
+	// Short Indonesian is close to Malay; narrowing to the tenant's languages settles it
+	for _, text := range []string{"Di mana pesanan saya?", "Saya mau refund untuk pesanan #123"} {
+		if got := Detect(text, []string{"en", "id"}); got.Language != "id" {
+			t.Errorf("Detect(%q, [en id]) = %+v, want id", text, got)
+		}
+	}
+
+	got := Detect("¿Dónde está mi pedido?", []string{"en", "id", "xx"})
+	if got.Language != "en" && got.Language != "id" {
+		t.Errorf("Detect() = %+v, want one of the candidates", got)
+	}
+	if got := Detect("Where is my order?", []string{"xx"}); got.Language != "" {
+		t.Errorf("Detect() = %+v, want empty without profiled candidates", got)
+	}
+}
+
+func TestDetect_Unreliable(t *testing.T) {
+	for _, text := range []string{"hi", "ok", "refund", "#123 ???", ""} {
+		if got := Detect(text, nil); got.Reliable(text, 0.8) {
+			t.Errorf("Detect(%q) = %+v, want unreliable", text, got)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// GroundingResult describes how well an answer is supported by the knowledge base.
+type GroundingResult struct {
+	Score              float64  // 0.0 (unsupported) to 1.0 (fully supported)
+	Sentences          int      // Sentences with enough content to check
+	Supported          int      // Sentences whose content words mostly appear in one snippet
+	UnsupportedNumbers []string // Numbers or dates not found in the knowledge base or question
+	Judged             bool     // The LLM judge contributed to Score
+}
+
+var (
+	sentenceBoundary  = regexp.MustCompile(`[.!?]+\s+|\n+`)
+	groundingNumber   = regexp.MustCompile(`\d+(?:[.,:/\-]\d+)*`)
+	redactionToken    = regexp.MustCompile(`\[[A-Z]+_\d+\]`)
+	groundingStopword = map[string]bool{}
+)
+
+func init() {
+	for _, w := range strings.Fields(`the and for are but not you your with this that have has had was were will would can could
+		our their they them from into about there here what when where which who how all any its it's also just than then
+		please thank thanks hello sure happy help let know more some been being does did such very
+		yang dan untuk dengan dari ini itu ada akan dapat bisa kami kamu anda saya tidak juga atau pada dalam sudah
+		jika karena oleh agar para terima kasih silakan mohon`) {
+		groundingStopword[w] = true
+	}
+}
+
+// supportThreshold is the share of a sentence's content words that must appear
+// in a single snippet for the sentence to count as supported.
+const supportThreshold = 0.5
+
+// numberPenalty is subtracted from the score for each unsupported number.
+const numberPenalty = 0.25
+
+// GroundingScorer checks that answers are supported by the retrieved knowledge
+// using lexical overlap and number consistency, optionally confirmed by an LLM judge.
+type GroundingScorer struct {
+	judge      Client
+	judgeModel string
+}
+
+// NewGroundingScorer creates a lexical grounding scorer.
+func NewGroundingScorer() *GroundingScorer {
+	return &GroundingScorer{}
+}
+
+// WithJudge asks client to rate answers the lexical check cannot fully confirm.
+// An empty model uses the client's default.
+func (g *GroundingScorer) WithJudge(client Client, model string) *GroundingScorer {
+	g.judge = client
+	g.judgeModel = model
+	return g
+}
+
+// Score rates answer against the knowledge base. Numbers that also appear in
+// the question (e.g. an order number) are not penalised.
+func (g *GroundingScorer) Score(ctx context.Context, answer, question string, knowledgeBase []string) GroundingResult {
+	answer = redactionToken.ReplaceAllString(answer, " ")
+
+	snippets := make([]map[string]bool, len(knowledgeBase))
+	for i, kb := range knowledgeBase {
+		snippets[i] = wordSet(contentWords(kb))
+	}
+
+	var result GroundingResult
+	for _, sentence := range sentenceBoundary.Split(answer, -1) {
+		words := contentWords(sentence)
+		if len(words) < 3 {
+			continue // Greetings and sign-offs carry no claims
+		}
+		result.Sentences++
+		for _, snippet := range snippets {
+			if overlap(words, snippet) >= supportThreshold {
+				result.Supported++
+				break
+			}
+		}
+	}
+
+	lexical := 1.0
+	if result.Sentences > 0 {
+		lexical = float64(result.Supported) / float64(result.Sentences)
+	}
+
+	known := map[string]bool{}
+	for _, text := range append([]string{question}, knowledgeBase...) {
+		for _, n := range groundingNumber.FindAllString(text, -1) {
+			known[canonicalNumber(n)] = true
+		}
+	}
+	for _, n := range groundingNumber.FindAllString(answer, -1) {
+		if !known[canonicalNumber(n)] {
+			result.UnsupportedNumbers = append(result.UnsupportedNumbers, n)
+		}
+	}
+
+	result.Score = lexical
+	if g.judge != nil && (lexical < 1 || len(result.UnsupportedNumbers) > 0) {
+		if judged, err := g.judgeScore(ctx, answer, knowledgeBase); err == nil {
+			// The judge understands paraphrases; lexical overlap does not
+			result.Score = judged
+			result.Judged = true
+		}
+	}
+	result.Score -= numberPenalty * float64(len(result.UnsupportedNumbers))
+	result.Score = math.Max(0, math.Min(1, result.Score))
+	return result
+}
+
+// BlendConfidence mixes a groundedness score into a confidence score.
+// weight 0 keeps the confidence; weight 1 replaces it.
+func BlendConfidence(confidence, groundedness, weight float64) float64 {
+	blended := (1-weight)*confidence + weight*groundedness
+	return math.Max(0, math.Min(1, blended))
+}
+
+const judgePrompt = `You check whether a customer support answer is supported by the knowledge base. Reply with exactly one word:
+SUPPORTED if every factual claim in the answer is stated in the knowledge base,
+PARTIAL if some claims are supported and others are not,
+UNSUPPORTED if the main claims are not in the knowledge base or contradict it.`
+
+func (g *GroundingScorer) judgeScore(ctx context.Context, answer string, knowledgeBase []string) (float64, error) {
+	resp, err := g.judge.GenerateAnswer(ctx, &Request{
+		Raw: true,
+		Messages: []Message{
+			{Role: "system", Content: judgePrompt},
+			{Role: "user", Content: Delimit("knowledge_base", strings.Join(knowledgeBase, "\n\n")) + "\n\n" + Delimit("answer", answer)},
+		},
+		Model:       g.judgeModel,
+		MaxTokens:   5,
+		Temperature: Temp(0),
+	})
+	if err != nil {
+		return 0, err
+	}
+	switch label := strings.ToUpper(strings.TrimSpace(resp.Content)); {
+	case strings.HasPrefix(label, "SUPPORTED"):
+		return 1, nil
+	case strings.HasPrefix(label, "PARTIAL"):
+		return 0.5, nil
+	case strings.HasPrefix(label, "UNSUPPORTED"):
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("unexpected judge label %q", resp.Content)
+	}
+}
+
+// contentWords lower-cases text and returns its words minus stopwords and very short tokens.
+func contentWords(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := make([]string, 0, len(fields))
+	for _, w := range fields {
+		if len([]rune(w)) < 3 || groundingStopword[w] {
+			continue
+		}
+		words = append(words, stem(w))
+	}
+	return words
+}
+
+// stem strips a few common English and Indonesian suffixes so "refunds"
+// matches "refund" and "pesanannya" matches "pesanan".
+func stem(w string) string {
+	for _, suffix := range []string{"nya", "ing", "ed", "es", "s"} {
+		if strings.HasSuffix(w, suffix) && len(w)-len(suffix) >= 4 {
+			return strings.TrimSuffix(w, suffix)
+		}
+	}
+	return w
+}
+
+func wordSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+func overlap(words []string, snippet map[string]bool) float64 {
+	if len(words) == 0 {
+		return 0
+	}
+	hits := 0
+	for _, w := range words {
+		if snippet[w] {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(words))
+}
+
+// canonicalNumber drops separators so "1,000" matches "1000" and "12/05" matches "12-05".
+func canonicalNumber(n string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, n)
+}
@@ -0,0 +1,100 @@
+package llm
+
+import (
+	"context"
+	"testing"
+)
+
+type labelClient struct {
+	label string
+	calls int
+}
+
+func (c *labelClient) GenerateAnswer(ctx context.Context, req *Request) (*Response, error) {
+	c.calls++
+	return &Response{Content: c.label}, nil
+}
+
+func TestGroundingScorer_Score(t *testing.T) {
+	kb := []string{
+		"Refunds are available within 30 days of delivery for unused items in their original packaging.",
+		"Standard shipping takes 3-5 business days within Indonesia.",
+	}
+	tests := []struct {
+		name     string
+		answer   string
+		question string
+		min, max float64
+	}{
+		{
+			name:   "supported",
+			answer: "Refunds are available for unused items within 30 days of delivery. Happy to help!",
+			min:    1, max: 1,
+		},
+		{
+			name:   "hallucinated policy",
+			answer: "You can exchange opened electronics at any partner store for store credit.",
+			min:    0, max: 0,
+		},
+		{
+			name:   "wrong number",
+			answer: "Refunds are available within 60 days of delivery for unused items.",
+			min:    0.7, max: 0.8,
+		},
+		{
+			name:     "number from question",
+			answer:   "Order 88231 ships within 3-5 business days within Indonesia.",
+			question: "When will order 88231 arrive?",
+			min:      1, max: 1,
+		},
+		{
+			name:   "half supported",
+			answer: "Standard shipping takes 3-5 business days. Express delivery arrives by drone overnight.",
+			min:    0.5, max: 0.5,
+		},
+	}
+
+	g := NewGroundingScorer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := g.Score(context.Background(), tt.answer, tt.question, kb)
+			if got.Score < tt.min || got.Score > tt.max {
+				t.Errorf("Score() = %.2f (%+v), want [%.2f, %.2f]", got.Score, got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestGroundingScorer_Judge(t *testing.T) {
+	kb := []string{"Refunds are available within 30 days of delivery."}
+	judge := &labelClient{label: "SUPPORTED"}
+	g := NewGroundingScorer().WithJudge(judge, "")
+
+	// Paraphrase the lexical check cannot confirm
+	got := g.Score(context.Background(), "You have a month after it arrives to get your money returned.", "", kb)
+	if !got.Judged || got.Score != 1 {
+		t.Errorf("Score() = %+v, want judged 1.0", got)
+	}
+
+	// Fully supported answers skip the judge
+	judge.calls = 0
+	g.Score(context.Background(), "Refunds are available within 30 days of delivery.", "", kb)
+	if judge.calls != 0 {
+		t.Errorf("judge called %d times for a supported answer", judge.calls)
+	}
+
+	// Unparseable verdicts keep the lexical score
+	judge.label = "It depends"
+	if got := g.Score(context.Background(), "Our stores accept exchanges on opened electronics.", "", kb); got.Judged {
+		t.Errorf("Score() = %+v, want lexical fallback", got)
+	}
+}
+
+func TestBlendConfidence(t *testing.T) {
+	if got := BlendConfidence(0.7, 1, 0.5); got != 0.85 {
+		t.Errorf("BlendConfidence() = %v, want 0.85", got)
+	}
+	if got := BlendConfidence(0.7, 0, 0); got != 0.7 {
+		t.Errorf("BlendConfidence() with weight 0 = %v, want 0.7", got)
+	}
+}
@@ -0,0 +1,81 @@
+package llm
+
+import (
+	"fmt"
+	"math"
+)
+
+// ConfidenceStrategy selects how token logprobs and the heuristic score are combined
+type ConfidenceStrategy string
+
+const (
+	// StrategyHeuristic ignores logprobs and keeps the phrase/length heuristics
+	StrategyHeuristic ConfidenceStrategy = "heuristic"
+	// StrategyMean starts from the geometric mean token probability instead of the fixed 0.7 base
+	StrategyMean ConfidenceStrategy = "mean"
+	// StrategyMin starts from the least likely token's probability, the most
+	// conservative choice. Over a multi-sentence answer some token is almost
+	// always unlikely, so the score nears 0 and most long answers fall back;
+	// it only suits short, single-sentence answers.
+	StrategyMin ConfidenceStrategy = "min"
+	// StrategyBlend averages the heuristic score with the geometric mean token probability
+	StrategyBlend ConfidenceStrategy = "blend"
+)
+
+// ParseConfidenceStrategy validates a strategy name; empty means StrategyHeuristic
+func ParseConfidenceStrategy(name string) (ConfidenceStrategy, error) {
+	switch s := ConfidenceStrategy(name); s {
+	case "":
+		return StrategyHeuristic, nil
+	case StrategyHeuristic, StrategyMean, StrategyMin, StrategyBlend:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unknown confidence strategy %q (want heuristic, mean, min or blend)", name)
+	}
+}
+
+// UsesLogprobs reports whether the strategy needs token logprobs from the provider
+func (s ConfidenceStrategy) UsesLogprobs() bool {
+	return s != StrategyHeuristic
+}
+
+// TokenProbabilities summarises token logprobs as the geometric mean and the
+// minimum token probability. ok is false when there are no logprobs.
+func TokenProbabilities(logprobs []float64) (mean, min float64, ok bool) {
+	if len(logprobs) == 0 {
+		return 0, 0, false
+	}
+	sum := 0.0
+	minLogprob := 0.0
+	for _, lp := range logprobs {
+		sum += lp
+		if lp < minLogprob {
+			minLogprob = lp
+		}
+	}
+	return math.Exp(sum / float64(len(logprobs))), math.Exp(minLogprob), true
+}
+
+// CalculateWithLogprobs combines token probabilities with the heuristic
+// penalties according to strategy. Responses without logprobs (e.g. from a
+// provider that does not return them) keep the heuristic score.
+func (cs *ConfidenceScorer) CalculateWithLogprobs(response *Response, knowledgeBase []string, strategy ConfidenceStrategy) float64 {
+	heuristic := cs.CalculateConfidence(response, knowledgeBase)
+	mean, min, ok := TokenProbabilities(response.TokenLogprobs)
+	if !ok {
+		return heuristic
+	}
+
+	var confidence float64
+	switch strategy {
+	case StrategyMean:
+		confidence = mean - cs.penalty(response, knowledgeBase)
+	case StrategyMin:
+		confidence = min - cs.penalty(response, knowledgeBase)
+	case StrategyBlend:
+		confidence = (heuristic + mean) / 2
+	default:
+		return heuristic
+	}
+	return math.Max(0.0, math.Min(1.0, confidence))
+}
@@ -0,0 +1,106 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTokenProbabilities(t *testing.T) {
+	mean, min, ok := TokenProbabilities([]float64{math.Log(0.9), math.Log(0.9), math.Log(0.5)})
+	if !ok {
+		t.Fatal("TokenProbabilities() ok = false, want true")
+	}
+	wantMean := math.Cbrt(0.9 * 0.9 * 0.5)
+	if math.Abs(mean-wantMean) > 1e-9 || math.Abs(min-0.5) > 1e-9 {
+		t.Errorf("TokenProbabilities() = %v, %v, want %v, 0.5", mean, min, wantMean)
+	}
+	if _, _, ok := TokenProbabilities(nil); ok {
+		t.Error("TokenProbabilities(nil) ok = true, want false")
+	}
+}
+
+func TestConfidenceScorer_CalculateWithLogprobs(t *testing.T) {
+	scorer := NewConfidenceScorer()
+	kb := []string{"Return policy: 30 days"}
+	confident := &Response{
+		Content:       "You can return items within 30 days of delivery.",
+		FinishReason:  "stop",
+		TokenLogprobs: []float64{math.Log(0.95), math.Log(0.95), math.Log(0.95), math.Log(0.4)},
+	}
+	mean, min, _ := TokenProbabilities(confident.TokenLogprobs)
+
+	tests := []struct {
+		name     string
+		response *Response
+		kb       []string
+		strategy ConfidenceStrategy
+		want     float64
+	}{
+		{name: "heuristic ignores logprobs", response: confident, kb: kb, strategy: StrategyHeuristic, want: 0.7},
+		{name: "mean", response: confident, kb: kb, strategy: StrategyMean, want: mean},
+		{name: "min", response: confident, kb: kb, strategy: StrategyMin, want: min},
+		{name: "blend", response: confident, kb: kb, strategy: StrategyBlend, want: (0.7 + mean) / 2},
+		{name: "mean keeps heuristic penalties", response: confident, kb: nil, strategy: StrategyMean, want: mean - 0.3},
+		{
+			name:     "no logprobs falls back to heuristic",
+			response: &Response{Content: confident.Content, FinishReason: "stop"},
+			kb:       kb,
+			strategy: StrategyMean,
+			want:     0.7,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scorer.CalculateWithLogprobs(tt.response, tt.kb, tt.strategy)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("CalculateWithLogprobs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConfidenceStrategy(t *testing.T) {
+	if s, err := ParseConfidenceStrategy(""); err != nil || s != StrategyHeuristic {
+		t.Errorf("ParseConfidenceStrategy(\"\") = %q, %v, want heuristic", s, err)
+	}
+	if _, err := ParseConfidenceStrategy("median"); err == nil {
+		t.Error("ParseConfidenceStrategy(\"median\") error = nil, want error")
+	}
+}
+
+func TestOpenAIClient_RequestsLogprobs(t *testing.T) {
+	var sent openAIRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model":"gpt-4o","choices":[{"message":{"role":"assistant","content":"Returns are accepted within 30 days."},"finish_reason":"stop",
+			"logprobs":{"content":[{"token":"Returns","logprob":-0.01},{"token":" are","logprob":-0.02},{"token":" accepted","logprob":-0.9}]}}],
+			"usage":{"total_tokens":42}}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(Config{BaseURL: server.URL, APIKey: "test", ConfidenceStrategy: StrategyMin})
+	resp, err := client.GenerateAnswer(context.Background(), &Request{
+		Messages:      []Message{{Role: "user", Content: "How long do I have to return an item?"}},
+		KnowledgeBase: []string{"Returns are accepted within 30 days."},
+	})
+	if err != nil {
+		t.Fatalf("GenerateAnswer() error = %v", err)
+	}
+	if !sent.Logprobs {
+		t.Error("request logprobs = false, want true")
+	}
+	if len(resp.TokenLogprobs) != 3 {
+		t.Fatalf("TokenLogprobs = %v, want 3 values", resp.TokenLogprobs)
+	}
+	if want := math.Exp(-0.9); math.Abs(resp.Confidence-want) > 1e-9 {
+		t.Errorf("Confidence = %v, want min token probability %v", resp.Confidence, want)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
 )
 
 // OpenAIClient implements the Client interface for OpenAI
@@ -16,10 +18,15 @@ type OpenAIClient struct {
 	httpClient  *http.Client
 	promptBuilder *PromptBuilder
 	confidenceScorer *ConfidenceScorer
+	strategy         ConfidenceStrategy
 }
 
 // NewOpenAIClient creates a new OpenAI client
 func NewOpenAIClient(config Config) *OpenAIClient {
+	strategy := config.ConfidenceStrategy
+	if strategy == "" {
+		strategy = StrategyHeuristic
+	}
 	timeout := time.Duration(config.Timeout) * time.Second
 	if timeout == 0 {
 		timeout = 30 * time.Second // Default timeout
@@ -32,6 +39,7 @@ func NewOpenAIClient(config Config) *OpenAIClient {
 		},
 		promptBuilder: NewPromptBuilder(),
 		confidenceScorer: NewConfidenceScorer(),
+		strategy:         strategy,
 	}
 }
 
@@ -40,12 +48,78 @@ type openAIRequest struct {
 	Model       string    `json:"model"`
 	Messages    []message `json:"messages"`
 	MaxTokens   int       `json:"max_tokens,omitempty"`
-	Temperature float64   `json:"temperature,omitempty"`
+	Temperature *float64  `json:"temperature,omitempty"`
+	Logprobs    bool      `json:"logprobs,omitempty"`
+
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+	Tools          []openAITool    `json:"tools,omitempty"`
+	ToolChoice     string          `json:"tool_choice,omitempty"`
+}
+
+type openAITool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description,omitempty"`
+		Parameters  json.RawMessage `json:"parameters,omitempty"`
+	} `json:"function"`
+}
+
+type openAIToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+func toOpenAITools(tools []Tool) []openAITool {
+	out := make([]openAITool, len(tools))
+	for i, t := range tools {
+		out[i].Type = "function"
+		out[i].Function.Name = t.Name
+		out[i].Function.Description = t.Description
+		out[i].Function.Parameters = t.Parameters
+	}
+	return out
+}
+
+func toOpenAIToolCalls(calls []ToolCall) []openAIToolCall {
+	out := make([]openAIToolCall, len(calls))
+	for i, call := range calls {
+		out[i].ID = call.ID
+		out[i].Type = "function"
+		out[i].Function.Name = call.Name
+		out[i].Function.Arguments = call.Arguments
+	}
+	return out
+}
+
+// responseFormat requests schema-constrained JSON output
+type responseFormat struct {
+	Type       string `json:"type"`
+	JSONSchema struct {
+		Name   string          `json:"name"`
+		Strict bool            `json:"strict"`
+		Schema json.RawMessage `json:"schema"`
+	} `json:"json_schema"`
+}
+
+func structuredResponseFormat() *responseFormat {
+	format := &responseFormat{Type: "json_schema"}
+	format.JSONSchema.Name = "support_answer"
+	format.JSONSchema.Strict = true
+	format.JSONSchema.Schema = StructuredSchema
+	return format
 }
 
 type message struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
+
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
 }
 
 // openAIResponse represents the OpenAI API response
@@ -58,6 +132,12 @@ type openAIResponse struct {
 		Index        int    `json:"index"`
 		Message      message `json:"message"`
 		FinishReason string `json:"finish_reason"`
+		Logprobs     *struct {
+			Content []struct {
+				Token   string  `json:"token"`
+				Logprob float64 `json:"logprob"`
+			} `json:"content"`
+		} `json:"logprobs,omitempty"`
 	} `json:"choices"`
 	Usage struct {
 		PromptTokens     int `json:"prompt_tokens"`
@@ -74,7 +154,10 @@ type openAIResponse struct {
 // GenerateAnswer generates an answer using OpenAI API
 func (c *OpenAIClient) GenerateAnswer(ctx context.Context, req *Request) (*Response, error) {
 	// Build messages using prompt builder
-	messages := c.promptBuilder.BuildMessages(req)
+	messages := req.Messages
+	if !req.Raw {
+		messages = c.promptBuilder.BuildMessages(req)
+	}
 
 	// Convert to OpenAI message format
 	openAIMessages := make([]message, len(messages))
@@ -103,11 +186,12 @@ func (c *OpenAIClient) GenerateAnswer(ctx context.Context, req *Request) (*Respo
 	}
 
 	temperature := req.Temperature
-	if temperature == 0 {
-		temperature = c.config.Temperature
-		if temperature == 0 {
-			temperature = 0.7
+	if temperature == nil {
+		fallback := c.config.Temperature
+		if fallback == 0 {
+			fallback = 0.7
 		}
+		temperature = &fallback
 	}
 
 	// Create request payload
@@ -116,8 +200,119 @@ func (c *OpenAIClient) GenerateAnswer(ctx context.Context, req *Request) (*Respo
 		Messages:    openAIMessages,
 		MaxTokens:   maxTokens,
 		Temperature: temperature,
+		// Token logprobs feed the confidence score; classifier calls don't need them
+		Logprobs: c.strategy.UsesLogprobs() && !req.Raw,
+	}
+	structured := req.Structured && !req.Raw
+	if structured {
+		payload.ResponseFormat = structuredResponseFormat()
+	}
+	if len(req.Tools) > 0 && req.ToolExecutor != nil && !req.Raw {
+		payload.Tools = toOpenAITools(req.Tools)
+	}
+
+	resp, err := c.complete(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload.Tools) > 0 {
+		if resp, err = c.runTools(ctx, &payload, resp, req); err != nil {
+			return nil, err
+		}
+	}
+	if structured {
+		c.parseStructured(ctx, payload, resp, len(req.KnowledgeBase))
+	}
+
+	// Calculate confidence score; tool results count as knowledge
+	knowledge := append(append([]string{}, req.KnowledgeBase...), toolResults(resp.ToolCalls)...)
+	resp.Confidence = c.confidenceScorer.CalculateWithLogprobs(resp, knowledge, c.strategy)
+
+	return resp, nil
+}
+
+// runTools executes the tool calls the model asks for and sends the results
+// back until it answers. The last allowed turn disables tools so the loop
+// always ends with an answer. payload keeps the conversation for later repairs.
+func (c *OpenAIClient) runTools(ctx context.Context, payload *openAIRequest, resp *Response, req *Request) (*Response, error) {
+	maxIterations := req.MaxToolIterations
+	if maxIterations <= 0 {
+		maxIterations = DefaultMaxToolIterations
 	}
 
+	var calls []ToolCall
+	tokens, promptTokens, completionTokens := resp.TokensUsed, resp.PromptTokens, resp.CompletionTokens
+	for iteration := 0; len(resp.ToolCalls) > 0 && iteration < maxIterations; iteration++ {
+		payload.Messages = append(payload.Messages, message{
+			Role:      "assistant",
+			Content:   resp.Content,
+			ToolCalls: toOpenAIToolCalls(resp.ToolCalls),
+		})
+		for _, call := range resp.ToolCalls {
+			done := runTool(ctx, req.ToolExecutor, call)
+			calls = append(calls, done)
+			payload.Messages = append(payload.Messages, message{Role: "tool", ToolCallID: call.ID, Content: done.Result})
+		}
+		if iteration+1 == maxIterations {
+			payload.ToolChoice = "none"
+		}
+
+		next, err := c.complete(ctx, *payload)
+		if err != nil {
+			return nil, err
+		}
+		tokens += next.TokensUsed
+		promptTokens += next.PromptTokens
+		completionTokens += next.CompletionTokens
+		resp = next
+	}
+	resp.TokensUsed = tokens
+	resp.PromptTokens, resp.CompletionTokens = promptTokens, completionTokens
+	resp.ToolCalls = calls
+	return resp, nil
+}
+
+// parseStructured validates a structured reply, sending invalid output back to
+// the model for repair. On success Content becomes the answer text.
+func (c *OpenAIClient) parseStructured(ctx context.Context, payload openAIRequest, resp *Response, sourceCount int) {
+	raw := resp.Content
+	answer, err := ParseStructured(raw, sourceCount)
+	for attempt := 0; err != nil && attempt < StructuredRepairAttempts; attempt++ {
+		repair := payload
+		if len(repair.Tools) > 0 {
+			repair.ToolChoice = "none"
+		}
+		repair.Messages = append(append([]message{}, payload.Messages...),
+			message{Role: "assistant", Content: raw},
+			message{Role: "user", Content: RepairPrompt(err)},
+		)
+		next, cerr := c.complete(ctx, repair)
+		if cerr != nil {
+			err = cerr
+			break
+		}
+		resp.TokensUsed += next.TokensUsed
+		resp.PromptTokens += next.PromptTokens
+		resp.CompletionTokens += next.CompletionTokens
+		resp.TokenLogprobs = next.TokenLogprobs
+		resp.FinishReason = next.FinishReason
+		raw = next.Content
+		answer, err = ParseStructured(raw, sourceCount)
+	}
+	if err != nil {
+		logger.Error("structured output invalid after repair", map[string]interface{}{
+			"error": err.Error(),
+			"model": resp.Model,
+		})
+		resp.Content = raw
+		return
+	}
+	resp.Structured = answer
+	resp.Content = answer.Answer
+}
+
+// complete sends one chat completion request with retries.
+func (c *OpenAIClient) complete(ctx context.Context, payload openAIRequest) (*Response, error) {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -183,14 +378,26 @@ func (c *OpenAIClient) GenerateAnswer(ctx context.Context, req *Request) (*Respo
 
 		choice := openAIResp.Choices[0]
 		resp = &Response{
-			Content:      choice.Message.Content,
-			TokensUsed:   openAIResp.Usage.TotalTokens,
-			Model:        openAIResp.Model,
-			FinishReason: choice.FinishReason,
+			Content:          choice.Message.Content,
+			TokensUsed:       openAIResp.Usage.TotalTokens,
+			PromptTokens:     openAIResp.Usage.PromptTokens,
+			CompletionTokens: openAIResp.Usage.CompletionTokens,
+			Model:            openAIResp.Model,
+			FinishReason:     choice.FinishReason,
+		}
+		for _, call := range choice.Message.ToolCalls {
+			resp.ToolCalls = append(resp.ToolCalls, ToolCall{
+				ID:        call.ID,
+				Name:      call.Function.Name,
+				Arguments: call.Function.Arguments,
+			})
+		}
+		if choice.Logprobs != nil {
+			resp.TokenLogprobs = make([]float64, len(choice.Logprobs.Content))
+			for i, token := range choice.Logprobs.Content {
+				resp.TokenLogprobs[i] = token.Logprob
+			}
 		}
-
-		// Calculate confidence score
-		resp.Confidence = c.confidenceScorer.CalculateConfidence(resp, req.KnowledgeBase)
 
 		return nil
 	}, retryConfig)
@@ -0,0 +1,51 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOpenAIClient_Temperature(t *testing.T) {
+	tests := []struct {
+		name        string
+		temperature *float64
+		want        float64
+	}{
+		{"unset uses client default", nil, 0.4},
+		{"explicit zero is sent", Temp(0), 0},
+		{"explicit value", Temp(1.2), 1.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent map[string]interface{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"model":"gpt-4o","choices":[{"message":{"role":"assistant","content":"ok"},"finish_reason":"stop"}],"usage":{"total_tokens":1}}`))
+			}))
+			defer server.Close()
+
+			client := NewOpenAIClient(Config{BaseURL: server.URL, APIKey: "test", Temperature: 0.4})
+			_, err := client.GenerateAnswer(context.Background(), &Request{
+				Messages:    []Message{{Role: "user", Content: "hi"}},
+				Temperature: tt.temperature,
+			})
+			if err != nil {
+				t.Fatalf("GenerateAnswer() error = %v", err)
+			}
+			got, ok := sent["temperature"].(float64)
+			if !ok {
+				t.Fatalf("request temperature missing: %v", sent["temperature"])
+			}
+			if got != tt.want {
+				t.Errorf("temperature = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// StructuredRepairAttempts is how many times an invalid structured reply is
+// sent back to the model for correction.
+const StructuredRepairAttempts = 1
+
+// ErrInvalidStructuredOutput is wrapped by ParseStructured errors.
+var ErrInvalidStructuredOutput = errors.New("invalid structured output")
+
+// StructuredAnswer is the JSON object the model returns in structured mode.
+type StructuredAnswer struct {
+	Answer         string   `json:"answer"`
+	UsedSourceIDs  []string `json:"used_source_ids"`
+	NeedsHuman     bool     `json:"needs_human"`
+	Intent         string   `json:"intent"`
+	SelfConfidence float64  `json:"self_confidence"`
+}
+
+// StructuredSchema is the JSON schema of StructuredAnswer, in the strict form
+// OpenAI's response_format accepts.
+var StructuredSchema = json.RawMessage(`{
+  "type": "object",
+  "properties": {
+    "answer": {"type": "string"},
+    "used_source_ids": {"type": "array", "items": {"type": "string"}},
+    "needs_human": {"type": "boolean"},
+    "intent": {"type": "string"},
+    "self_confidence": {"type": "number", "minimum": 0, "maximum": 1}
+  },
+  "required": ["answer", "used_source_ids", "needs_human", "intent", "self_confidence"],
+  "additionalProperties": false
+}`)
+
+// structuredInstruction tells providers without schema enforcement what to return.
+const structuredInstruction = `Reply with a single JSON object and nothing else, with these fields:
+- "answer": the reply to the customer
+- "used_source_ids": IDs of the knowledge base entries the answer relies on, such as "S1"; empty if none
+- "needs_human": true when the customer should be handed to a human agent
+- "intent": a short snake_case label for what the customer wants, such as "refund_request"
+- "self_confidence": how sure you are that the answer is correct and complete, from 0 to 1`
+
+// SourceID returns the ID of the i-th (0-based) knowledge base entry in structured prompts.
+func SourceID(i int) string {
+	return fmt.Sprintf("S%d", i+1)
+}
+
+// labelSources prefixes each knowledge base entry with its source ID.
+func labelSources(knowledgeBase []string) []string {
+	labelled := make([]string, len(knowledgeBase))
+	for i, kb := range knowledgeBase {
+		labelled[i] = "[" + SourceID(i) + "] " + kb
+	}
+	return labelled
+}
+
+// ParseStructured decodes and validates a structured reply. sourceCount is the
+// number of knowledge base entries the model was given; unknown source IDs
+// are rejected so the caller can trust them.
+func ParseStructured(content string, sourceCount int) (*StructuredAnswer, error) {
+	content = stripCodeFence(content)
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content), &fields); err != nil {
+		return nil, fmt.Errorf("%w: not a JSON object: %v", ErrInvalidStructuredOutput, err)
+	}
+	for _, name := range []string{"answer", "used_source_ids", "needs_human", "intent", "self_confidence"} {
+		if _, ok := fields[name]; !ok {
+			return nil, fmt.Errorf("%w: missing field %q", ErrInvalidStructuredOutput, name)
+		}
+	}
+
+	var answer StructuredAnswer
+	decoder := json.NewDecoder(bytes.NewReader([]byte(content)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&answer); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStructuredOutput, err)
+	}
+	if strings.TrimSpace(answer.Answer) == "" {
+		return nil, fmt.Errorf("%w: answer is empty", ErrInvalidStructuredOutput)
+	}
+	if answer.SelfConfidence < 0 || answer.SelfConfidence > 1 {
+		return nil, fmt.Errorf("%w: self_confidence %v is not between 0 and 1", ErrInvalidStructuredOutput, answer.SelfConfidence)
+	}
+	valid := make(map[string]bool, sourceCount)
+	for i := 0; i < sourceCount; i++ {
+		valid[SourceID(i)] = true
+	}
+	for _, id := range answer.UsedSourceIDs {
+		if !valid[id] {
+			return nil, fmt.Errorf("%w: unknown source id %q", ErrInvalidStructuredOutput, id)
+		}
+	}
+	if answer.UsedSourceIDs == nil {
+		answer.UsedSourceIDs = []string{}
+	}
+	return &answer, nil
+}
+
+// RepairPrompt asks the model to correct an invalid structured reply.
+func RepairPrompt(err error) string {
+	return "Your previous reply could not be used (" + err.Error() + "). " +
+		"Reply again with only the JSON object, with every field present and nothing else."
+}
+
+// stripCodeFence removes a Markdown code fence some models wrap JSON in.
+func stripCodeFence(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "```") {
+		return content
+	}
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimPrefix(content, "json")
+	content = strings.TrimSuffix(content, "```")
+	return strings.TrimSpace(content)
+}
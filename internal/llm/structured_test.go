@@ -0,0 +1,105 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseStructured(t *testing.T) {
+	valid := `{"answer":"Refunds take 5 days.","used_source_ids":["S2"],"needs_human":false,"intent":"refund_status","self_confidence":0.9}`
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "valid", content: valid},
+		{name: "code fence", content: "```json\n" + valid + "\n```"},
+		{name: "not json", content: "Refunds take 5 days.", wantErr: "not a JSON object"},
+		{name: "missing field", content: `{"answer":"Hi","used_source_ids":[],"needs_human":false,"intent":"greeting"}`, wantErr: "self_confidence"},
+		{name: "unknown field", content: strings.Replace(valid, `"intent"`, `"mood":"calm","intent"`, 1), wantErr: "mood"},
+		{name: "empty answer", content: strings.Replace(valid, "Refunds take 5 days.", " ", 1), wantErr: "answer is empty"},
+		{name: "confidence out of range", content: strings.Replace(valid, "0.9", "1.5", 1), wantErr: "self_confidence"},
+		{name: "unknown source", content: strings.Replace(valid, "S2", "S7", 1), wantErr: "unknown source id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStructured(tt.content, 2)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ParseStructured() error = %v", err)
+				}
+				if got.Answer != "Refunds take 5 days." || got.UsedSourceIDs[0] != "S2" || got.Intent != "refund_status" {
+					t.Errorf("ParseStructured() = %+v", got)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidStructuredOutput) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseStructured() error = %v, want mention of %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPromptBuilder_StructuredLabelsSources(t *testing.T) {
+	messages := NewPromptBuilder().BuildMessages(&Request{
+		Messages:      []Message{{Role: "user", Content: "Where is my order?"}},
+		KnowledgeBase: []string{"Orders ship in 2 days.", "Refunds take 5 days."},
+		Structured:    true,
+	})
+	if !strings.Contains(messages[0].Content, `"used_source_ids"`) {
+		t.Error("system message does not describe the JSON reply")
+	}
+	if !strings.Contains(messages[1].Content, "[S1] Orders ship") || !strings.Contains(messages[1].Content, "[S2] Refunds") {
+		t.Errorf("user message = %q, want labelled sources", messages[1].Content)
+	}
+}
+
+func TestOpenAIClient_StructuredRepair(t *testing.T) {
+	replies := []string{
+		`Sure! Refunds take 5 days.`,
+		`{"answer":"Refunds take 5 days.","used_source_ids":["S1"],"needs_human":false,"intent":"refund_status","self_confidence":0.8}`,
+	}
+	var requests []openAIRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		reply := replies[len(requests)]
+		requests = append(requests, req)
+		content, _ := json.Marshal(reply)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model":"gpt-4o","choices":[{"message":{"role":"assistant","content":` + string(content) + `},"finish_reason":"stop"}],"usage":{"total_tokens":10}}`))
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient(Config{BaseURL: server.URL, APIKey: "test", ConfidenceStrategy: StrategyHeuristic})
+	resp, err := client.GenerateAnswer(context.Background(), &Request{
+		Messages:      []Message{{Role: "user", Content: "How long do refunds take?"}},
+		KnowledgeBase: []string{"Refunds take 5 days."},
+		Structured:    true,
+	})
+	if err != nil {
+		t.Fatalf("GenerateAnswer() error = %v", err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("requests = %d, want original and one repair", len(requests))
+	}
+	if requests[0].ResponseFormat == nil || requests[0].ResponseFormat.Type != "json_schema" {
+		t.Errorf("response_format = %+v, want json_schema", requests[0].ResponseFormat)
+	}
+	repair := requests[1].Messages
+	if last := repair[len(repair)-1]; last.Role != "user" || !strings.Contains(last.Content, "could not be used") {
+		t.Errorf("repair message = %+v", last)
+	}
+	if resp.Structured == nil || resp.Content != "Refunds take 5 days." || resp.TokensUsed != 20 {
+		t.Errorf("GenerateAnswer() = %+v, want parsed answer with both calls' tokens", resp)
+	}
+}
@@ -0,0 +1,100 @@
+package llm
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// DefaultUserTemplate renders the knowledge base and question the same way as
+// the built-in prompt. It is used when a template only customises the system message.
+const DefaultUserTemplate = `Knowledge Base:
+{{delimit "knowledge_base" (join .Sources "\n\n")}}
+
+Customer Question:
+{{delimit "customer_question" .Question}}`
+
+// PromptData is the set of variables available to prompt templates.
+type PromptData struct {
+	BrandName    string
+	Language     string // Language code or BCP-47 tag, e.g. "id" or "id-ID"
+	LanguageName string // Human-readable language, e.g. "Indonesian"
+	LanguageRule string // Language and number/price formatting instruction for the locale
+	CustomerName string
+	Question     string
+	Sources      []string          // Retrieved knowledge snippets
+	Vars         map[string]string // Tenant-defined variables (tone, sign-off, forbidden topics, ...)
+}
+
+// templateFuncs are available to every prompt template. Use delimit around
+// customer-controlled values so they cannot pose as instructions.
+var templateFuncs = template.FuncMap{
+	"delimit": Delimit,
+	"join":    strings.Join,
+}
+
+// PromptTemplate is a compiled pair of Go text/templates for the system and
+// user messages. Templates control the prompt: the builder does not append
+// its own language or personalisation instructions, only the safety guidelines
+// and, in structured mode, the reply format.
+type PromptTemplate struct {
+	system *template.Template
+	user   *template.Template
+}
+
+// ParsePromptTemplate compiles the given templates. An empty user template
+// falls back to DefaultUserTemplate. Templates are test-rendered with sample
+// data so missing fields fail here rather than at request time.
+func ParsePromptTemplate(system, user string) (*PromptTemplate, error) {
+	if system == "" {
+		return nil, fmt.Errorf("system template is required")
+	}
+	if user == "" {
+		user = DefaultUserTemplate
+	}
+
+	sysTmpl, err := template.New("system").Funcs(templateFuncs).Option("missingkey=zero").Parse(system)
+	if err != nil {
+		return nil, fmt.Errorf("invalid system template: %w", err)
+	}
+	userTmpl, err := template.New("user").Funcs(templateFuncs).Option("missingkey=zero").Parse(user)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user template: %w", err)
+	}
+
+	pt := &PromptTemplate{system: sysTmpl, user: userTmpl}
+	sample := PromptData{
+		BrandName:    "Brand",
+		Language:     "en",
+		LanguageName: "English",
+		LanguageRule: "Please respond in English.",
+		CustomerName: "Customer",
+		Question:     "Question?",
+		Sources:      []string{"Source"},
+		Vars:         map[string]string{},
+	}
+	if _, err := pt.Render(sample); err != nil {
+		return nil, err
+	}
+	return pt, nil
+}
+
+// Render executes both templates and returns the system and user messages.
+func (pt *PromptTemplate) Render(data PromptData) ([]Message, error) {
+	if data.Vars == nil {
+		data.Vars = map[string]string{}
+	}
+
+	var sys, user bytes.Buffer
+	if err := pt.system.Execute(&sys, data); err != nil {
+		return nil, fmt.Errorf("failed to render system template: %w", err)
+	}
+	if err := pt.user.Execute(&user, data); err != nil {
+		return nil, fmt.Errorf("failed to render user template: %w", err)
+	}
+	return []Message{
+		{Role: "system", Content: sys.String()},
+		{Role: "user", Content: user.String()},
+	}, nil
+}
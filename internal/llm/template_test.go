@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPromptTemplate_Render(t *testing.T) {
+	pt, err := ParsePromptTemplate(
+		"You are {{.BrandName}} support. Tone: {{.Vars.tone}}. Reply in {{.LanguageName}}.",
+		"",
+	)
+	if err != nil {
+		t.Fatalf("ParsePromptTemplate() error = %v", err)
+	}
+
+	msgs, err := pt.Render(PromptData{
+		BrandName:    "Acme",
+		LanguageName: "Indonesian",
+		Question:     "Where is my order?",
+		Sources:      []string{"Orders ship in 2 days.", "Refunds take 5 days."},
+		Vars:         map[string]string{"tone": "friendly"},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(msgs))
+	}
+	if want := "You are Acme support. Tone: friendly. Reply in Indonesian."; msgs[0].Content != want {
+		t.Errorf("system = %q, want %q", msgs[0].Content, want)
+	}
+	for _, want := range []string{"<knowledge_base>\nOrders ship in 2 days.\n\nRefunds take 5 days.\n</knowledge_base>", "<customer_question>\nWhere is my order?\n</customer_question>"} {
+		if !strings.Contains(msgs[1].Content, want) {
+			t.Errorf("user message missing %q: %q", want, msgs[1].Content)
+		}
+	}
+}
+
+func TestPromptTemplate_MissingVarRendersEmpty(t *testing.T) {
+	pt, err := ParsePromptTemplate("Sign off with {{.Vars.signoff}}.", "{{.Question}}")
+	if err != nil {
+		t.Fatalf("ParsePromptTemplate() error = %v", err)
+	}
+	msgs, err := pt.Render(PromptData{Question: "Hi"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if msgs[0].Content != "Sign off with ." {
+		t.Errorf("system = %q", msgs[0].Content)
+	}
+}
+
+func TestParsePromptTemplate_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		system string
+		user   string
+	}{
+		{name: "empty system", system: ""},
+		{name: "syntax error", system: "Hello {{.BrandName"},
+		{name: "unknown field", system: "Hello {{.Unknown}}"},
+		{name: "bad user template", system: "ok", user: "{{range}}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePromptTemplate(tt.system, tt.user); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestPromptBuilder_UsesTemplate(t *testing.T) {
+	pt, err := ParsePromptTemplate("{{.BrandName}} bot", "Q: {{.Question}}")
+	if err != nil {
+		t.Fatalf("ParsePromptTemplate() error = %v", err)
+	}
+	msgs := NewPromptBuilder().BuildMessages(&Request{
+		Messages:  []Message{{Role: "user", Content: "Hello"}},
+		Language:  "en",
+		BrandName: "Acme",
+		Template:  pt,
+	})
+	if len(msgs) != 2 || msgs[0].Content != "Acme bot\n\n"+safetyGuidelines || msgs[1].Content != "Q: Hello" {
+		t.Errorf("unexpected messages: %+v", msgs)
+	}
+}
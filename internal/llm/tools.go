@@ -0,0 +1,56 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// DefaultMaxToolIterations bounds the model/tool round trips for one answer.
+const DefaultMaxToolIterations = 3
+
+// Tool is a function the model may call.
+type Tool struct {
+	Name        string
+	Description string
+	Parameters  json.RawMessage // JSON schema of the arguments object
+}
+
+// ToolCall is one function call requested by the model and its outcome.
+type ToolCall struct {
+	ID        string
+	Name      string
+	Arguments string // JSON object as produced by the model
+	Result    string // Sent back to the model; a JSON error object when the call failed
+	Err       error
+}
+
+// ToolExecutor runs tool calls for a request.
+type ToolExecutor interface {
+	Execute(ctx context.Context, call ToolCall) (string, error)
+}
+
+// runTool executes a call, turning failures into a result the model can read
+// so it can apologise or ask the customer for corrected details.
+func runTool(ctx context.Context, executor ToolExecutor, call ToolCall) ToolCall {
+	result, err := executor.Execute(ctx, call)
+	if err != nil {
+		msg, _ := json.Marshal(map[string]string{"error": err.Error()})
+		call.Result = string(msg)
+		call.Err = err
+		return call
+	}
+	call.Result = result
+	return call
+}
+
+// toolResults returns the successful tool outputs, which count as knowledge
+// when scoring confidence.
+func toolResults(calls []ToolCall) []string {
+	var results []string
+	for _, call := range calls {
+		if call.Err == nil {
+			results = append(results, call.Result)
+		}
+	}
+	return results
+}
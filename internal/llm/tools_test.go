@@ -0,0 +1,106 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type stubExecutor struct {
+	calls []ToolCall
+	err   error
+}
+
+func (s *stubExecutor) Execute(_ context.Context, call ToolCall) (string, error) {
+	s.calls = append(s.calls, call)
+	if s.err != nil {
+		return "", s.err
+	}
+	return `{"status":"shipped"}`, nil
+}
+
+// toolServer asks for a tool call on the first toolTurns requests, unless
+// tools are disabled, then answers.
+func toolServer(t *testing.T, toolTurns int, requests *[]openAIRequest) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		*requests = append(*requests, req)
+		w.Header().Set("Content-Type", "application/json")
+		if len(*requests) <= toolTurns && req.ToolChoice != "none" {
+			w.Write([]byte(`{"model":"gpt-4o","choices":[{"message":{"role":"assistant","content":"","tool_calls":[
+				{"id":"call_1","type":"function","function":{"name":"order_status","arguments":"{\"order_id\":\"123\"}"}}]},
+				"finish_reason":"tool_calls"}],"usage":{"total_tokens":10}}`))
+			return
+		}
+		w.Write([]byte(`{"model":"gpt-4o","choices":[{"message":{"role":"assistant","content":"Your order 123 has shipped and is on its way."},
+			"finish_reason":"stop"}],"usage":{"total_tokens":10}}`))
+	}))
+}
+
+func TestOpenAIClient_ToolLoop(t *testing.T) {
+	var requests []openAIRequest
+	server := toolServer(t, 1, &requests)
+	defer server.Close()
+
+	executor := &stubExecutor{}
+	client := NewOpenAIClient(Config{BaseURL: server.URL, APIKey: "test", ConfidenceStrategy: StrategyHeuristic})
+	resp, err := client.GenerateAnswer(context.Background(), &Request{
+		Messages:     []Message{{Role: "user", Content: "Where is my order #123?"}},
+		Tools:        []Tool{{Name: "order_status", Parameters: json.RawMessage(`{"type":"object"}`)}},
+		ToolExecutor: executor,
+	})
+	if err != nil {
+		t.Fatalf("GenerateAnswer() error = %v", err)
+	}
+	if len(requests) != 2 || len(requests[0].Tools) != 1 || requests[0].Tools[0].Function.Name != "order_status" {
+		t.Fatalf("requests = %+v", requests)
+	}
+	followUp := requests[1].Messages
+	toolMsg := followUp[len(followUp)-1]
+	if toolMsg.Role != "tool" || toolMsg.ToolCallID != "call_1" || toolMsg.Content != `{"status":"shipped"}` {
+		t.Errorf("tool message = %+v", toolMsg)
+	}
+	if assistant := followUp[len(followUp)-2]; len(assistant.ToolCalls) != 1 {
+		t.Errorf("assistant message = %+v, want the tool call echoed", assistant)
+	}
+	if len(executor.calls) != 1 || executor.calls[0].Arguments != `{"order_id":"123"}` {
+		t.Errorf("executor calls = %+v", executor.calls)
+	}
+	if resp.Content != "Your order 123 has shipped and is on its way." || resp.TokensUsed != 20 || len(resp.ToolCalls) != 1 {
+		t.Errorf("GenerateAnswer() = %+v", resp)
+	}
+	// The tool result counts as knowledge, so no missing-knowledge penalty
+	if resp.Confidence != 0.7 {
+		t.Errorf("Confidence = %v, want 0.7", resp.Confidence)
+	}
+}
+
+func TestOpenAIClient_ToolLoopMaxIterations(t *testing.T) {
+	var requests []openAIRequest
+	server := toolServer(t, 10, &requests)
+	defer server.Close()
+
+	executor := &stubExecutor{err: errors.New("backend unavailable")}
+	client := NewOpenAIClient(Config{BaseURL: server.URL, APIKey: "test"})
+	resp, err := client.GenerateAnswer(context.Background(), &Request{
+		Messages:          []Message{{Role: "user", Content: "Where is my order #123?"}},
+		Tools:             []Tool{{Name: "order_status"}},
+		ToolExecutor:      executor,
+		MaxToolIterations: 2,
+	})
+	if err != nil {
+		t.Fatalf("GenerateAnswer() error = %v", err)
+	}
+	if len(requests) != 3 || requests[2].ToolChoice != "none" {
+		t.Errorf("requests = %d, last tool_choice = %q, want 3 with tools disabled last", len(requests), requests[len(requests)-1].ToolChoice)
+	}
+	if len(resp.ToolCalls) != 2 || resp.ToolCalls[0].Err == nil || resp.ToolCalls[0].Result != `{"error":"backend unavailable"}` {
+		t.Errorf("ToolCalls = %+v, want failed calls reported to the model", resp.ToolCalls)
+	}
+}
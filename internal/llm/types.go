@@ -6,26 +6,66 @@ import "context"
 type Request struct {
 	Messages      []Message
 	MaxTokens     int
-	Temperature   float64
+	Temperature   *float64 // nil uses the client default; 0 is sent as-is
 	Model         string
+	SystemPrompt  string   // Overrides the built-in support prompt when set
 	KnowledgeBase []string // Retrieved knowledge documents for RAG
-	Language      string   // Language code (e.g., "en", "es")
+	Language      string   // Language code or BCP-47 tag (e.g., "en", "pt-BR")
 	TenantID      string   // Multi-tenant support
+	CustomerID    string   // Authenticated end-user, if known
+	CustomerName  string   // Display name used to personalise answers
+
+	// Raw sends Messages as-is, bypassing the support prompt (e.g. for classifiers)
+	Raw bool
+
+	// Structured asks for a StructuredAnswer JSON object instead of plain text
+	Structured bool
+
+	// Tools the model may call; calls are run by ToolExecutor between model
+	// turns, at most MaxToolIterations times (0 uses DefaultMaxToolIterations)
+	Tools             []Tool
+	ToolExecutor      ToolExecutor
+	MaxToolIterations int
+
+	// Tenant prompt template; when set it replaces the built-in prompt
+	Template   *PromptTemplate
+	BrandName  string
+	PromptVars map[string]string
+}
+
+// Temp returns a pointer to t for Request.Temperature.
+func Temp(t float64) *float64 {
+	return &t
 }
 
 // Message represents a single message in the conversation
 type Message struct {
-	Role    string // "system", "user", "assistant"
-	Content string
+	Role    string `json:"role"` // "system", "user", "assistant"
+	Content string `json:"content"`
 }
 
 // Response represents the LLM response
 type Response struct {
-	Content        string  // The generated answer
-	Confidence     float64 // Confidence score (0.0 to 1.0)
-	TokensUsed     int     // Number of tokens consumed
-	Model          string  // Model used
-	FinishReason   string  // Reason for completion (e.g., "stop", "length")
+	Content      string  // The generated answer
+	Confidence   float64 // Confidence score (0.0 to 1.0)
+	TokensUsed   int     // Number of tokens consumed
+	Model        string  // Model used
+	FinishReason string  // Reason for completion (e.g., "stop", "length")
+
+	// PromptTokens and CompletionTokens split TokensUsed into input and output,
+	// which providers price differently
+	PromptTokens     int
+	CompletionTokens int
+
+	// TokenLogprobs holds per-token log probabilities when the provider returns them
+	TokenLogprobs []float64
+
+	// Structured is the parsed reply in structured mode; nil when the model
+	// did not return valid JSON even after repair. Content then holds its raw output.
+	Structured *StructuredAnswer
+
+	// ToolCalls made while producing the answer, with their results
+	ToolCalls []ToolCall
 }
 
 // Client defines the interface for LLM clients
@@ -36,15 +76,16 @@ type Client interface {
 
 // Config holds LLM client configuration
 type Config struct {
-	Provider      string  // "openai", "anthropic", etc.
-	APIKey        string
-	BaseURL       string  // Optional, for custom endpoints
-	DefaultModel  string
-	MaxTokens     int
-	Temperature   float64
-	Timeout       int     // Timeout in seconds
-	MaxRetries    int     // Maximum number of retries
-	RetryDelay    int     // Initial retry delay in milliseconds
-}
-
+	Provider     string // "openai", "anthropic", etc.
+	APIKey       string
+	BaseURL      string // Optional, for custom endpoints
+	DefaultModel string
+	MaxTokens    int
+	Temperature  float64
+	Timeout      int // Timeout in seconds
+	MaxRetries   int // Maximum number of retries
+	RetryDelay   int // Initial retry delay in milliseconds
 
+	// ConfidenceStrategy combines token logprobs with the heuristic score
+	ConfidenceStrategy ConfidenceStrategy
+}
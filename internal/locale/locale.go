@@ -0,0 +1,217 @@
+// Package locale describes the languages and regions the service talks to
+// customers in: names for prompts and formatting rules.
+package locale
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Locale is a BCP-47 tag with the resources used for it.
+type Locale struct {
+	Tag        string // Canonical BCP-47 tag, e.g. "id-ID"
+	Language   string // ISO 639-1 code, e.g. "id"
+	Name       string // English name used in prompts, e.g. "Indonesian"
+	NativeName string // e.g. "Bahasa Indonesia"
+	Format     Format
+}
+
+// Format holds how numbers, prices and dates are written in a locale.
+type Format struct {
+	DecimalSeparator   string
+	ThousandsSeparator string
+	CurrencySymbol     string
+	CurrencySuffix     bool // Symbol follows the amount, e.g. "10,00 €"
+	CurrencyDecimals   int
+	DateLayout         string // Go time layout, e.g. "02/01/2006"
+}
+
+var locales = map[string]Locale{}
+
+// defaults maps a bare language to the locale used for it.
+var defaults = map[string]string{}
+
+func register(l Locale, isDefault bool) {
+	locales[l.Tag] = l
+	if isDefault {
+		defaults[l.Language] = l.Tag
+	}
+}
+
+func init() {
+	dotComma := Format{DecimalSeparator: ".", ThousandsSeparator: ",", CurrencyDecimals: 2}
+	commaDot := Format{DecimalSeparator: ",", ThousandsSeparator: ".", CurrencyDecimals: 2}
+
+	us := dotComma
+	us.CurrencySymbol, us.DateLayout = "$", "01/02/2006"
+	register(Locale{Tag: "en-US", Language: "en", Name: "English", NativeName: "English", Format: us}, true)
+	gb := dotComma
+	gb.CurrencySymbol, gb.DateLayout = "£", "02/01/2006"
+	register(Locale{Tag: "en-GB", Language: "en", Name: "English", NativeName: "English", Format: gb}, false)
+
+	idr := commaDot
+	idr.CurrencySymbol, idr.CurrencyDecimals, idr.DateLayout = "Rp", 0, "02/01/2006"
+	register(Locale{Tag: "id-ID", Language: "id", Name: "Indonesian", NativeName: "Bahasa Indonesia", Format: idr}, true)
+
+	myr := dotComma
+	myr.CurrencySymbol, myr.DateLayout = "RM", "02/01/2006"
+	register(Locale{Tag: "ms-MY", Language: "ms", Name: "Malay", NativeName: "Bahasa Melayu", Format: myr}, true)
+
+	thb := dotComma
+	thb.CurrencySymbol, thb.DateLayout = "฿", "02/01/2006"
+	register(Locale{Tag: "th-TH", Language: "th", Name: "Thai", NativeName: "ไทย", Format: thb}, true)
+
+	vnd := commaDot
+	vnd.CurrencySymbol, vnd.CurrencySuffix, vnd.CurrencyDecimals, vnd.DateLayout = "₫", true, 0, "02/01/2006"
+	register(Locale{Tag: "vi-VN", Language: "vi", Name: "Vietnamese", NativeName: "Tiếng Việt", Format: vnd}, true)
+
+	eur := commaDot
+	eur.CurrencySymbol, eur.CurrencySuffix, eur.DateLayout = "€", true, "02/01/2006"
+	register(Locale{Tag: "es-ES", Language: "es", Name: "Spanish", NativeName: "Español", Format: eur}, true)
+	fr := eur
+	fr.ThousandsSeparator = " "
+	register(Locale{Tag: "fr-FR", Language: "fr", Name: "French", NativeName: "Français", Format: fr}, true)
+	de := eur
+	de.DateLayout = "02.01.2006"
+	register(Locale{Tag: "de-DE", Language: "de", Name: "German", NativeName: "Deutsch", Format: de}, true)
+	register(Locale{Tag: "it-IT", Language: "it", Name: "Italian", NativeName: "Italiano", Format: eur}, true)
+
+	brl := commaDot
+	brl.CurrencySymbol, brl.DateLayout = "R$", "02/01/2006"
+	register(Locale{Tag: "pt-BR", Language: "pt", Name: "Portuguese", NativeName: "Português (Brasil)", Format: brl}, true)
+	pt := eur
+	pt.ThousandsSeparator = " "
+	register(Locale{Tag: "pt-PT", Language: "pt", Name: "Portuguese", NativeName: "Português", Format: pt}, false)
+
+	jpy := dotComma
+	jpy.CurrencySymbol, jpy.CurrencyDecimals, jpy.DateLayout = "¥", 0, "2006/01/02"
+	register(Locale{Tag: "ja-JP", Language: "ja", Name: "Japanese", NativeName: "日本語", Format: jpy}, true)
+	krw := dotComma
+	krw.CurrencySymbol, krw.CurrencyDecimals, krw.DateLayout = "₩", 0, "2006.01.02"
+	register(Locale{Tag: "ko-KR", Language: "ko", Name: "Korean", NativeName: "한국어", Format: krw}, true)
+	cny := dotComma
+	cny.CurrencySymbol, cny.DateLayout = "¥", "2006/01/02"
+	register(Locale{Tag: "zh-CN", Language: "zh", Name: "Chinese", NativeName: "中文", Format: cny}, true)
+	sar := dotComma
+	sar.CurrencySymbol, sar.CurrencySuffix, sar.DateLayout = "ر.س", true, "02/01/2006"
+	register(Locale{Tag: "ar-SA", Language: "ar", Name: "Arabic", NativeName: "العربية", Format: sar}, true)
+	inr := dotComma
+	inr.CurrencySymbol, inr.DateLayout = "₹", "02/01/2006"
+	register(Locale{Tag: "hi-IN", Language: "hi", Name: "Hindi", NativeName: "हिन्दी", Format: inr}, true)
+	rub := commaDot
+	rub.ThousandsSeparator, rub.CurrencySymbol, rub.CurrencySuffix, rub.DateLayout = " ", "₽", true, "02.01.2006"
+	register(Locale{Tag: "ru-RU", Language: "ru", Name: "Russian", NativeName: "Русский", Format: rub}, true)
+}
+
+// Canonical normalises a tag's case and separators: "id_id" becomes "id-ID".
+func Canonical(tag string) string {
+	parts := strings.FieldsFunc(strings.TrimSpace(tag), func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 {
+		return ""
+	}
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		} else if len(parts[i]) == 4 {
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// BaseLanguage returns the language subtag: "pt-BR" becomes "pt".
+func BaseLanguage(tag string) string {
+	tag = Canonical(tag)
+	if i := strings.IndexByte(tag, '-'); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+// Lookup finds the locale for a tag. Unknown regions fall back to the
+// language's default locale, so "en-AU" resolves to en-US.
+func Lookup(tag string) (Locale, bool) {
+	tag = Canonical(tag)
+	if l, ok := locales[tag]; ok {
+		return l, true
+	}
+	if def, ok := defaults[BaseLanguage(tag)]; ok {
+		return locales[def], true
+	}
+	return Locale{}, false
+}
+
+// Name returns the English name of a tag's language, or the tag itself.
+func Name(tag string) string {
+	if l, ok := Lookup(tag); ok {
+		return l.Name
+	}
+	return tag
+}
+
+// Tags lists every registered locale, sorted.
+func Tags() []string {
+	out := make([]string, 0, len(locales))
+	for t := range locales {
+		out = append(out, t)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// FormatNumber writes n with the locale's separators and the given decimals.
+func (l Locale) FormatNumber(n float64, decimals int) string {
+	negative := n < 0
+	s := fmt.Sprintf("%.*f", decimals, math.Abs(n))
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	var b strings.Builder
+	if negative {
+		b.WriteByte('-')
+	}
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(l.Format.ThousandsSeparator)
+		}
+		b.WriteRune(r)
+	}
+	if frac != "" {
+		b.WriteString(l.Format.DecimalSeparator)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+// FormatCurrency writes an amount in the locale's currency.
+func (l Locale) FormatCurrency(amount float64) string {
+	n := l.FormatNumber(amount, l.Format.CurrencyDecimals)
+	if l.Format.CurrencySuffix {
+		return n + " " + l.Format.CurrencySymbol
+	}
+	return l.Format.CurrencySymbol + " " + n
+}
+
+// FormatDate writes a date with the locale's layout.
+func (l Locale) FormatDate(t time.Time) string {
+	layout := l.Format.DateLayout
+	if layout == "" {
+		layout = "2006-01-02"
+	}
+	return t.Format(layout)
+}
+
+// exampleDate is the date used to show a locale's layout in prompts.
+var exampleDate = time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// Instruction tells the model which language to answer in and how to write
+// numbers, prices and dates.
+func (l Locale) Instruction() string {
+	return fmt.Sprintf("Please respond in %s. Write numbers like %s and prices like %s. Write dates like %s.",
+		l.Name, l.FormatNumber(1234.5, 1), l.FormatCurrency(150000), l.FormatDate(exampleDate))
+}
@@ -0,0 +1,75 @@
+package locale
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"id", "id-ID"},
+		{"id_id", "id-ID"},
+		{"pt-BR", "pt-BR"},
+		{"pt-pt", "pt-PT"},
+		{"pt", "pt-BR"},
+		{"en-AU", "en-US"},
+	}
+	for _, tt := range tests {
+		l, ok := Lookup(tt.tag)
+		if !ok || l.Tag != tt.want {
+			t.Errorf("Lookup(%q) = %q, %v, want %q", tt.tag, l.Tag, ok, tt.want)
+		}
+	}
+	if _, ok := Lookup("xx"); ok {
+		t.Error("Lookup(xx) ok = true, want false")
+	}
+}
+
+func TestName(t *testing.T) {
+	if got := Name("id"); got != "Indonesian" {
+		t.Errorf("Name(id) = %q, want Indonesian", got)
+	}
+	if got := Name("xx"); got != "xx" {
+		t.Errorf("Name(xx) = %q, want the code itself", got)
+	}
+}
+
+func TestBaseLanguage(t *testing.T) {
+	if got := BaseLanguage("PT_br"); got != "pt" {
+		t.Errorf("BaseLanguage(PT_br) = %q, want pt", got)
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	id, _ := Lookup("id-ID")
+	if got := id.FormatNumber(1234567.5, 2); got != "1.234.567,50" {
+		t.Errorf("FormatNumber() = %q", got)
+	}
+	if got := id.FormatCurrency(150000); got != "Rp 150.000" {
+		t.Errorf("FormatCurrency() = %q", got)
+	}
+	de, _ := Lookup("de")
+	if got := de.FormatCurrency(-9.99); got != "-9,99 €" {
+		t.Errorf("FormatCurrency() = %q", got)
+	}
+	us, _ := Lookup("en-US")
+	if got := us.FormatDate(time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC)); got != "03/04/2025" {
+		t.Errorf("FormatDate() = %q", got)
+	}
+}
+
+func TestEveryLocaleIsComplete(t *testing.T) {
+	for _, tag := range Tags() {
+		l, _ := Lookup(tag)
+		if l.Name == "" || l.Language == "" || l.Format.DecimalSeparator == "" || l.Format.CurrencySymbol == "" || l.Format.DateLayout == "" {
+			t.Errorf("locale %s is incomplete: %+v", tag, l)
+		}
+		if !strings.HasPrefix(l.Instruction(), "Please respond in "+l.Name+".") {
+			t.Errorf("%s Instruction() = %q", tag, l.Instruction())
+		}
+	}
+}
@@ -3,8 +3,22 @@ package logger
 import (
 	"fmt"
 	"log"
+	"sync"
 )
 
+var (
+	redactMu sync.RWMutex
+	redact   func(string) string
+)
+
+// SetRedactor installs a function applied to every logged field value, e.g.
+// to strip personal data from errors and free text. nil disables redaction.
+func SetRedactor(fn func(string) string) {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+	redact = fn
+}
+
 func Info(msg string, fields map[string]interface{}) {
 	log.Println(format("INFO", msg, fields))
 }
@@ -13,6 +27,12 @@ func Error(msg string, fields map[string]interface{}) {
 	log.Println(format("ERROR", msg, fields))
 }
 
+// Audit records security-relevant events (who did what, for which tenant).
+// Callers must not include raw customer content in fields.
+func Audit(msg string, fields map[string]interface{}) {
+	log.Println(format("AUDIT", msg, fields))
+}
+
 func format(level, msg string, fields map[string]interface{}) string {
 	out := "level=" + level + " msg=\"" + msg + "\""
 	for k, v := range fields {
@@ -26,5 +46,12 @@ func toString(v interface{}) string {
 }
 
 func logValue(v interface{}) string {
-	return fmt.Sprintf("%v", v)
+	s := fmt.Sprintf("%v", v)
+	redactMu.RLock()
+	fn := redact
+	redactMu.RUnlock()
+	if fn != nil {
+		s = fn(s)
+	}
+	return s
 }
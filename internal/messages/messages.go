@@ -0,0 +1,173 @@
+// Package messages is the catalog of customer-facing texts: fallback answers
+// and error messages per locale, with tenant overrides and placeholders.
+package messages
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/locale"
+)
+
+// Message keys.
+const (
+	Fallback            = "fallback"
+	RateLimited         = "rate_limited"
+	BudgetExceeded      = "budget_exceeded"
+	RejectedInput       = "rejected_input"
+	UnsupportedLanguage = "unsupported_language"
+	InternalError       = "internal_error"
+)
+
+// Keys lists every message key.
+var Keys = []string{Fallback, RateLimited, BudgetExceeded, RejectedInput, UnsupportedLanguage, InternalError}
+
+// DefaultFallback is the built-in English fallback answer.
+const DefaultFallback = "We are unable to confidently answer your question. Please contact customer support."
+
+// builtin holds the default texts by key, then locale tag or language.
+var builtin = map[string]map[string]string{
+	Fallback: {
+		"en":    DefaultFallback,
+		"id":    "Kami belum dapat menjawab pertanyaan Anda dengan yakin. Silakan hubungi layanan pelanggan.",
+		"ms":    "Kami tidak dapat menjawab soalan anda dengan yakin. Sila hubungi khidmat pelanggan.",
+		"th":    "ขออภัย เราไม่สามารถตอบคำถามของคุณได้อย่างมั่นใจ กรุณาติดต่อฝ่ายบริการลูกค้า",
+		"vi":    "Chúng tôi chưa thể trả lời chắc chắn câu hỏi của bạn. Vui lòng liên hệ bộ phận chăm sóc khách hàng.",
+		"es":    "No podemos responder a su pregunta con seguridad. Póngase en contacto con atención al cliente.",
+		"fr":    "Nous ne pouvons pas répondre à votre question avec certitude. Veuillez contacter le service client.",
+		"de":    "Wir können Ihre Frage leider nicht sicher beantworten. Bitte wenden Sie sich an den Kundenservice.",
+		"it":    "Non siamo in grado di rispondere con certezza alla tua domanda. Contatta l'assistenza clienti.",
+		"pt":    "Não conseguimos responder à sua pergunta com segurança. Entre em contato com o atendimento ao cliente.",
+		"pt-PT": "Não conseguimos responder à sua pergunta com segurança. Contacte o apoio ao cliente.",
+	},
+	RateLimited: {
+		"en": "Too many requests. Please try again in a moment.",
+		"id": "Terlalu banyak permintaan. Silakan coba lagi sebentar lagi.",
+		"ms": "Terlalu banyak permintaan. Sila cuba lagi sebentar lagi.",
+		"th": "มีคำขอมากเกินไป กรุณาลองใหม่อีกครั้งในอีกสักครู่",
+		"vi": "Có quá nhiều yêu cầu. Vui lòng thử lại sau giây lát.",
+		"es": "Demasiadas solicitudes. Inténtelo de nuevo en un momento.",
+		"fr": "Trop de demandes. Veuillez réessayer dans un instant.",
+		"de": "Zu viele Anfragen. Bitte versuchen Sie es gleich noch einmal.",
+		"it": "Troppe richieste. Riprova tra un momento.",
+		"pt": "Muitas solicitações. Tente novamente em instantes.",
+	},
+	BudgetExceeded: {
+		"en": "Our assistant is unavailable right now. Please contact customer support.",
+		"id": "Asisten kami sedang tidak tersedia. Silakan hubungi layanan pelanggan.",
+		"ms": "Pembantu kami tidak tersedia buat masa ini. Sila hubungi khidmat pelanggan.",
+		"th": "ผู้ช่วยของเราไม่พร้อมให้บริการในขณะนี้ กรุณาติดต่อฝ่ายบริการลูกค้า",
+		"vi": "Trợ lý của chúng tôi hiện không khả dụng. Vui lòng liên hệ bộ phận chăm sóc khách hàng.",
+		"es": "Nuestro asistente no está disponible en este momento. Póngase en contacto con atención al cliente.",
+		"fr": "Notre assistant est indisponible pour le moment. Veuillez contacter le service client.",
+		"de": "Unser Assistent ist gerade nicht verfügbar. Bitte wenden Sie sich an den Kundenservice.",
+		"it": "Il nostro assistente non è al momento disponibile. Contatta l'assistenza clienti.",
+		"pt": "Nosso assistente está indisponível no momento. Entre em contato com o atendimento ao cliente.",
+	},
+	RejectedInput: {
+		"en": "We could not process this question. Please rephrase it.",
+		"id": "Kami tidak dapat memproses pertanyaan ini. Silakan ubah kalimatnya.",
+		"ms": "Kami tidak dapat memproses soalan ini. Sila ubah ayatnya.",
+		"th": "เราไม่สามารถดำเนินการกับคำถามนี้ได้ กรุณาเรียบเรียงคำถามใหม่",
+		"vi": "Chúng tôi không thể xử lý câu hỏi này. Vui lòng diễn đạt lại.",
+		"es": "No pudimos procesar esta pregunta. Por favor, reformúlela.",
+		"fr": "Nous n'avons pas pu traiter cette question. Veuillez la reformuler.",
+		"de": "Wir konnten diese Frage nicht verarbeiten. Bitte formulieren Sie sie um.",
+		"it": "Non siamo riusciti a elaborare questa domanda. Riformulala, per favore.",
+		"pt": "Não conseguimos processar esta pergunta. Por favor, reformule-a.",
+	},
+	UnsupportedLanguage: {
+		"en": "Sorry, we do not support this language yet.",
+		"id": "Maaf, kami belum mendukung bahasa ini.",
+		"ms": "Maaf, kami belum menyokong bahasa ini.",
+		"th": "ขออภัย เรายังไม่รองรับภาษานี้",
+		"vi": "Xin lỗi, chúng tôi chưa hỗ trợ ngôn ngữ này.",
+		"es": "Lo sentimos, todavía no admitimos este idioma.",
+		"fr": "Désolé, nous ne prenons pas encore en charge cette langue.",
+		"de": "Diese Sprache wird leider noch nicht unterstützt.",
+		"it": "Spiacenti, questa lingua non è ancora supportata.",
+		"pt": "Desculpe, ainda não oferecemos suporte a este idioma.",
+	},
+	InternalError: {
+		"en": "Something went wrong. Please try again later.",
+		"id": "Terjadi kesalahan. Silakan coba lagi nanti.",
+		"ms": "Berlaku ralat. Sila cuba lagi nanti.",
+		"th": "เกิดข้อผิดพลาด กรุณาลองใหม่อีกครั้งภายหลัง",
+		"vi": "Đã xảy ra lỗi. Vui lòng thử lại sau.",
+		"es": "Algo salió mal. Inténtelo de nuevo más tarde.",
+		"fr": "Une erreur s'est produite. Veuillez réessayer plus tard.",
+		"de": "Etwas ist schiefgelaufen. Bitte versuchen Sie es später erneut.",
+		"it": "Si è verificato un errore. Riprova più tardi.",
+		"pt": "Algo deu errado. Tente novamente mais tarde.",
+	},
+}
+
+// placeholder matches {name} variables in message texts.
+var placeholder = regexp.MustCompile(`\{([a-z][a-z0-9_]*)\}`)
+
+// ValidKey reports whether key is a message key.
+func ValidKey(key string) bool {
+	_, ok := builtin[key]
+	return ok
+}
+
+// Placeholders returns the variable names used in text.
+func Placeholders(text string) []string {
+	var names []string
+	for _, m := range placeholder.FindAllStringSubmatch(text, -1) {
+		names = append(names, m[1])
+	}
+	return names
+}
+
+// Catalog resolves messages for one tenant.
+type Catalog struct {
+	Overrides map[string]map[string]string // Key, then locale tag or language
+	Vars      map[string]string            // Values for {name} placeholders, e.g. support_phone
+}
+
+// Override returns the tenant's text for key in tag's locale or language.
+func (c Catalog) Override(key, tag string) (string, bool) {
+	text, ok := lookup(c.Overrides[key], tag)
+	if !ok {
+		return "", false
+	}
+	return c.Expand(text), true
+}
+
+// Text returns the tenant's text for key, else the built-in text for the
+// locale, else the English one.
+func (c Catalog) Text(key, tag string) string {
+	if text, ok := c.Override(key, tag); ok {
+		return text
+	}
+	if text, ok := lookup(builtin[key], tag); ok {
+		return c.Expand(text)
+	}
+	return c.Expand(builtin[key]["en"])
+}
+
+// Expand fills {name} placeholders from Vars; unknown names are left as they are.
+func (c Catalog) Expand(text string) string {
+	if len(c.Vars) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+	return placeholder.ReplaceAllStringFunc(text, func(m string) string {
+		if v, ok := c.Vars[m[1:len(m)-1]]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// lookup prefers the exact locale tag over its language.
+func lookup(texts map[string]string, tag string) (string, bool) {
+	if len(texts) == 0 {
+		return "", false
+	}
+	if text, ok := texts[locale.Canonical(tag)]; ok {
+		return text, true
+	}
+	text, ok := texts[locale.BaseLanguage(tag)]
+	return text, ok
+}
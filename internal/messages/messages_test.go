@@ -0,0 +1,42 @@
+package messages
+
+import "testing"
+
+func TestCatalog_Text(t *testing.T) {
+	c := Catalog{
+		Overrides: map[string]map[string]string{
+			RateLimited: {"id": "Sibuk, hubungi {support_phone}.", "pt-BR": "Aguarde um momento."},
+		},
+		Vars: map[string]string{"support_phone": "1500-123"},
+	}
+	tests := []struct {
+		key, tag, want string
+	}{
+		{RateLimited, "id-ID", "Sibuk, hubungi 1500-123."},
+		{RateLimited, "pt-BR", "Aguarde um momento."},
+		{RateLimited, "pt-PT", builtin[RateLimited]["pt"]},
+		{Fallback, "pt-PT", builtin[Fallback]["pt-PT"]},
+		{Fallback, "id", builtin[Fallback]["id"]},
+		{Fallback, "xx", DefaultFallback},
+	}
+	for _, tt := range tests {
+		if got := c.Text(tt.key, tt.tag); got != tt.want {
+			t.Errorf("Text(%s, %s) = %q, want %q", tt.key, tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestCatalog_Expand(t *testing.T) {
+	c := Catalog{Vars: map[string]string{"support_url": "https://help.example"}}
+	if got := c.Expand("See {support_url} or {unknown}."); got != "See https://help.example or {unknown}." {
+		t.Errorf("Expand() = %q", got)
+	}
+}
+
+func TestEveryKeyHasEnglish(t *testing.T) {
+	for _, key := range Keys {
+		if builtin[key]["en"] == "" {
+			t.Errorf("message %s has no English text", key)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/auth"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HeaderAPIKey = "X-API-Key"
+	CtxIdentity  = "identity"
+	CtxTenantID  = "tenant_id"
+)
+
+// Authenticate resolves the caller from either the X-API-Key header or an
+// "Authorization: Bearer <jwt>" header and attaches an *auth.Identity to the
+// context. The tenant is derived from the credential, never from the request
+// body. verifier may be nil when JWT authentication is not configured.
+// When required is false (local development), requests without credentials
+// pass through unauthenticated; supplied credentials are still validated.
+func Authenticate(keys *auth.KeyStore, verifier *auth.JWTVerifier, required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawKey := c.GetHeader(HeaderAPIKey)
+		bearer := bearerToken(c.GetHeader("Authorization"))
+
+		var identity *auth.Identity
+		switch {
+		case rawKey != "":
+			key, err := keys.Authenticate(rawKey)
+			if err != nil {
+				logger.Error("api key authentication failed", map[string]interface{}{
+					"error":      err.Error(),
+					"request_id": c.GetString(CtxRequestID),
+				})
+				message := "Invalid API key"
+				if errors.Is(err, auth.ErrKeyRevoked) {
+					message = "API key has been revoked"
+				}
+				abortAuth(c, http.StatusUnauthorized, "UNAUTHORIZED", message)
+				return
+			}
+			identity = &auth.Identity{
+				Method:   "api_key",
+				KeyID:    key.ID,
+				TenantID: key.TenantID,
+				Scopes:   key.Scopes,
+			}
+		case bearer != "":
+			if verifier == nil {
+				abortAuth(c, http.StatusUnauthorized, "UNAUTHORIZED", "Bearer authentication is not configured")
+				return
+			}
+			verified, err := verifier.Verify(c.Request.Context(), bearer)
+			if err != nil {
+				logger.Error("jwt authentication failed", map[string]interface{}{
+					"error":      err.Error(),
+					"request_id": c.GetString(CtxRequestID),
+				})
+				message := "Invalid token"
+				if errors.Is(err, auth.ErrTokenExpired) {
+					message = "Token has expired"
+				}
+				abortAuth(c, http.StatusUnauthorized, "UNAUTHORIZED", message)
+				return
+			}
+			identity = verified
+		default:
+			if required {
+				abortAuth(c, http.StatusUnauthorized, "UNAUTHORIZED", "Missing API key or bearer token")
+				return
+			}
+			c.Next()
+			return
+		}
+
+		c.Set(CtxIdentity, identity)
+		if identity.TenantID != "" {
+			c.Set(CtxTenantID, identity.TenantID)
+		}
+		c.Next()
+	}
+}
+
+// RequireScope rejects authenticated callers that lack scope. Unauthenticated
+// requests only reach this point when authentication is disabled, so they pass.
+func RequireScope(scope auth.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := IdentityFrom(c)
+		if ok && !identity.HasScope(scope) {
+			abortAuth(c, http.StatusForbidden, "FORBIDDEN", "Credential lacks required scope: "+string(scope))
+			return
+		}
+		c.Next()
+	}
+}
+
+// IdentityFrom returns the authenticated identity, if any.
+func IdentityFrom(c *gin.Context) (*auth.Identity, bool) {
+	v, ok := c.Get(CtxIdentity)
+	if !ok {
+		return nil, false
+	}
+	identity, ok := v.(*auth.Identity)
+	return identity, ok && identity != nil
+}
+
+func bearerToken(header string) string {
+	const prefix = "bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(header[len(prefix):])
+}
+
+func abortAuth(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, gin.H{
+		"error": gin.H{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
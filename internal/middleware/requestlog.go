@@ -14,13 +14,13 @@ const (
 	CtxRequestID    = "request_id"
 )
 
-// RequestLogger adds a request_id (if missing), logs request completion, and records latency.
+// RequestLogger adds a request_id (if missing or malformed), logs request completion, and records latency.
 func RequestLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
 
 		reqID := c.GetHeader(HeaderRequestID)
-		if reqID == "" {
+		if !validRequestID(reqID) {
 			reqID = newRequestID()
 		}
 		c.Set(CtxRequestID, reqID)
@@ -49,6 +49,20 @@ func RequestLogger() gin.HandlerFunc {
 	}
 }
 
+// validRequestID accepts inbound IDs of up to 128 letters, digits, '-', '_'
+// and '.', so client IDs cannot flood or forge log fields.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
 func newRequestID() string {
 	var b [16]byte
 	_, _ = rand.Read(b[:])
@@ -0,0 +1,51 @@
+// Package netguard keeps outbound HTTP calls to tenant- or caller-supplied
+// URLs, such as tools and job callbacks, away from internal networks.
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedAddress is returned when a connection would reach an internal address.
+var ErrBlockedAddress = errors.New("address not allowed")
+
+// sharedAddressSpace is 100.64.0.0/10 (RFC 6598), used for carrier-grade NAT
+// and by some cloud metadata services.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Blocked reports whether ip is loopback, private, link-local (including
+// 169.254.169.254), shared, unspecified or multicast.
+func Blocked(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddressSpace.Contains(ip)
+}
+
+// Control is a net.Dialer Control function that refuses blocked addresses.
+// It sees the resolved IP at connect time, so DNS rebinding cannot get
+// around it.
+func Control(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || Blocked(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	return nil
+}
+
+// Transport returns an HTTP transport whose connections pass Control. It
+// ignores proxy settings, since the proxy, not the target, would be checked.
+func Transport() *http.Transport {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: Control}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	t.DialContext = dialer.DialContext
+	return t
+}
@@ -19,6 +19,16 @@ type Metrics struct {
 	CacheHitsTotal     atomic.Int64
 	CacheMissesTotal   atomic.Int64
 
+	InjectionBlockedTotal atomic.Int64
+	OutputBlockedTotal    atomic.Int64
+
+	EscalatedTotal     atomic.Int64
+	CannedAnswersTotal atomic.Int64
+	BatchItemsTotal    atomic.Int64
+	JobsSubmittedTotal atomic.Int64
+
+	IdempotentReplaysTotal atomic.Int64
+
 	LatencyCount atomic.Int64
 	LatencySumMs atomic.Int64
 }
@@ -43,15 +53,22 @@ func (m *Metrics) Snapshot() map[string]interface{} {
 	}
 
 	return map[string]interface{}{
-		"requests_total":       m.RequestsTotal.Load(),
-		"errors_total":         m.ErrorsTotal.Load(),
-		"rate_limited_total":   m.RateLimitedTotal.Load(),
-		"budget_blocked_total": m.BudgetBlockedTotal.Load(),
-		"cache_hits_total":     m.CacheHitsTotal.Load(),
-		"cache_misses_total":   m.CacheMissesTotal.Load(),
-		"latency_count":        count,
-		"latency_sum_ms":       sum,
-		"latency_avg_ms":       avg,
+		"requests_total":           m.RequestsTotal.Load(),
+		"errors_total":             m.ErrorsTotal.Load(),
+		"rate_limited_total":       m.RateLimitedTotal.Load(),
+		"budget_blocked_total":     m.BudgetBlockedTotal.Load(),
+		"cache_hits_total":         m.CacheHitsTotal.Load(),
+		"cache_misses_total":       m.CacheMissesTotal.Load(),
+		"injection_blocked_total":  m.InjectionBlockedTotal.Load(),
+		"output_blocked_total":     m.OutputBlockedTotal.Load(),
+		"escalated_total":          m.EscalatedTotal.Load(),
+		"canned_answers_total":     m.CannedAnswersTotal.Load(),
+		"batch_items_total":        m.BatchItemsTotal.Load(),
+		"jobs_submitted_total":     m.JobsSubmittedTotal.Load(),
+		"idempotent_replays_total": m.IdempotentReplaysTotal.Load(),
+		"latency_count":            count,
+		"latency_sum_ms":           sum,
+		"latency_avg_ms":           avg,
 	}
 }
 
@@ -0,0 +1,252 @@
+package pii
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Kind is a category of personal data.
+type Kind string
+
+const (
+	KindEmail   Kind = "email"
+	KindCard    Kind = "card"
+	KindNIK     Kind = "nik" // Indonesian national identity number (Nomor Induk Kependudukan)
+	KindPhone   Kind = "phone"
+	KindAddress Kind = "address"
+)
+
+// AllKinds lists every kind in the order they are applied. Cards and NIKs run
+// before phones so long digit runs are classified by their stricter rule.
+var AllKinds = []Kind{KindEmail, KindCard, KindNIK, KindPhone, KindAddress}
+
+// localeKinds are the default kinds per language; other languages use defaultKinds.
+var (
+	defaultKinds = []Kind{KindEmail, KindCard, KindPhone, KindAddress}
+	localeKinds  = map[string][]Kind{
+		"id": AllKinds,
+	}
+)
+
+// DefaultKinds returns the kinds redacted for a language when a tenant does not configure its own.
+func DefaultKinds(language string) []Kind {
+	if kinds, ok := localeKinds[strings.ToLower(language)]; ok {
+		return kinds
+	}
+	return defaultKinds
+}
+
+// ParseKinds validates kind names.
+func ParseKinds(names []string) ([]Kind, error) {
+	kinds := make([]Kind, 0, len(names))
+	for _, name := range names {
+		k := Kind(strings.ToLower(strings.TrimSpace(name)))
+		if _, ok := rules[k]; !ok {
+			return nil, fmt.Errorf("unknown pii kind %q", name)
+		}
+		kinds = append(kinds, k)
+	}
+	return kinds, nil
+}
+
+type rule struct {
+	patterns []*regexp.Regexp
+	valid    func(match string) bool // Optional post-match check
+}
+
+var rules = map[Kind]rule{
+	KindEmail: {
+		patterns: []*regexp.Regexp{regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)},
+	},
+	KindCard: {
+		patterns: []*regexp.Regexp{regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`)},
+		valid: func(match string) bool {
+			d := digits(match)
+			return len(d) >= 13 && len(d) <= 19 && luhn(d)
+		},
+	},
+	KindNIK: {
+		patterns: []*regexp.Regexp{regexp.MustCompile(`\b\d{16}\b`)},
+		valid:    validNIK,
+	},
+	KindPhone: {
+		patterns: []*regexp.Regexp{
+			// Indonesian mobile: +62 / 62 / 0 followed by 8xx
+			regexp.MustCompile(`(?:\+62|\b62|\b0)[\s\-]?8\d{1,2}[\s\-]?\d{3,4}[\s\-]?\d{3,5}\b`),
+			// International with a leading +
+			regexp.MustCompile(`\+\d{1,3}[\s\-]?\(?\d{1,4}\)?(?:[\s\-.]?\d{2,4}){2,4}\b`),
+			// North American (555) 123-4567 / 555-123-4567
+			regexp.MustCompile(`(?:\(\d{3}\)\s?|\b\d{3}[\s\-.])\d{3}[\s\-.]\d{4}\b`),
+		},
+		valid: func(match string) bool {
+			n := len(digits(match))
+			return n >= 9 && n <= 15
+		},
+	},
+	KindAddress: {
+		patterns: []*regexp.Regexp{
+			// Indonesian street addresses: "Jl. Sudirman No. 5", "Jalan Merdeka Raya 10"
+			regexp.MustCompile(`\b(?:Jl|Jln|Jalan)\.?\s+[A-Z][\w\-]*(?:,?\s+(?:No|Nomor)\.?\s*\d+[A-Za-z]?|\s+[A-Z][\w\-]*|\s+\d+[A-Za-z]?\b){0,5}`),
+			regexp.MustCompile(`(?i)\bRT\.?\s*\d{1,3}\s*/\s*RW\.?\s*\d{1,3}\b`),
+			// English street addresses: "221B Baker Street", "1600 Pennsylvania Ave"
+			regexp.MustCompile(`\b\d{1,5}[A-Za-z]?\s+(?:[A-Z][a-z]+\s+){1,4}(?:Street|St|Avenue|Ave|Road|Rd|Boulevard|Blvd|Lane|Ln|Drive|Dr|Court|Ct|Way|Place|Pl)\b\.?`),
+		},
+	},
+}
+
+// placeholder matches the tokens written by Redact, e.g. [EMAIL_1].
+var placeholder = regexp.MustCompile(`\[(?:EMAIL|CARD|NIK|PHONE|ADDRESS)_\d+\]`)
+
+// Redactor replaces personal data with reversible placeholders.
+type Redactor struct {
+	kinds []Kind
+}
+
+// New creates a redactor for the given kinds, applied in AllKinds order.
+func New(kinds []Kind) *Redactor {
+	enabled := map[Kind]bool{}
+	for _, k := range kinds {
+		enabled[k] = true
+	}
+	r := &Redactor{}
+	for _, k := range AllKinds {
+		if enabled[k] {
+			r.kinds = append(r.kinds, k)
+		}
+	}
+	return r
+}
+
+// Redact replaces personal data in text with placeholders recorded in v.
+// The same value always maps to the same placeholder within a vault.
+func (r *Redactor) Redact(text string, v *Vault) string {
+	for _, k := range r.kinds {
+		rule := rules[k]
+		for _, p := range rule.patterns {
+			text = p.ReplaceAllStringFunc(text, func(match string) string {
+				if placeholder.MatchString(match) || (rule.valid != nil && !rule.valid(match)) {
+					return match
+				}
+				return v.add(k, match)
+			})
+		}
+	}
+	return text
+}
+
+// RedactString redacts text without keeping the originals, e.g. for logs.
+func (r *Redactor) RedactString(text string) string {
+	return r.Redact(text, NewVault())
+}
+
+// Vault holds the originals behind placeholders for one request.
+// It is safe for concurrent use.
+type Vault struct {
+	mu        sync.Mutex
+	originals map[string]string // placeholder -> original
+	byValue   map[string]string // kind|original -> placeholder
+	counts    map[Kind]int
+}
+
+// NewVault creates an empty vault.
+func NewVault() *Vault {
+	return &Vault{
+		originals: map[string]string{},
+		byValue:   map[string]string{},
+		counts:    map[Kind]int{},
+	}
+}
+
+func (v *Vault) add(k Kind, original string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	key := string(k) + "|" + original
+	if p, ok := v.byValue[key]; ok {
+		return p
+	}
+	v.counts[k]++
+	p := "[" + strings.ToUpper(string(k)) + "_" + strconv.Itoa(v.counts[k]) + "]"
+	v.byValue[key] = p
+	v.originals[p] = original
+	return p
+}
+
+// Len returns the number of redacted values.
+func (v *Vault) Len() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.originals)
+}
+
+// Kinds returns how many values of each kind were redacted.
+func (v *Vault) Kinds() map[Kind]int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	out := make(map[Kind]int, len(v.counts))
+	for k, n := range v.counts {
+		out[k] = n
+	}
+	return out
+}
+
+// Restore replaces placeholders in text with the original values. Unknown
+// placeholders are left as they are.
+func (v *Vault) Restore(text string) string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if len(v.originals) == 0 {
+		return text
+	}
+	return placeholder.ReplaceAllStringFunc(text, func(p string) string {
+		if original, ok := v.originals[p]; ok {
+			return original
+		}
+		return p
+	})
+}
+
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// luhn reports whether the digit string passes the Luhn checksum used by payment cards.
+func luhn(d string) bool {
+	sum := 0
+	double := false
+	for i := len(d) - 1; i >= 0; i-- {
+		n := int(d[i] - '0')
+		if double {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validNIK checks the NIK layout: 2-digit province (11-94), regency, district,
+// then DDMMYY birth date where women add 40 to the day, then a serial.
+func validNIK(match string) bool {
+	province, _ := strconv.Atoi(match[0:2])
+	day, _ := strconv.Atoi(match[6:8])
+	month, _ := strconv.Atoi(match[8:10])
+	if day > 40 {
+		day -= 40
+	}
+	return province >= 11 && province <= 94 && day >= 1 && day <= 31 && month >= 1 && month <= 12
+}
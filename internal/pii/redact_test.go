@@ -0,0 +1,75 @@
+package pii
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactor_Redact(t *testing.T) {
+	r := New(AllKinds)
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "email", text: "Mail me at budi.santoso+shop@example.co.id please", want: "Mail me at [EMAIL_1] please"},
+		{name: "card with spaces", text: "Card 4111 1111 1111 1111 was charged", want: "Card [CARD_1] was charged"},
+		{name: "invalid luhn is kept", text: "Order 4111 1111 1111 1112 status", want: "Order 4111 1111 1111 1112 status"},
+		{name: "nik", text: "NIK saya 3174056512900001", want: "NIK saya [NIK_1]"},
+		{name: "indonesian mobile", text: "Hubungi 0812-3456-7890 atau +62 812 3456 7890", want: "Hubungi [PHONE_1] atau [PHONE_2]"},
+		{name: "us phone", text: "Call (555) 123-4567", want: "Call [PHONE_1]"},
+		{name: "indonesian address", text: "Kirim ke Jl. Sudirman No. 5, RT 01/RW 02", want: "Kirim ke [ADDRESS_1], [ADDRESS_2]"},
+		{name: "english address", text: "I live at 221B Baker Street, London", want: "I live at [ADDRESS_1], London"},
+		{name: "order number is kept", text: "Where is order 12345?", want: "Where is order 12345?"},
+		{name: "repeated value reuses placeholder", text: "a@b.com or a@b.com", want: "[EMAIL_1] or [EMAIL_1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Redact(tt.text, NewVault()); got != tt.want {
+				t.Errorf("Redact() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVault_Restore(t *testing.T) {
+	r := New(AllKinds)
+	v := NewVault()
+	redacted := r.Redact("I'm jane@example.com, card 5500 0000 0000 0004", v)
+	if strings.Contains(redacted, "jane@example.com") || strings.Contains(redacted, "5500") {
+		t.Fatalf("PII leaked: %q", redacted)
+	}
+
+	answer := "We emailed [EMAIL_1] about card [CARD_1]. [PHONE_9] is unknown."
+	want := "We emailed jane@example.com about card 5500 0000 0000 0004. [PHONE_9] is unknown."
+	if got := v.Restore(answer); got != want {
+		t.Errorf("Restore() = %q, want %q", got, want)
+	}
+	if v.Len() != 2 || v.Kinds()[KindCard] != 1 {
+		t.Errorf("Len() = %d, Kinds() = %v", v.Len(), v.Kinds())
+	}
+}
+
+func TestDefaultKinds(t *testing.T) {
+	hasNIK := func(kinds []Kind) bool {
+		for _, k := range kinds {
+			if k == KindNIK {
+				return true
+			}
+		}
+		return false
+	}
+	if !hasNIK(DefaultKinds("id")) {
+		t.Error("Indonesian locale should redact NIK")
+	}
+	if hasNIK(DefaultKinds("en")) {
+		t.Error("English locale should not redact NIK by default")
+	}
+
+	if _, err := ParseKinds([]string{"email", " Phone "}); err != nil {
+		t.Errorf("ParseKinds() error = %v", err)
+	}
+	if _, err := ParseKinds([]string{"passport"}); err == nil {
+		t.Error("expected error for unknown kind")
+	}
+}
@@ -0,0 +1,247 @@
+package prompts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/llm"
+)
+
+var (
+	ErrVersionNotFound = errors.New("prompt version not found")
+	ErrNoActiveVersion = errors.New("no active prompt version")
+	ErrInvalidTemplate = errors.New("invalid prompt template")
+)
+
+// Version is one immutable revision of a tenant's prompt template.
+type Version struct {
+	TenantID    string    `json:"tenant_id"`
+	Version     int       `json:"version"`
+	System      string    `json:"system"`
+	User        string    `json:"user,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedBy   string    `json:"created_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	compiled *llm.PromptTemplate
+}
+
+// Template returns the compiled template for this version.
+func (v Version) Template() *llm.PromptTemplate {
+	return v.compiled
+}
+
+type tenantPrompts struct {
+	versions []Version // index i holds version i+1
+	active   int       // 0 = none; the built-in prompt is used
+}
+
+// storedTenant is the on-disk form of a tenant's prompt history.
+type storedTenant struct {
+	TenantID string    `json:"tenant_id"`
+	Active   int       `json:"active_version"`
+	Versions []Version `json:"versions"`
+}
+
+// Store keeps versioned, tenant-scoped prompt templates. Versions are never
+// modified; activating a version is an atomic pointer move. When a storage
+// path is set, every change is persisted as a JSON snapshot.
+type Store struct {
+	mu      sync.RWMutex
+	tenants map[string]*tenantPrompts
+	path    string
+	now     func() time.Time
+}
+
+// NewStore creates an empty prompt store
+func NewStore() *Store {
+	return &Store{
+		tenants: map[string]*tenantPrompts{},
+		now:     time.Now,
+	}
+}
+
+// LoadFile replaces the store contents with a snapshot previously written by
+// the store and persists subsequent changes to the same path. A missing file
+// starts an empty store.
+func (s *Store) LoadFile(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s.saveLocked()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read prompt store: %w", err)
+	}
+
+	var snapshot []storedTenant
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse prompt store: %w", err)
+	}
+	tenants := make(map[string]*tenantPrompts, len(snapshot))
+	for _, st := range snapshot {
+		tp := &tenantPrompts{versions: make([]Version, 0, len(st.Versions)), active: st.Active}
+		for i, v := range st.Versions {
+			if v.Version != i+1 {
+				return fmt.Errorf("invalid prompt store: tenant %q version %d out of order", st.TenantID, v.Version)
+			}
+			compiled, err := llm.ParsePromptTemplate(v.System, v.User)
+			if err != nil {
+				return fmt.Errorf("invalid prompt store: tenant %q version %d: %w", st.TenantID, v.Version, err)
+			}
+			v.TenantID = st.TenantID
+			v.compiled = compiled
+			tp.versions = append(tp.versions, v)
+		}
+		if tp.active < 0 || tp.active > len(tp.versions) {
+			return fmt.Errorf("invalid prompt store: tenant %q active version %d not found", st.TenantID, tp.active)
+		}
+		tenants[st.TenantID] = tp
+	}
+	s.tenants = tenants
+	return nil
+}
+
+// Create validates and stores a new version. It does not become active
+// unless activate is true.
+func (s *Store) Create(tenantID, system, user, description, createdBy string, activate bool) (Version, error) {
+	compiled, err := llm.ParsePromptTemplate(system, user)
+	if err != nil {
+		return Version{}, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tp := s.tenants[tenantID]
+	created := tp == nil
+	if created {
+		tp = &tenantPrompts{}
+		s.tenants[tenantID] = tp
+	}
+	prevActive := tp.active
+	v := Version{
+		TenantID:    tenantID,
+		Version:     len(tp.versions) + 1,
+		System:      system,
+		User:        user,
+		Description: description,
+		CreatedBy:   createdBy,
+		CreatedAt:   s.now(),
+		compiled:    compiled,
+	}
+	tp.versions = append(tp.versions, v)
+	if activate {
+		tp.active = v.Version
+	}
+	if err := s.saveLocked(); err != nil {
+		tp.versions = tp.versions[:len(tp.versions)-1]
+		tp.active = prevActive
+		if created {
+			delete(s.tenants, tenantID)
+		}
+		return Version{}, err
+	}
+	return v, nil
+}
+
+// Get returns a specific version.
+func (s *Store) Get(tenantID string, version int) (Version, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tp := s.tenants[tenantID]
+	if tp == nil || version < 1 || version > len(tp.versions) {
+		return Version{}, ErrVersionNotFound
+	}
+	return tp.versions[version-1], nil
+}
+
+// Active returns the tenant's active version.
+func (s *Store) Active(tenantID string) (Version, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tp := s.tenants[tenantID]
+	if tp == nil || tp.active == 0 {
+		return Version{}, ErrNoActiveVersion
+	}
+	return tp.versions[tp.active-1], nil
+}
+
+// List returns every version for the tenant and the active version number (0 = none).
+func (s *Store) List(tenantID string) ([]Version, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tp := s.tenants[tenantID]
+	if tp == nil {
+		return []Version{}, 0
+	}
+	return append([]Version(nil), tp.versions...), tp.active
+}
+
+// Activate makes version the tenant's live prompt. Version 0 reverts to the built-in prompt.
+func (s *Store) Activate(tenantID string, version int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tp := s.tenants[tenantID]
+	if version == 0 && tp == nil {
+		return nil
+	}
+	if tp == nil || version < 0 || version > len(tp.versions) {
+		return ErrVersionNotFound
+	}
+	prev := tp.active
+	tp.active = version
+	if err := s.saveLocked(); err != nil {
+		tp.active = prev
+		return err
+	}
+	return nil
+}
+
+// saveLocked writes the snapshot atomically (temp file + rename).
+func (s *Store) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+	snapshot := make([]storedTenant, 0, len(s.tenants))
+	for id, tp := range s.tenants {
+		snapshot = append(snapshot, storedTenant{TenantID: id, Active: tp.active, Versions: tp.versions})
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].TenantID < snapshot[j].TenantID })
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode prompt store: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".prompts-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to persist prompt store: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to persist prompt store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to persist prompt store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to persist prompt store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package prompts
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_Versioning(t *testing.T) {
+	s := NewStore()
+
+	if _, err := s.Active("shop-123"); !errors.Is(err, ErrNoActiveVersion) {
+		t.Fatalf("Active() error = %v, want ErrNoActiveVersion", err)
+	}
+
+	v1, err := s.Create("shop-123", "v1 {{.BrandName}}", "", "first", "admin", true)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	v2, err := s.Create("shop-123", "v2 {{.BrandName}}", "", "second", "admin", false)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if v1.Version != 1 || v2.Version != 2 {
+		t.Fatalf("versions = %d, %d, want 1, 2", v1.Version, v2.Version)
+	}
+
+	active, err := s.Active("shop-123")
+	if err != nil || active.Version != 1 {
+		t.Fatalf("Active() = %d, %v, want version 1", active.Version, err)
+	}
+
+	if err := s.Activate("shop-123", 2); err != nil {
+		t.Fatalf("Activate() error = %v", err)
+	}
+	versions, current := s.List("shop-123")
+	if len(versions) != 2 || current != 2 {
+		t.Fatalf("List() = %d versions, active %d", len(versions), current)
+	}
+
+	// Rollback to the built-in prompt
+	if err := s.Activate("shop-123", 0); err != nil {
+		t.Fatalf("Activate(0) error = %v", err)
+	}
+	if _, err := s.Active("shop-123"); !errors.Is(err, ErrNoActiveVersion) {
+		t.Errorf("Active() after revert error = %v", err)
+	}
+
+	if err := s.Activate("shop-123", 3); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("Activate(3) error = %v, want ErrVersionNotFound", err)
+	}
+	if _, err := s.Get("shop-456", 1); !errors.Is(err, ErrVersionNotFound) {
+		t.Errorf("Get() other tenant error = %v, want ErrVersionNotFound", err)
+	}
+}
+
+func TestStore_CreateRejectsInvalidTemplate(t *testing.T) {
+	s := NewStore()
+	if _, err := s.Create("shop-123", "{{.Nope}}", "", "", "admin", true); err == nil {
+		t.Fatal("expected error for invalid template")
+	}
+	if versions, _ := s.List("shop-123"); len(versions) != 0 {
+		t.Errorf("invalid template was stored: %d versions", len(versions))
+	}
+}
+
+func TestStore_LoadFilePersistsVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompts.json")
+
+	s := NewStore()
+	if err := s.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if _, err := s.Create("shop-123", "v1 {{.BrandName}}", "", "first", "admin", true); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := s.Create("shop-123", "v2 {{.BrandName}}", "Q: {{.Question}}", "second", "admin", false); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := s.Activate("shop-123", 2); err != nil {
+		t.Fatalf("Activate() error = %v", err)
+	}
+
+	reloaded := NewStore()
+	if err := reloaded.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	versions, active := reloaded.List("shop-123")
+	if len(versions) != 2 || active != 2 {
+		t.Fatalf("List() = %d versions, active %d; want 2, 2", len(versions), active)
+	}
+	v, err := reloaded.Active("shop-123")
+	if err != nil || v.Template() == nil || v.User != "Q: {{.Question}}" || v.CreatedBy != "admin" {
+		t.Errorf("Active() = %+v, %v; want compiled version 2", v, err)
+	}
+}
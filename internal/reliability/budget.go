@@ -30,26 +30,37 @@ func (b *BudgetGuard) BudgetTokens() int {
 
 // Allow returns false if tenant is already over budget for the current window.
 func (b *BudgetGuard) Allow(tenantID string) bool {
-	if !b.Enabled() {
+	return b.AllowBudget(tenantID, b.BudgetTokens())
+}
+
+// AllowBudget is Allow with a per-tenant budget in place of the global one.
+// A budget <= 0 disables the check.
+func (b *BudgetGuard) AllowBudget(tenantID string, budget int) bool {
+	if b == nil || b.tracker == nil || budget <= 0 {
 		return true
 	}
 	u, ok := b.tracker.Get(tenantID)
 	if !ok {
 		return true
 	}
-	return u.TokensUsed < b.budget
+	return u.TokensUsed < budget
 }
 
 // Remaining returns remaining budget and a boolean indicating if budget is enabled.
 func (b *BudgetGuard) Remaining(tenantID string) (int, bool, time.Time) {
-	if !b.Enabled() {
+	return b.RemainingBudget(tenantID, b.BudgetTokens())
+}
+
+// RemainingBudget is Remaining with a per-tenant budget in place of the global one.
+func (b *BudgetGuard) RemainingBudget(tenantID string, budget int) (int, bool, time.Time) {
+	if b == nil || b.tracker == nil || budget <= 0 {
 		return 0, false, time.Time{}
 	}
 	u, ok := b.tracker.Get(tenantID)
 	if !ok {
-		return b.budget, true, time.Time{}
+		return budget, true, time.Time{}
 	}
-	remaining := b.budget - u.TokensUsed
+	remaining := budget - u.TokensUsed
 	if remaining < 0 {
 		remaining = 0
 	}
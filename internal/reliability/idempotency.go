@@ -0,0 +1,106 @@
+package reliability
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrIdempotencyKeyReused means the key was already used with a different request.
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused with a different request")
+	// ErrIdempotencyInProgress means the first request with the key has not finished.
+	ErrIdempotencyInProgress = errors.New("request with this idempotency key is in progress")
+)
+
+type idempotencyEntry[T any] struct {
+	fingerprint string
+	done        bool
+	value       T
+	expiresAt   time.Time
+}
+
+// IdempotencyStore remembers responses by client-supplied idempotency key for
+// a window, so retried requests are answered without running again. It is
+// separate from ResponseCache: entries are keyed by the caller, not the
+// question, and are never shared between callers. Safe for concurrent use.
+type IdempotencyStore[T any] struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	items     map[string]*idempotencyEntry[T]
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+func NewIdempotencyStore[T any](window time.Duration) *IdempotencyStore[T] {
+	if window <= 0 {
+		window = 24 * time.Hour
+	}
+	return &IdempotencyStore[T]{
+		window: window,
+		items:  map[string]*idempotencyEntry[T]{},
+		now:    time.Now,
+	}
+}
+
+// Begin claims key for a request with the given fingerprint (e.g. a hash of
+// its body). For a new key it returns replay == false and the caller must
+// call Complete or Release. A finished request with the same fingerprint is
+// returned with replay == true. A different fingerprint returns
+// ErrIdempotencyKeyReused; a matching request still running returns
+// ErrIdempotencyInProgress.
+func (s *IdempotencyStore[T]) Begin(key, fingerprint string) (value T, replay bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweepLocked(now)
+	if e, ok := s.items[key]; ok && now.Before(e.expiresAt) {
+		switch {
+		case e.fingerprint != fingerprint:
+			return value, false, ErrIdempotencyKeyReused
+		case !e.done:
+			return value, false, ErrIdempotencyInProgress
+		default:
+			return e.value, true, nil
+		}
+	}
+	s.items[key] = &idempotencyEntry[T]{fingerprint: fingerprint, expiresAt: now.Add(s.window)}
+	return value, false, nil
+}
+
+// Complete stores the response for a key claimed with Begin; it is replayed
+// until the window ends.
+func (s *IdempotencyStore[T]) Complete(key string, value T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.items[key]; ok && !e.done {
+		e.done = true
+		e.value = value
+		e.expiresAt = s.now().Add(s.window)
+	}
+}
+
+// Release forgets a key claimed with Begin without storing a response, so a
+// retry runs again; used for failed requests.
+func (s *IdempotencyStore[T]) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.items[key]; ok && !e.done {
+		delete(s.items, key)
+	}
+}
+
+// sweepLocked drops expired entries, at most once a minute.
+func (s *IdempotencyStore[T]) sweepLocked(now time.Time) {
+	if now.Before(s.nextSweep) {
+		return
+	}
+	s.nextSweep = now.Add(time.Minute)
+	for key, e := range s.items {
+		if !now.Before(e.expiresAt) {
+			delete(s.items, key)
+		}
+	}
+}
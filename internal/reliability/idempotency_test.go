@@ -0,0 +1,66 @@
+package reliability
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyStore_Replay(t *testing.T) {
+	s := NewIdempotencyStore[string](time.Hour)
+
+	if _, replay, err := s.Begin("k1", "body-a"); err != nil || replay {
+		t.Fatalf("Begin(new) = replay %v, err %v; want a fresh claim", replay, err)
+	}
+	if _, _, err := s.Begin("k1", "body-a"); !errors.Is(err, ErrIdempotencyInProgress) {
+		t.Errorf("Begin(in flight) error = %v, want ErrIdempotencyInProgress", err)
+	}
+	s.Complete("k1", "answer")
+
+	value, replay, err := s.Begin("k1", "body-a")
+	if err != nil || !replay || value != "answer" {
+		t.Errorf("Begin(done) = %q, %v, %v; want replay of %q", value, replay, err, "answer")
+	}
+	if _, _, err := s.Begin("k1", "body-b"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Begin(different body) error = %v, want ErrIdempotencyKeyReused", err)
+	}
+}
+
+func TestIdempotencyStore_ReleaseAllowsRetry(t *testing.T) {
+	s := NewIdempotencyStore[string](time.Hour)
+
+	s.Begin("k1", "body-a")
+	s.Release("k1")
+	if _, replay, err := s.Begin("k1", "body-a"); err != nil || replay {
+		t.Errorf("Begin(after Release) = replay %v, err %v; want a fresh claim", replay, err)
+	}
+
+	// Completed keys are not released
+	s.Complete("k1", "answer")
+	s.Release("k1")
+	if value, replay, _ := s.Begin("k1", "body-a"); !replay || value != "answer" {
+		t.Errorf("Begin(after Release of done key) = %q, %v; want replay", value, replay)
+	}
+}
+
+func TestIdempotencyStore_Expiry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := NewIdempotencyStore[string](time.Hour)
+	s.now = func() time.Time { return now }
+
+	s.Begin("k1", "body-a")
+	s.Complete("k1", "answer")
+
+	now = now.Add(59 * time.Minute)
+	if _, replay, _ := s.Begin("k1", "body-a"); !replay {
+		t.Error("Begin(within window) did not replay")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, replay, err := s.Begin("k1", "body-b"); err != nil || replay {
+		t.Errorf("Begin(expired) = replay %v, err %v; want a fresh claim for any body", replay, err)
+	}
+	if len(s.items) != 1 {
+		t.Errorf("items = %d, want the expired entry swept", len(s.items))
+	}
+}
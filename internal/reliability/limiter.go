@@ -1,6 +1,7 @@
 package reliability
 
 import (
+	"context"
 	"sync"
 	"time"
 )
@@ -38,9 +39,22 @@ func NewTenantRateLimiter(ratePerSec float64, burst int) *TenantRateLimiter {
 
 // Allow returns true if the tenant is allowed to proceed right now.
 func (l *TenantRateLimiter) Allow(tenantID string) bool {
+	return l.AllowRate(tenantID, l.ratePerSec, int(l.burst))
+}
+
+// AllowRate is Allow with a per-tenant rate and burst in place of the global
+// limits. Non-positive values fall back to the global limits.
+func (l *TenantRateLimiter) AllowRate(tenantID string, ratePerSec float64, burst int) bool {
 	if tenantID == "" {
 		tenantID = "_unknown"
 	}
+	if ratePerSec <= 0 {
+		ratePerSec = l.ratePerSec
+	}
+	capacity := l.burst
+	if burst > 0 {
+		capacity = float64(burst)
+	}
 
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -48,16 +62,17 @@ func (l *TenantRateLimiter) Allow(tenantID string) bool {
 	b := l.buckets[tenantID]
 	now := l.now()
 	if b == nil {
-		l.buckets[tenantID] = &tokenBucket{tokens: l.burst - 1, lastRefill: now}
+		l.buckets[tenantID] = &tokenBucket{tokens: capacity - 1, lastRefill: now}
 		return true
 	}
 
-	// Refill based on elapsed time.
+	// Refill based on elapsed time; a lowered burst takes effect immediately.
 	elapsed := now.Sub(b.lastRefill).Seconds()
 	if elapsed > 0 {
-		b.tokens = minFloat(l.burst, b.tokens+(elapsed*l.ratePerSec))
+		b.tokens = b.tokens + (elapsed * ratePerSec)
 		b.lastRefill = now
 	}
+	b.tokens = minFloat(capacity, b.tokens)
 
 	if b.tokens < 1 {
 		return false
@@ -66,6 +81,23 @@ func (l *TenantRateLimiter) Allow(tenantID string) bool {
 	return true
 }
 
+// WaitRate blocks until AllowRate succeeds or ctx is done.
+func (l *TenantRateLimiter) WaitRate(ctx context.Context, tenantID string, ratePerSec float64, burst int) error {
+	if ratePerSec <= 0 {
+		ratePerSec = l.ratePerSec
+	}
+	for !l.AllowRate(tenantID, ratePerSec, burst) {
+		timer := time.NewTimer(time.Duration(float64(time.Second) / ratePerSec))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
 func minFloat(a, b float64) float64 {
 	if a < b {
 		return a
@@ -13,6 +13,8 @@ type TokenUsage struct {
 
 	Requests   int
 	TokensUsed int
+	// TranslationTokens is the part of TokensUsed spent translating questions and answers
+	TranslationTokens int
 }
 
 // TokenUsageTracker tracks per-tenant token usage in-memory.
@@ -47,6 +49,34 @@ func (t *TokenUsageTracker) Add(tenantID string, tokensUsed int) TokenUsage {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	u := t.currentLocked(tenantID)
+	u.Requests += 1
+	u.TokensUsed += tokensUsed
+	return *u
+}
+
+// AddTranslation records tokens spent on translation. They count towards the
+// budget but not as a separate request.
+func (t *TokenUsageTracker) AddTranslation(tenantID string, tokensUsed int) TokenUsage {
+	if tenantID == "" {
+		tenantID = "_unknown"
+	}
+	if tokensUsed < 0 {
+		tokensUsed = 0
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u := t.currentLocked(tenantID)
+	u.TokensUsed += tokensUsed
+	u.TranslationTokens += tokensUsed
+	return *u
+}
+
+// currentLocked returns the tenant's usage, starting a new window when the
+// previous one has expired.
+func (t *TokenUsageTracker) currentLocked(tenantID string) *TokenUsage {
 	now := t.now()
 	u := t.tenants[tenantID]
 	if u == nil || now.Sub(u.WindowStart) >= t.window {
@@ -57,10 +87,7 @@ func (t *TokenUsageTracker) Add(tenantID string, tokensUsed int) TokenUsage {
 		}
 		t.tenants[tenantID] = u
 	}
-
-	u.Requests += 1
-	u.TokensUsed += tokensUsed
-	return *u
+	return u
 }
 
 func (t *TokenUsageTracker) Get(tenantID string) (TokenUsage, bool) {
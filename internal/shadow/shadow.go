@@ -0,0 +1,276 @@
+package shadow
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/llm"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+)
+
+// Result is one model's answer to a request.
+type Result struct {
+	Model      string  `json:"model"`
+	Answer     string  `json:"answer,omitempty"`
+	Confidence float64 `json:"confidence"`
+	TokensUsed int     `json:"tokens_used"`
+	LatencyMs  int64   `json:"latency_ms"`
+	Fallback   bool    `json:"fallback"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Comparison stores the primary and shadow answers to the same request side by side.
+type Comparison struct {
+	RequestID           string    `json:"request_id"`
+	TenantID            string    `json:"tenant_id"`
+	Language            string    `json:"language"`
+	Question            string    `json:"question"`
+	ConfidenceThreshold float64   `json:"confidence_threshold"`
+	Primary             Result    `json:"primary"`
+	Shadow              Result    `json:"shadow"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// Summary aggregates the stored comparisons.
+type Summary struct {
+	Model                string  `json:"model"`
+	Comparisons          int     `json:"comparisons"`
+	ShadowErrors         int     `json:"shadow_errors"`
+	Dropped              int64   `json:"dropped"` // Skipped because all workers were busy
+	AvgPrimaryConfidence float64 `json:"avg_primary_confidence"`
+	AvgShadowConfidence  float64 `json:"avg_shadow_confidence"`
+	PrimaryFallbackRate  float64 `json:"primary_fallback_rate"`
+	ShadowFallbackRate   float64 `json:"shadow_fallback_rate"`
+	AvgPrimaryTokens     float64 `json:"avg_primary_tokens"`
+	AvgShadowTokens      float64 `json:"avg_shadow_tokens"`
+	AvgPrimaryLatencyMs  float64 `json:"avg_primary_latency_ms"`
+	AvgShadowLatencyMs   float64 `json:"avg_shadow_latency_ms"`
+}
+
+// Config controls how much traffic is shadowed.
+type Config struct {
+	Model          string        // Model the shadow requests are sent to
+	SampleRate     float64       // Fraction of eligible requests to shadow (0-1)
+	MaxConcurrency int           // In-flight shadow calls; excess requests are dropped
+	MaxResults     int           // Comparisons kept in memory (oldest evicted first)
+	Timeout        time.Duration // Per shadow call; independent of the user request
+
+	// Grounding is applied to shadow answers the same way as to primary
+	// answers so confidences stay comparable; nil disables it
+	Grounding       *llm.GroundingScorer
+	GroundingWeight float64
+}
+
+// Runner sends copies of production requests to a candidate model in the
+// background. It never blocks the caller: when all workers are busy the
+// request is dropped rather than queued.
+type Runner struct {
+	client llm.Client
+	cfg    Config
+	sem    chan struct{}
+	wg     sync.WaitGroup
+
+	mu      sync.RWMutex
+	results []Comparison // Ring buffer
+	next    int
+	full    bool
+
+	dropped atomic.Int64
+	sample  func() float64
+	now     func() time.Time
+}
+
+// NewRunner creates a shadow runner for client.
+func NewRunner(client llm.Client, cfg Config) *Runner {
+	if cfg.MaxConcurrency <= 0 {
+		cfg.MaxConcurrency = 1
+	}
+	if cfg.MaxResults <= 0 {
+		cfg.MaxResults = 1000
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	return &Runner{
+		client:  client,
+		cfg:     cfg,
+		sem:     make(chan struct{}, cfg.MaxConcurrency),
+		results: make([]Comparison, cfg.MaxResults),
+		sample:  rand.Float64,
+		now:     time.Now,
+	}
+}
+
+// Model returns the shadow model name.
+func (r *Runner) Model() string {
+	return r.cfg.Model
+}
+
+// Submit shadows req if it is sampled and a worker is free. The comparison
+// must carry the primary result; the request is copied so later changes by
+// the caller do not affect the shadow call. It reports whether the request
+// was submitted.
+func (r *Runner) Submit(cmp Comparison, req *llm.Request) bool {
+	if r.cfg.SampleRate <= 0 || r.sample() >= r.cfg.SampleRate {
+		return false
+	}
+	select {
+	case r.sem <- struct{}{}:
+	default:
+		r.dropped.Add(1)
+		return false
+	}
+
+	shadowReq := *req
+	shadowReq.Model = r.cfg.Model
+	// Tool calls can have side effects and load tenant backends; never replay them
+	shadowReq.Tools = nil
+	shadowReq.ToolExecutor = nil
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer func() { <-r.sem }()
+		r.run(cmp, &shadowReq)
+	}()
+	return true
+}
+
+// Wait blocks until in-flight shadow calls finish.
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
+
+func (r *Runner) run(cmp Comparison, req *llm.Request) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := r.client.GenerateAnswer(ctx, req)
+	cmp.Shadow = Result{
+		Model:     r.cfg.Model,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		cmp.Shadow.Error = err.Error()
+		logger.Error("shadow request failed", map[string]interface{}{
+			"error":        err.Error(),
+			"request_id":   cmp.RequestID,
+			"tenant_id":    cmp.TenantID,
+			"shadow_model": r.cfg.Model,
+		})
+	} else {
+		cmp.Shadow.Answer = resp.Content
+		cmp.Shadow.Confidence = resp.Confidence
+		if r.cfg.Grounding != nil && len(req.KnowledgeBase) > 0 {
+			g := r.cfg.Grounding.Score(ctx, resp.Content, cmp.Question, req.KnowledgeBase)
+			cmp.Shadow.Confidence = llm.BlendConfidence(resp.Confidence, g.Score, r.cfg.GroundingWeight)
+		}
+		cmp.Shadow.TokensUsed = resp.TokensUsed
+		cmp.Shadow.Fallback = cmp.Shadow.Confidence < cmp.ConfidenceThreshold
+		if resp.Model != "" {
+			cmp.Shadow.Model = resp.Model
+		}
+	}
+	cmp.CreatedAt = r.now()
+
+	r.mu.Lock()
+	r.results[r.next] = cmp
+	r.next = (r.next + 1) % len(r.results)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+
+	logger.Info("shadow comparison recorded", map[string]interface{}{
+		"request_id":        cmp.RequestID,
+		"tenant_id":         cmp.TenantID,
+		"shadow_model":      cmp.Shadow.Model,
+		"primary_tokens":    cmp.Primary.TokensUsed,
+		"shadow_tokens":     cmp.Shadow.TokensUsed,
+		"shadow_latency_ms": cmp.Shadow.LatencyMs,
+	})
+}
+
+// List returns up to limit comparisons, newest first, optionally filtered by
+// tenant. A limit <= 0 returns everything stored.
+func (r *Runner) List(tenantID string, limit int) []Comparison {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := []Comparison{}
+	r.each(func(c Comparison) bool {
+		if tenantID != "" && c.TenantID != tenantID {
+			return true
+		}
+		out = append(out, c)
+		return limit <= 0 || len(out) < limit
+	})
+	return out
+}
+
+// Summary aggregates stored comparisons, optionally filtered by tenant.
+// Shadow calls that failed are counted but excluded from the averages.
+func (r *Runner) Summary(tenantID string) Summary {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s := Summary{Model: r.cfg.Model, Dropped: r.dropped.Load()}
+	var (
+		primaryConf, shadowConf         float64
+		primaryFallback, shadowFallback int
+		primaryTokens, shadowTokens     int
+		primaryLatency, shadowLatency   int64
+	)
+	r.each(func(c Comparison) bool {
+		if tenantID != "" && c.TenantID != tenantID {
+			return true
+		}
+		s.Comparisons++
+		if c.Shadow.Error != "" {
+			s.ShadowErrors++
+			return true
+		}
+		primaryConf += c.Primary.Confidence
+		shadowConf += c.Shadow.Confidence
+		primaryTokens += c.Primary.TokensUsed
+		shadowTokens += c.Shadow.TokensUsed
+		primaryLatency += c.Primary.LatencyMs
+		shadowLatency += c.Shadow.LatencyMs
+		if c.Primary.Fallback {
+			primaryFallback++
+		}
+		if c.Shadow.Fallback {
+			shadowFallback++
+		}
+		return true
+	})
+
+	if n := float64(s.Comparisons - s.ShadowErrors); n > 0 {
+		s.AvgPrimaryConfidence = primaryConf / n
+		s.AvgShadowConfidence = shadowConf / n
+		s.PrimaryFallbackRate = float64(primaryFallback) / n
+		s.ShadowFallbackRate = float64(shadowFallback) / n
+		s.AvgPrimaryTokens = float64(primaryTokens) / n
+		s.AvgShadowTokens = float64(shadowTokens) / n
+		s.AvgPrimaryLatencyMs = float64(primaryLatency) / n
+		s.AvgShadowLatencyMs = float64(shadowLatency) / n
+	}
+	return s
+}
+
+// each visits stored comparisons newest first until fn returns false. Caller holds r.mu.
+func (r *Runner) each(fn func(Comparison) bool) {
+	count := r.next
+	if r.full {
+		count = len(r.results)
+	}
+	for i := 1; i <= count; i++ {
+		idx := (r.next - i + len(r.results)) % len(r.results)
+		if !fn(r.results[idx]) {
+			return
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package shadow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/llm"
+)
+
+type stubClient struct {
+	mu      sync.Mutex
+	models  []string
+	block   chan struct{}
+	err     error
+	content string
+}
+
+func (s *stubClient) GenerateAnswer(ctx context.Context, req *llm.Request) (*llm.Response, error) {
+	s.mu.Lock()
+	s.models = append(s.models, req.Model)
+	s.mu.Unlock()
+	if s.block != nil {
+		<-s.block
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &llm.Response{Content: s.content, Confidence: 0.6, TokensUsed: 40, Model: req.Model}, nil
+}
+
+func primary() Comparison {
+	return Comparison{
+		RequestID:           "req-1",
+		TenantID:            "shop-123",
+		Language:            "en",
+		Question:            "Where is my order?",
+		ConfidenceThreshold: 0.7,
+		Primary:             Result{Model: "gpt-3.5-turbo", Answer: "Tomorrow", Confidence: 0.9, TokensUsed: 50, LatencyMs: 100},
+	}
+}
+
+func TestRunner_RecordsSideBySide(t *testing.T) {
+	client := &stubClient{content: "It ships tomorrow"}
+	r := NewRunner(client, Config{Model: "gpt-4o", SampleRate: 1, MaxConcurrency: 2, MaxResults: 10})
+
+	req := &llm.Request{Model: "gpt-3.5-turbo", TenantID: "shop-123"}
+	if !r.Submit(primary(), req) {
+		t.Fatal("expected request to be shadowed")
+	}
+	r.Wait()
+
+	if req.Model != "gpt-3.5-turbo" {
+		t.Errorf("caller's request was modified: model = %s", req.Model)
+	}
+	if len(client.models) != 1 || client.models[0] != "gpt-4o" {
+		t.Fatalf("shadow client called with %v", client.models)
+	}
+
+	got := r.List("shop-123", 0)
+	if len(got) != 1 {
+		t.Fatalf("List() returned %d comparisons", len(got))
+	}
+	if got[0].Primary.Answer != "Tomorrow" || got[0].Shadow.Answer != "It ships tomorrow" {
+		t.Errorf("unexpected comparison: %+v", got[0])
+	}
+	if !got[0].Shadow.Fallback {
+		t.Error("shadow confidence 0.6 below threshold 0.7 should be a fallback")
+	}
+
+	s := r.Summary("")
+	if s.Comparisons != 1 || s.AvgShadowTokens != 40 || s.AvgPrimaryConfidence != 0.9 || s.ShadowFallbackRate != 1 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+	if other := r.List("shop-456", 0); len(other) != 0 {
+		t.Errorf("tenant filter leaked %d comparisons", len(other))
+	}
+}
+
+func TestRunner_NeverBlocks(t *testing.T) {
+	client := &stubClient{block: make(chan struct{})}
+	r := NewRunner(client, Config{Model: "gpt-4o", SampleRate: 1, MaxConcurrency: 1})
+
+	if !r.Submit(primary(), &llm.Request{}) {
+		t.Fatal("first request should be shadowed")
+	}
+
+	done := make(chan bool)
+	go func() { done <- r.Submit(primary(), &llm.Request{}) }()
+	select {
+	case ok := <-done:
+		if ok {
+			t.Error("second request should be dropped while the worker is busy")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Submit blocked")
+	}
+
+	close(client.block)
+	r.Wait()
+	if s := r.Summary(""); s.Dropped != 1 || s.Comparisons != 1 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+}
+
+func TestRunner_SamplingAndErrors(t *testing.T) {
+	client := &stubClient{err: errors.New("boom")}
+	r := NewRunner(client, Config{Model: "gpt-4o", SampleRate: 0.5, MaxResults: 2})
+
+	r.sample = func() float64 { return 0.9 }
+	if r.Submit(primary(), &llm.Request{}) {
+		t.Error("unsampled request was shadowed")
+	}
+
+	r.sample = func() float64 { return 0.1 }
+	for i := 0; i < 3; i++ {
+		r.Submit(primary(), &llm.Request{})
+		r.Wait()
+	}
+
+	if got := r.List("", 0); len(got) != 2 {
+		t.Errorf("ring buffer kept %d comparisons, want 2", len(got))
+	}
+	s := r.Summary("")
+	if s.ShadowErrors != 2 || s.AvgShadowConfidence != 0 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+}
@@ -0,0 +1,437 @@
+package tenant
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+)
+
+var (
+	ErrTenantNotFound      = errors.New("tenant not found")
+	ErrTenantDisabled      = errors.New("tenant disabled")
+	ErrTenantExists        = errors.New("tenant already exists")
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+	ErrInvalidTenant       = errors.New("invalid tenant")
+)
+
+// Tenant fields that can be changed through the admin API. Once changed at
+// runtime, a field is no longer taken from the config file.
+const (
+	FieldEnabled = "enabled"
+	FieldConfig  = "config"
+)
+
+// Tenant is a registered tenant.
+type Tenant struct {
+	ID        string              `json:"id"`
+	Name      string              `json:"name,omitempty"`
+	Enabled   bool                `json:"enabled"`
+	Languages []string            `json:"languages,omitempty"` // Empty means every supported language
+	Config    config.TenantConfig `json:"config"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+
+	// RuntimeFields lists fields set through the admin API; config reloads keep them
+	RuntimeFields []string `json:"runtime_fields,omitempty"`
+	// FromConfig is set for tenants declared in the config file; a reload
+	// that no longer declares them removes them
+	FromConfig bool `json:"from_config,omitempty"`
+}
+
+// RuntimeField reports whether field was last set through the admin API.
+func (t Tenant) RuntimeField(field string) bool {
+	for _, f := range t.RuntimeFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Tenant) markRuntime(field string) {
+	if !t.RuntimeField(field) {
+		t.RuntimeFields = append(t.RuntimeFields, field)
+		sort.Strings(t.RuntimeFields)
+	}
+}
+
+// SupportsLanguage reports whether the tenant serves the given language.
+// It does not check the platform-wide language list.
+func (t Tenant) SupportsLanguage(language string) bool {
+	if len(t.Languages) == 0 {
+		return true
+	}
+	for _, l := range t.Languages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
+
+// Registry is the source of truth for which tenants exist and which languages
+// they may use. It is safe for concurrent use. When a storage path is set,
+// every mutation is persisted as a JSON snapshot.
+type Registry struct {
+	mu        sync.RWMutex
+	tenants   map[string]*Tenant
+	languages map[string]bool
+	defaults  config.TenantSettings
+	path      string
+	now       func() time.Time
+}
+
+// NewRegistry creates an empty registry that accepts the given platform languages.
+func NewRegistry(languages map[string]bool) *Registry {
+	langs := make(map[string]bool, len(languages))
+	for l, ok := range languages {
+		if ok {
+			langs[strings.ToLower(l)] = true
+		}
+	}
+	return &Registry{
+		tenants:   map[string]*Tenant{},
+		languages: langs,
+		defaults:  config.BuiltinTenantSettings(),
+		now:       time.Now,
+	}
+}
+
+// NewRegistryFromConfig seeds a registry with enabled tenants from a static
+// ID set such as config.Tenants.
+func NewRegistryFromConfig(tenants map[string]bool, languages map[string]bool) *Registry {
+	r := NewRegistry(languages)
+	now := r.now()
+	for id, enabled := range tenants {
+		r.tenants[id] = &Tenant{ID: id, Enabled: enabled, CreatedAt: now, UpdatedAt: now}
+	}
+	return r
+}
+
+// LoadFile replaces the registry contents with a snapshot previously written by
+// the registry and persists subsequent mutations to the same path. A missing
+// file is not an error; it is created on the first mutation.
+func (r *Registry) LoadFile(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return r.saveLocked()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read tenant registry: %w", err)
+	}
+
+	var snapshot []Tenant
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse tenant registry: %w", err)
+	}
+	tenants := make(map[string]*Tenant, len(snapshot))
+	for i := range snapshot {
+		t := snapshot[i]
+		if err := r.validateLocked(t); err != nil {
+			return fmt.Errorf("invalid tenant %q: %w", t.ID, err)
+		}
+		tenants[t.ID] = &t
+	}
+	r.tenants = tenants
+	return nil
+}
+
+// Get returns the tenant with the given ID.
+func (r *Registry) Get(id string) (Tenant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.tenants[id]
+	if !ok {
+		return Tenant{}, ErrTenantNotFound
+	}
+	return cloneTenant(t), nil
+}
+
+// List returns every tenant sorted by ID.
+func (r *Registry) List() []Tenant {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Tenant, 0, len(r.tenants))
+	for _, t := range r.tenants {
+		out = append(out, cloneTenant(t))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Add registers a new, enabled tenant.
+func (r *Registry) Add(t Tenant) (Tenant, error) {
+	t.ID = strings.TrimSpace(t.ID)
+	if t.ID == "" {
+		return Tenant{}, fmt.Errorf("%w: id is required", ErrInvalidTenant)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.tenants[t.ID]; exists {
+		return Tenant{}, ErrTenantExists
+	}
+	t.Languages = append([]string(nil), t.Languages...)
+	if err := r.validateLocked(t); err != nil {
+		return Tenant{}, err
+	}
+	if err := t.Config.Validate(); err != nil {
+		return Tenant{}, fmt.Errorf("%w: %v", ErrInvalidTenant, err)
+	}
+
+	now := r.now()
+	t.Enabled = true
+	t.CreatedAt = now
+	t.UpdatedAt = now
+	r.tenants[t.ID] = &t
+	if err := r.saveLocked(); err != nil {
+		delete(r.tenants, t.ID)
+		return Tenant{}, err
+	}
+	return cloneTenant(&t), nil
+}
+
+// SetConfig replaces a tenant's setting overrides.
+func (r *Registry) SetConfig(id string, cfg config.TenantConfig) (Tenant, error) {
+	if err := cfg.Validate(); err != nil {
+		return Tenant{}, fmt.Errorf("%w: %v", ErrInvalidTenant, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.tenants[id]
+	if !ok {
+		return Tenant{}, ErrTenantNotFound
+	}
+	prev := cloneTenant(t)
+	t.Config = cfg
+	t.UpdatedAt = r.now()
+	t.markRuntime(FieldConfig)
+	if err := r.saveLocked(); err != nil {
+		*t = prev
+		return Tenant{}, err
+	}
+	return cloneTenant(t), nil
+}
+
+// Reconfigure atomically swaps the global defaults and upserts tenants
+// declared in configuration. Tenants that came from an earlier configuration
+// but are no longer declared are removed. Tenants added at runtime are left
+// untouched, as are fields of configured tenants last changed through the
+// admin API (see RuntimeFields). Requests already holding resolved settings
+// finish with them.
+func (r *Registry) Reconfigure(defaults config.TenantSettings, specs []config.TenantSpec) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	declared := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		declared[spec.ID] = true
+	}
+	next := make(map[string]*Tenant, len(r.tenants)+len(specs))
+	for id, t := range r.tenants {
+		if t.FromConfig && !declared[id] {
+			continue
+		}
+		clone := cloneTenant(t)
+		next[id] = &clone
+	}
+	for _, spec := range specs {
+		t := Tenant{
+			ID:         spec.ID,
+			Name:       spec.Name,
+			Enabled:    spec.IsEnabled(),
+			Languages:  append([]string(nil), spec.Languages...),
+			Config:     spec.Config,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+			FromConfig: true,
+		}
+		if err := r.validateLocked(t); err != nil {
+			return fmt.Errorf("invalid tenant %q: %w", spec.ID, err)
+		}
+		if existing, ok := next[spec.ID]; ok {
+			t.CreatedAt = existing.CreatedAt
+			t.RuntimeFields = existing.RuntimeFields
+			if existing.RuntimeField(FieldEnabled) {
+				t.Enabled = existing.Enabled
+			}
+			if existing.RuntimeField(FieldConfig) {
+				t.Config = existing.Config
+			}
+		}
+		next[spec.ID] = &t
+	}
+
+	prevTenants, prevDefaults := r.tenants, r.defaults
+	r.tenants, r.defaults = next, defaults
+	if err := r.saveLocked(); err != nil {
+		r.tenants, r.defaults = prevTenants, prevDefaults
+		return err
+	}
+	return nil
+}
+
+// SetDefaults replaces the global settings that tenant overrides are layered on.
+func (r *Registry) SetDefaults(defaults config.TenantSettings) {
+	r.mu.Lock()
+	r.defaults = defaults
+	r.mu.Unlock()
+}
+
+// Settings returns the effective settings for t: global defaults plus its
+// overrides. The brand name defaults to the tenant's name, then its ID.
+func (r *Registry) Settings(t Tenant) config.TenantSettings {
+	r.mu.RLock()
+	defaults := r.defaults
+	r.mu.RUnlock()
+
+	settings := t.Config.Apply(defaults)
+	if settings.BrandName == "" {
+		settings.BrandName = t.Name
+	}
+	if settings.BrandName == "" {
+		settings.BrandName = t.ID
+	}
+	return settings
+}
+
+// SetEnabled enables or disables a tenant. Disabled tenants are rejected at
+// request time but keep their keys and settings.
+func (r *Registry) SetEnabled(id string, enabled bool) (Tenant, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.tenants[id]
+	if !ok {
+		return Tenant{}, ErrTenantNotFound
+	}
+	prev := cloneTenant(t)
+	t.Enabled = enabled
+	t.UpdatedAt = r.now()
+	t.markRuntime(FieldEnabled)
+	if err := r.saveLocked(); err != nil {
+		*t = prev
+		return Tenant{}, err
+	}
+	return cloneTenant(t), nil
+}
+
+// Resolve validates that a request for tenantID in language may be served.
+func (r *Registry) Resolve(tenantID, language string) (Tenant, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.tenants[tenantID]
+	if !ok {
+		return Tenant{}, ErrTenantNotFound
+	}
+	if !t.Enabled {
+		return Tenant{}, ErrTenantDisabled
+	}
+	lang := strings.ToLower(language)
+	if !r.languages[lang] || !t.SupportsLanguage(lang) {
+		return Tenant{}, ErrUnsupportedLanguage
+	}
+	return cloneTenant(t), nil
+}
+
+// Languages returns the languages a tenant may use, sorted, or nil when the
+// tenant is unknown. Disabled tenants are included so callers get the same
+// error from Resolve as they would with an explicit language.
+func (r *Registry) Languages(tenantID string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.tenants[tenantID]
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(r.languages))
+	for l := range r.languages {
+		if t.SupportsLanguage(l) {
+			out = append(out, l)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// SupportedLanguages returns the platform-wide language codes, sorted.
+func (r *Registry) SupportedLanguages() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]string, 0, len(r.languages))
+	for l := range r.languages {
+		out = append(out, l)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (r *Registry) validateLocked(t Tenant) error {
+	for i, l := range t.Languages {
+		l = strings.ToLower(l)
+		if !r.languages[l] {
+			return fmt.Errorf("%w: %s", ErrUnsupportedLanguage, l)
+		}
+		t.Languages[i] = l
+	}
+	return nil
+}
+
+// saveLocked writes the snapshot atomically (temp file + rename).
+func (r *Registry) saveLocked() error {
+	if r.path == "" {
+		return nil
+	}
+	snapshot := make([]Tenant, 0, len(r.tenants))
+	for _, t := range r.tenants {
+		snapshot = append(snapshot, *t)
+	}
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].ID < snapshot[j].ID })
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode tenant registry: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(r.path), ".tenants-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to persist tenant registry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to persist tenant registry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to persist tenant registry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to persist tenant registry: %w", err)
+	}
+	return nil
+}
+
+func cloneTenant(t *Tenant) Tenant {
+	out := *t
+	out.Languages = append([]string(nil), t.Languages...)
+	out.RuntimeFields = append([]string(nil), t.RuntimeFields...)
+	return out
+}
@@ -0,0 +1,220 @@
+package tenant
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+)
+
+func TestRegistry_Resolve(t *testing.T) {
+	r := NewRegistryFromConfig(map[string]bool{"shop-123": true}, map[string]bool{"en": true, "id": true})
+	if _, err := r.Add(Tenant{ID: "shop-en", Languages: []string{"en"}}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := r.Add(Tenant{ID: "shop-off"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := r.SetEnabled("shop-off", false); err != nil {
+		t.Fatalf("SetEnabled() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tenantID string
+		language string
+		wantErr  error
+	}{
+		{name: "seeded tenant", tenantID: "shop-123", language: "id"},
+		{name: "language is case-insensitive", tenantID: "shop-123", language: "EN"},
+		{name: "unknown tenant", tenantID: "shop-999", language: "en", wantErr: ErrTenantNotFound},
+		{name: "disabled tenant", tenantID: "shop-off", language: "en", wantErr: ErrTenantDisabled},
+		{name: "platform unsupported language", tenantID: "shop-123", language: "fr", wantErr: ErrUnsupportedLanguage},
+		{name: "tenant unsupported language", tenantID: "shop-en", language: "id", wantErr: ErrUnsupportedLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Resolve(tt.tenantID, tt.language)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Resolve() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistry_Languages(t *testing.T) {
+	r := NewRegistryFromConfig(map[string]bool{"shop-123": true}, map[string]bool{"en": true, "id": true})
+	if _, err := r.Add(Tenant{ID: "shop-id", Languages: []string{"id"}}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if got := r.Languages("shop-123"); len(got) != 2 || got[0] != "en" || got[1] != "id" {
+		t.Errorf("Languages(shop-123) = %v, want [en id]", got)
+	}
+	if got := r.Languages("shop-id"); len(got) != 1 || got[0] != "id" {
+		t.Errorf("Languages(shop-id) = %v, want [id]", got)
+	}
+	if got := r.Languages("shop-999"); got != nil {
+		t.Errorf("Languages(shop-999) = %v, want nil", got)
+	}
+}
+
+func TestRegistry_AddValidation(t *testing.T) {
+	r := NewRegistry(map[string]bool{"en": true})
+	if _, err := r.Add(Tenant{ID: "shop-1", Languages: []string{"fr"}}); !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("Add(fr) error = %v, want ErrUnsupportedLanguage", err)
+	}
+	if _, err := r.Add(Tenant{ID: " "}); !errors.Is(err, ErrInvalidTenant) {
+		t.Errorf("Add(blank) error = %v, want ErrInvalidTenant", err)
+	}
+	if _, err := r.Add(Tenant{ID: "shop-1"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := r.Add(Tenant{ID: "shop-1"}); !errors.Is(err, ErrTenantExists) {
+		t.Errorf("Add(duplicate) error = %v, want ErrTenantExists", err)
+	}
+}
+
+func TestRegistry_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tenants.json")
+	languages := map[string]bool{"en": true}
+
+	r := NewRegistryFromConfig(map[string]bool{"shop-123": true}, languages)
+	if err := r.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if _, err := r.Add(Tenant{ID: "shop-new", Name: "New Shop"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := r.SetEnabled("shop-123", false); err != nil {
+		t.Fatalf("SetEnabled() error = %v", err)
+	}
+
+	reloaded := NewRegistry(languages)
+	if err := reloaded.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if got, err := reloaded.Get("shop-new"); err != nil || got.Name != "New Shop" {
+		t.Errorf("Get(shop-new) = %+v, %v", got, err)
+	}
+	if _, err := reloaded.Resolve("shop-123", "en"); !errors.Is(err, ErrTenantDisabled) {
+		t.Errorf("Resolve(shop-123) error = %v, want ErrTenantDisabled", err)
+	}
+}
+
+func TestRegistry_Reconfigure(t *testing.T) {
+	r := NewRegistry(map[string]bool{"en": true})
+	if _, err := r.Add(Tenant{ID: "runtime-shop"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	defaults := config.BuiltinTenantSettings()
+	defaults.RateLimitPerSec = 5
+	model := "gpt-4o"
+	disabled := false
+	specs := []config.TenantSpec{
+		{ID: "enterprise", Config: config.TenantConfig{Model: model}},
+		{ID: "churned", Enabled: &disabled},
+	}
+	if err := r.Reconfigure(defaults, specs); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+
+	ent, err := r.Resolve("enterprise", "en")
+	if err != nil {
+		t.Fatalf("Resolve(enterprise) error = %v", err)
+	}
+	if got := r.Settings(ent); got.Model != "gpt-4o" || got.RateLimitPerSec != 5 {
+		t.Errorf("Settings(enterprise) = %+v", got)
+	}
+	if _, err := r.Resolve("churned", "en"); !errors.Is(err, ErrTenantDisabled) {
+		t.Errorf("Resolve(churned) error = %v, want ErrTenantDisabled", err)
+	}
+	if _, err := r.Resolve("runtime-shop", "en"); err != nil {
+		t.Errorf("Resolve(runtime-shop) error = %v, want runtime tenant kept", err)
+	}
+
+	bad := []config.TenantSpec{{ID: "enterprise", Languages: []string{"fr"}}}
+	if err := r.Reconfigure(config.BuiltinTenantSettings(), bad); err == nil {
+		t.Fatal("Reconfigure(invalid) error = nil, want error")
+	}
+	if got := r.Settings(ent); got.RateLimitPerSec != 5 {
+		t.Errorf("Settings() after rejected reload = %+v, want previous defaults kept", got)
+	}
+}
+
+func TestRegistry_ReconfigureRemovesUndeclaredTenants(t *testing.T) {
+	r := NewRegistry(map[string]bool{"en": true})
+	if _, err := r.Add(Tenant{ID: "runtime-shop"}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	specs := []config.TenantSpec{{ID: "enterprise"}, {ID: "churned"}}
+	if err := r.Reconfigure(config.BuiltinTenantSettings(), specs); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+
+	// churned is deleted from the config file
+	if err := r.Reconfigure(config.BuiltinTenantSettings(), specs[:1]); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	if _, err := r.Resolve("churned", "en"); !errors.Is(err, ErrTenantNotFound) {
+		t.Errorf("Resolve(churned) error = %v, want ErrTenantNotFound", err)
+	}
+	for _, id := range []string{"enterprise", "runtime-shop"} {
+		if _, err := r.Resolve(id, "en"); err != nil {
+			t.Errorf("Resolve(%s) error = %v, want tenant kept", id, err)
+		}
+	}
+}
+
+func TestRegistry_ReconfigureKeepsRuntimeChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tenants.json")
+	languages := map[string]bool{"en": true}
+	specs := []config.TenantSpec{
+		{ID: "shop-123", Name: "Shop", Config: config.TenantConfig{Model: "gpt-4o"}},
+	}
+
+	r := NewRegistry(languages)
+	if err := r.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if err := r.Reconfigure(config.BuiltinTenantSettings(), specs); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	if _, err := r.SetEnabled("shop-123", false); err != nil {
+		t.Fatalf("SetEnabled() error = %v", err)
+	}
+
+	// Simulate a restart: load the snapshot, then apply the same config file
+	// with a changed name and model
+	specs[0].Name = "Renamed Shop"
+	specs[0].Config.Model = "gpt-4o-mini"
+	restarted := NewRegistry(languages)
+	if err := restarted.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	if err := restarted.Reconfigure(config.BuiltinTenantSettings(), specs); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	got, err := restarted.Get("shop-123")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Enabled {
+		t.Error("Enabled = true, want runtime disable kept across reload")
+	}
+	if got.Name != "Renamed Shop" || got.Config.Model != "gpt-4o-mini" {
+		t.Errorf("Get() = %+v, want file values for fields not changed at runtime", got)
+	}
+
+	if _, err := restarted.SetConfig("shop-123", config.TenantConfig{Model: "gpt-4o"}); err != nil {
+		t.Fatalf("SetConfig() error = %v", err)
+	}
+	if err := restarted.Reconfigure(config.BuiltinTenantSettings(), specs); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	if got, _ := restarted.Get("shop-123"); got.Config.Model != "gpt-4o" {
+		t.Errorf("Config.Model = %q, want runtime config kept", got.Config.Model)
+	}
+}
@@ -0,0 +1,348 @@
+// Package tools runs the HTTP tools tenants expose to the model, such as
+// order status or refund eligibility lookups.
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/auth"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/llm"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/logger"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/netguard"
+)
+
+// MaxResultBytes caps how much of a tool response is passed to the model.
+const MaxResultBytes = 8 << 10
+
+// CustomerIDHeader carries the authenticated end customer on every tool call,
+// so backends can scope lookups to that customer's own data. It is absent for
+// callers without a customer, such as tenant API keys, and tool headers
+// cannot set it.
+const CustomerIDHeader = "X-Customer-ID"
+
+var (
+	ErrUnknownTool      = errors.New("unknown tool")
+	ErrInvalidArguments = errors.New("invalid tool arguments")
+	ErrHostNotAllowed   = errors.New("tool host not allowed")
+	ErrToolFailed       = errors.New("tool call failed")
+)
+
+// Registry turns tenant tool configuration into model tool definitions and
+// executors.
+type Registry struct {
+	client         *http.Client
+	defaultTimeout time.Duration
+	allowedHosts   map[string]bool
+}
+
+// NewRegistry creates a registry. Tools without their own timeout use
+// defaultTimeout; a non-empty allowedHosts restricts which hosts tools may
+// call. Without it, tools may call any host except internal addresses
+// (loopback, private, link-local), so internal services must be allowlisted.
+func NewRegistry(defaultTimeout time.Duration, allowedHosts []string) *Registry {
+	r := &Registry{
+		client: &http.Client{
+			// Tool endpoints are configured per tenant; redirects could lead anywhere
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		defaultTimeout: defaultTimeout,
+	}
+	if len(allowedHosts) > 0 {
+		r.allowedHosts = make(map[string]bool, len(allowedHosts))
+		for _, h := range allowedHosts {
+			r.allowedHosts[strings.ToLower(h)] = true
+		}
+	} else {
+		r.client.Transport = netguard.Transport()
+	}
+	return r
+}
+
+// Definitions describes the tenant's tools to the model.
+func (r *Registry) Definitions(tools []config.ToolConfig) []llm.Tool {
+	defs := make([]llm.Tool, len(tools))
+	for i, t := range tools {
+		defs[i] = llm.Tool{Name: t.Name, Description: t.Description, Parameters: schema(t)}
+	}
+	return defs
+}
+
+// Executor runs calls against one tenant's tools for one request made by
+// identity, which may be nil.
+func (r *Registry) Executor(tenantID, requestID string, identity *auth.Identity, tools []config.ToolConfig) *Executor {
+	byName := make(map[string]config.ToolConfig, len(tools))
+	for _, t := range tools {
+		byName[t.Name] = t
+	}
+	e := &Executor{registry: r, tenantID: tenantID, requestID: requestID, tools: byName}
+	if identity != nil {
+		e.customerID = identity.CustomerID
+	}
+	return e
+}
+
+// Executor implements llm.ToolExecutor for a tenant. Restore and Redact, when
+// set, map PII placeholders in arguments back to real values before the call
+// and redact personal data in results before they reach the model.
+type Executor struct {
+	registry   *Registry
+	tenantID   string
+	requestID  string
+	customerID string // Sent as CustomerIDHeader; empty without a customer
+	tools      map[string]config.ToolConfig
+
+	Restore func(string) string
+	Redact  func(string) string
+}
+
+// Execute validates the arguments, calls the endpoint and returns its body.
+func (e *Executor) Execute(ctx context.Context, call llm.ToolCall) (string, error) {
+	start := time.Now()
+	tool, ok := e.tools[call.Name]
+	if !ok {
+		e.audit(call.Name, nil, 0, start, ErrUnknownTool)
+		return "", fmt.Errorf("%w: %s", ErrUnknownTool, call.Name)
+	}
+	// Patterns are checked against the real values, not PII placeholders
+	args, err := validateArguments(tool, call.Arguments, e.Restore)
+	if err != nil {
+		e.audit(call.Name, nil, 0, start, err)
+		return "", err
+	}
+
+	status, body, err := e.registry.do(ctx, tool, args, e.customerID)
+	e.audit(call.Name, args, status, start, err)
+	if err != nil {
+		return "", err
+	}
+	if e.Redact != nil {
+		body = e.Redact(body)
+	}
+	return body, nil
+}
+
+// audit records the call without argument values, which may be personal data.
+func (e *Executor) audit(name string, args map[string]interface{}, status int, start time.Time, err error) {
+	names := make([]string, 0, len(args))
+	for k := range args {
+		names = append(names, k)
+	}
+	fields := map[string]interface{}{
+		"request_id":  e.requestID,
+		"tenant_id":   e.tenantID,
+		"tool":        name,
+		"arguments":   names,
+		"status":      status,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	logger.Audit("tool called", fields)
+}
+
+// do sends the HTTP request for a validated call.
+func (r *Registry) do(ctx context.Context, tool config.ToolConfig, args map[string]interface{}, customerID string) (int, string, error) {
+	timeout := r.defaultTimeout
+	if tool.TimeoutMs > 0 {
+		timeout = time.Duration(tool.TimeoutMs) * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Path placeholders are filled first; the rest go in the query or body
+	remaining := make(map[string]interface{}, len(args))
+	rawURL := tool.URL
+	for k, v := range args {
+		placeholder := "{" + k + "}"
+		if strings.Contains(rawURL, placeholder) {
+			rawURL = strings.ReplaceAll(rawURL, placeholder, url.PathEscape(fmt.Sprint(v)))
+		} else {
+			remaining[k] = v
+		}
+	}
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: %v", ErrToolFailed, err)
+	}
+	if r.allowedHosts != nil && !r.allowedHosts[strings.ToLower(target.Hostname())] {
+		return 0, "", fmt.Errorf("%w: %s", ErrHostNotAllowed, target.Hostname())
+	}
+
+	var body io.Reader
+	method := tool.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	if method == http.MethodPost {
+		data, err := json.Marshal(remaining)
+		if err != nil {
+			return 0, "", fmt.Errorf("%w: %v", ErrToolFailed, err)
+		}
+		body = bytes.NewReader(data)
+	} else {
+		query := target.Query()
+		for k, v := range remaining {
+			query.Set(k, fmt.Sprint(v))
+		}
+		target.RawQuery = query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: %v", ErrToolFailed, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range tool.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Del(CustomerIDHeader)
+	if customerID != "" {
+		req.Header.Set(CustomerIDHeader, customerID)
+	}
+
+	resp, err := r.client.Do(req)
+	if errors.Is(err, netguard.ErrBlockedAddress) {
+		return 0, "", fmt.Errorf("%w: %s", ErrHostNotAllowed, target.Hostname())
+	}
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: %v", ErrToolFailed, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxResultBytes))
+	if err != nil {
+		return resp.StatusCode, "", fmt.Errorf("%w: %v", ErrToolFailed, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, "", fmt.Errorf("%w: status %d", ErrToolFailed, resp.StatusCode)
+	}
+	return resp.StatusCode, string(data), nil
+}
+
+// ValidateArguments decodes the model's JSON arguments and checks them against
+// the tool's parameters: required ones present, no unknown ones, types and
+// patterns respected. Integers are returned as int64.
+func ValidateArguments(tool config.ToolConfig, raw string) (map[string]interface{}, error) {
+	return validateArguments(tool, raw, nil)
+}
+
+// validateArguments is ValidateArguments with string values passed through
+// restore, when set, before they are checked.
+func validateArguments(tool config.ToolConfig, raw string, restore func(string) string) (map[string]interface{}, error) {
+	if strings.TrimSpace(raw) == "" {
+		raw = "{}"
+	}
+	var args map[string]interface{}
+	decoder := json.NewDecoder(strings.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&args); err != nil {
+		return nil, fmt.Errorf("%w: not a JSON object", ErrInvalidArguments)
+	}
+
+	params := make(map[string]config.ToolParam, len(tool.Parameters))
+	for _, p := range tool.Parameters {
+		params[p.Name] = p
+		if _, ok := args[p.Name]; p.Required && !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalidArguments, p.Name)
+		}
+	}
+
+	out := make(map[string]interface{}, len(args))
+	for name, v := range args {
+		p, ok := params[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown argument %s", ErrInvalidArguments, name)
+		}
+		if s, ok := v.(string); ok && restore != nil {
+			v = restore(s)
+		}
+		value, err := convert(p, v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidArguments, name, err)
+		}
+		out[name] = value
+	}
+	return out, nil
+}
+
+func convert(p config.ToolParam, v interface{}) (interface{}, error) {
+	switch p.Type {
+	case "string":
+		s, ok := v.(string)
+		if !ok {
+			return nil, errors.New("must be a string")
+		}
+		if p.Pattern != "" {
+			re, err := regexp.Compile("^(?:" + p.Pattern + ")$")
+			if err != nil || !re.MatchString(s) {
+				return nil, errors.New("does not match the expected format")
+			}
+		}
+		return s, nil
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, errors.New("must be an integer")
+		}
+		i, err := strconv.ParseInt(n.String(), 10, 64)
+		if err != nil {
+			return nil, errors.New("must be an integer")
+		}
+		return i, nil
+	case "number":
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, errors.New("must be a number")
+		}
+		return n.Float64()
+	case "boolean":
+		b, ok := v.(bool)
+		if !ok {
+			return nil, errors.New("must be a boolean")
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", p.Type)
+	}
+}
+
+// schema builds the JSON schema of a tool's arguments.
+func schema(tool config.ToolConfig) json.RawMessage {
+	properties := make(map[string]interface{}, len(tool.Parameters))
+	required := []string{}
+	for _, p := range tool.Parameters {
+		prop := map[string]interface{}{"type": p.Type}
+		if p.Description != "" {
+			prop["description"] = p.Description
+		}
+		if p.Pattern != "" {
+			prop["pattern"] = "^(?:" + p.Pattern + ")$"
+		}
+		properties[p.Name] = prop
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+	data, _ := json.Marshal(map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	})
+	return data
+}
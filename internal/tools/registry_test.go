@@ -0,0 +1,227 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/auth"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/config"
+	"github.com/RyoKusnadi/tier1-support-ai/internal/llm"
+)
+
+func orderStatusTool(url string) config.ToolConfig {
+	return config.ToolConfig{
+		Name:        "order_status",
+		Description: "Look up the shipping status of an order",
+		URL:         url + "/orders/{order_id}",
+		Headers:     map[string]string{"X-Api-Key": "secret"},
+		Parameters: []config.ToolParam{
+			{Name: "order_id", Type: "string", Required: true, Pattern: `[0-9]{3,10}`},
+			{Name: "email", Type: "string"},
+		},
+	}
+}
+
+// testHosts allowlists the httptest backends, which internal-address
+// blocking would otherwise refuse.
+var testHosts = []string{"127.0.0.1"}
+
+func TestExecutor_GET(t *testing.T) {
+	var gotPath, gotQuery, gotKey string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery, gotKey = r.URL.Path, r.URL.Query().Get("email"), r.Header.Get("X-Api-Key")
+		w.Write([]byte(`{"status":"shipped","contact":"jane@example.com"}`))
+	}))
+	defer backend.Close()
+
+	registry := NewRegistry(time.Second, testHosts)
+	executor := registry.Executor("shop-123", "req-1", nil, []config.ToolConfig{orderStatusTool(backend.URL)})
+	executor.Restore = func(s string) string { return strings.ReplaceAll(s, "[EMAIL_1]", "jane@example.com") }
+	executor.Redact = func(s string) string { return strings.ReplaceAll(s, "jane@example.com", "[EMAIL_1]") }
+
+	result, err := executor.Execute(context.Background(), llm.ToolCall{
+		Name:      "order_status",
+		Arguments: `{"order_id":"123","email":"[EMAIL_1]"}`,
+	})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if gotPath != "/orders/123" || gotQuery != "jane@example.com" || gotKey != "secret" {
+		t.Errorf("backend saw path=%q email=%q key=%q", gotPath, gotQuery, gotKey)
+	}
+	if result != `{"status":"shipped","contact":"[EMAIL_1]"}` {
+		t.Errorf("Execute() = %s, want redacted body", result)
+	}
+}
+
+func TestExecutor_SendsCustomerID(t *testing.T) {
+	var got []string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(CustomerIDHeader))
+		w.Write([]byte(`{"status":"shipped"}`))
+	}))
+	defer backend.Close()
+
+	// A tool header cannot impersonate a customer
+	tool := orderStatusTool(backend.URL)
+	tool.Headers[CustomerIDHeader] = "cust-spoofed"
+	registry := NewRegistry(time.Second, testHosts)
+	call := llm.ToolCall{Name: "order_status", Arguments: `{"order_id":"123"}`}
+
+	customer := &auth.Identity{Method: "jwt", TenantID: "shop-123", CustomerID: "cust-42"}
+	if _, err := registry.Executor("shop-123", "req-1", customer, []config.ToolConfig{tool}).Execute(context.Background(), call); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	apiKey := &auth.Identity{Method: "api_key", KeyID: "key-1", TenantID: "shop-123"}
+	if _, err := registry.Executor("shop-123", "req-2", apiKey, []config.ToolConfig{tool}).Execute(context.Background(), call); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != "cust-42" || got[1] != "" {
+		t.Errorf("backend saw %s = %q, want [cust-42, none]", CustomerIDHeader, got)
+	}
+}
+
+func TestExecutor_POST(t *testing.T) {
+	var got map[string]interface{}
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"eligible":true}`))
+	}))
+	defer backend.Close()
+
+	tool := config.ToolConfig{
+		Name:   "refund_eligibility",
+		Method: "POST",
+		URL:    backend.URL + "/refunds/check",
+		Parameters: []config.ToolParam{
+			{Name: "order_id", Type: "integer", Required: true},
+			{Name: "opened", Type: "boolean"},
+		},
+	}
+	executor := NewRegistry(time.Second, testHosts).Executor("shop-123", "req-1", nil, []config.ToolConfig{tool})
+	if _, err := executor.Execute(context.Background(), llm.ToolCall{Name: tool.Name, Arguments: `{"order_id":123,"opened":false}`}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got["order_id"] != float64(123) || got["opened"] != false {
+		t.Errorf("backend body = %v", got)
+	}
+}
+
+func TestExecutor_Errors(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orders/404":
+			w.WriteHeader(http.StatusNotFound)
+		case "/orders/999":
+			time.Sleep(200 * time.Millisecond)
+		}
+	}))
+	defer backend.Close()
+
+	slow := orderStatusTool(backend.URL)
+	slow.TimeoutMs = 50
+	executor := NewRegistry(time.Second, testHosts).Executor("shop-123", "req-1", nil, []config.ToolConfig{slow})
+
+	tests := []struct {
+		name string
+		call llm.ToolCall
+		want error
+	}{
+		{name: "unknown tool", call: llm.ToolCall{Name: "delete_account", Arguments: `{}`}, want: ErrUnknownTool},
+		{name: "missing argument", call: llm.ToolCall{Name: "order_status", Arguments: `{}`}, want: ErrInvalidArguments},
+		{name: "bad pattern", call: llm.ToolCall{Name: "order_status", Arguments: `{"order_id":"12/../admin"}`}, want: ErrInvalidArguments},
+		{name: "wrong type", call: llm.ToolCall{Name: "order_status", Arguments: `{"order_id":123}`}, want: ErrInvalidArguments},
+		{name: "unknown argument", call: llm.ToolCall{Name: "order_status", Arguments: `{"order_id":"123","admin":true}`}, want: ErrInvalidArguments},
+		{name: "not found", call: llm.ToolCall{Name: "order_status", Arguments: `{"order_id":"404"}`}, want: ErrToolFailed},
+		{name: "timeout", call: llm.ToolCall{Name: "order_status", Arguments: `{"order_id":"999"}`}, want: ErrToolFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := executor.Execute(context.Background(), tt.call); !errors.Is(err, tt.want) {
+				t.Errorf("Execute() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecutor_AllowedHosts(t *testing.T) {
+	executor := NewRegistry(time.Second, []string{"orders.internal"}).
+		Executor("shop-123", "req-1", nil, []config.ToolConfig{orderStatusTool("http://127.0.0.1:1")})
+	_, err := executor.Execute(context.Background(), llm.ToolCall{Name: "order_status", Arguments: `{"order_id":"123"}`})
+	if !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("Execute() error = %v, want ErrHostNotAllowed", err)
+	}
+}
+
+func TestExecutor_BlocksInternalAddresses(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("tool call reached a loopback backend")
+	}))
+	defer backend.Close()
+
+	executor := NewRegistry(time.Second, nil).Executor("shop-123", "req-1", nil, []config.ToolConfig{orderStatusTool(backend.URL)})
+	_, err := executor.Execute(context.Background(), llm.ToolCall{Name: "order_status", Arguments: `{"order_id":"123"}`})
+	if !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("Execute() error = %v, want ErrHostNotAllowed", err)
+	}
+}
+
+func TestExecutor_ValidatesRestoredArguments(t *testing.T) {
+	var gotEmail string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEmail = r.URL.Query().Get("email")
+		w.Write([]byte(`{"status":"shipped"}`))
+	}))
+	defer backend.Close()
+
+	tool := orderStatusTool(backend.URL)
+	tool.Parameters[1].Pattern = `[^@\s]+@[^@\s]+`
+	executor := NewRegistry(time.Second, testHosts).Executor("shop-123", "req-1", nil, []config.ToolConfig{tool})
+	executor.Restore = func(s string) string {
+		return strings.NewReplacer("[EMAIL_1]", "jane@example.com", "[PHONE_1]", "+62 812 3456").Replace(s)
+	}
+
+	if _, err := executor.Execute(context.Background(), llm.ToolCall{
+		Name:      "order_status",
+		Arguments: `{"order_id":"123","email":"[EMAIL_1]"}`,
+	}); err != nil {
+		t.Fatalf("Execute() error = %v, want placeholder restored before the pattern check", err)
+	}
+	if gotEmail != "jane@example.com" {
+		t.Errorf("backend email = %q, want restored value", gotEmail)
+	}
+
+	_, err := executor.Execute(context.Background(), llm.ToolCall{
+		Name:      "order_status",
+		Arguments: `{"order_id":"123","email":"[PHONE_1]"}`,
+	})
+	if !errors.Is(err, ErrInvalidArguments) {
+		t.Errorf("Execute() error = %v, want restored value checked against the pattern", err)
+	}
+}
+
+func TestRegistry_Definitions(t *testing.T) {
+	defs := NewRegistry(time.Second, nil).Definitions([]config.ToolConfig{orderStatusTool("https://shop.example")})
+	if len(defs) != 1 || defs[0].Name != "order_status" {
+		t.Fatalf("Definitions() = %+v", defs)
+	}
+	var schema struct {
+		Properties map[string]map[string]string `json:"properties"`
+		Required   []string                     `json:"required"`
+	}
+	if err := json.Unmarshal(defs[0].Parameters, &schema); err != nil {
+		t.Fatalf("schema is not JSON: %v", err)
+	}
+	if schema.Properties["order_id"]["type"] != "string" || len(schema.Required) != 1 || schema.Required[0] != "order_id" {
+		t.Errorf("schema = %+v", schema)
+	}
+}
@@ -0,0 +1,50 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/RyoKusnadi/tier1-support-ai/internal/llm"
+)
+
+type stubClient struct {
+	content string
+	reqs    []*llm.Request
+}
+
+func (s *stubClient) GenerateAnswer(_ context.Context, req *llm.Request) (*llm.Response, error) {
+	s.reqs = append(s.reqs, req)
+	return &llm.Response{Content: s.content, TokensUsed: 42}, nil
+}
+
+func TestLLMTranslator(t *testing.T) {
+	client := &stubClient{content: "  Where is my order [ORDER_1]?\n"}
+	got, err := NewLLMTranslator(client, "gpt-4o-mini").Translate(context.Background(), "Di mana pesanan saya [ORDER_1]?", "ms", "en")
+	if err != nil {
+		t.Fatalf("Translate() error = %v", err)
+	}
+	if got.Text != "Where is my order [ORDER_1]?" || got.TokensUsed != 42 {
+		t.Errorf("Translate() = %+v", got)
+	}
+	req := client.reqs[0]
+	if !req.Raw || req.Model != "gpt-4o-mini" || !strings.Contains(req.Messages[0].Content, "from Malay to English") {
+		t.Errorf("request = %+v", req)
+	}
+}
+
+func TestLLMTranslator_SameLanguage(t *testing.T) {
+	client := &stubClient{}
+	got, err := NewLLMTranslator(client, "").Translate(context.Background(), "Hello", "en", "en")
+	if err != nil || got.Text != "Hello" || len(client.reqs) != 0 {
+		t.Errorf("Translate() = %+v, %v, calls = %d, want passthrough", got, err, len(client.reqs))
+	}
+}
+
+func TestLLMTranslator_Empty(t *testing.T) {
+	_, err := NewLLMTranslator(&stubClient{content: " "}, "").Translate(context.Background(), "Xin chào", "vi", "en")
+	if !errors.Is(err, ErrEmptyTranslation) {
+		t.Errorf("Translate() error = %v, want ErrEmptyTranslation", err)
+	}
+}